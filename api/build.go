@@ -9,6 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	api "github.com/go-vela/server/api/types"
+	"github.com/go-vela/server/compiler/types/pipeline"
 	"github.com/go-vela/worker/router/middleware/executor"
 )
 
@@ -97,3 +98,128 @@ func CancelBuild(c *gin.Context) {
 
 	c.JSON(http.StatusOK, build)
 }
+
+// BuildRequest represents the request body
+// for running a build in standalone mode.
+//
+// swagger:model BuildRequest
+type BuildRequest struct {
+	Build    *api.Build      `json:"build"`
+	Pipeline *pipeline.Build `json:"pipeline"`
+}
+
+// swagger:operation POST /api/v1/builds build RunBuild
+//
+// Run a compiled pipeline on the worker in standalone mode
+//
+// ---
+// produces:
+// - application/json
+// parameters:
+// - in: body
+//   name: body
+//   description: The build metadata and compiled pipeline to run
+//   required: true
+//   schema:
+//     "$ref": "#/definitions/BuildRequest"
+// security:
+//   - ApiKeyAuth: []
+// responses:
+//   '201':
+//     description: Successfully started the build
+//     type: json
+//     schema:
+//       "$ref": "#/definitions/Build"
+//   '400':
+//     description: Invalid build provided
+//     schema:
+//       "$ref": "#/definitions/Error"
+//   '500':
+//     description: Unable to run the build
+//     schema:
+//       "$ref": "#/definitions/Error"
+//   '503':
+//     description: No executor available to run the build
+//     schema:
+//       "$ref": "#/definitions/Error"
+
+// RunBuild represents the API handler to run a compiled
+// pipeline on an executor when the worker runs without
+// a server. The results are available from the executor
+// endpoints while the build runs.
+func RunBuild(c *gin.Context) {
+	// extract the builds channel that was packed into gin context
+	v, ok := c.Get("builds")
+	if !ok {
+		msg := "no builds channel in the context"
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, api.Error{Message: &msg})
+
+		return
+	}
+
+	// make sure we configured the channel properly
+	bChan, ok := v.(chan *BuildRequest)
+	if !ok || bChan == nil {
+		msg := "worker is not running in standalone mode"
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, api.Error{Message: &msg})
+
+		return
+	}
+
+	input := new(BuildRequest)
+
+	err := c.ShouldBindJSON(input)
+	if err != nil {
+		msg := fmt.Errorf("unable to decode JSON for build: %w", err).Error()
+
+		c.AbortWithStatusJSON(http.StatusBadRequest, api.Error{Message: &msg})
+
+		return
+	}
+
+	err = input.validate()
+	if err != nil {
+		msg := err.Error()
+
+		c.AbortWithStatusJSON(http.StatusBadRequest, api.Error{Message: &msg})
+
+		return
+	}
+
+	// hand the build to an idle executor
+	select {
+	case bChan <- input:
+	default:
+		msg := "no executor available to run the build"
+
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, api.Error{Message: &msg})
+
+		return
+	}
+
+	c.JSON(http.StatusCreated, input.Build)
+}
+
+// validate is a helper function to verify the
+// build request has the fields to run the build.
+func (r *BuildRequest) validate() error {
+	if r.Pipeline == nil {
+		return fmt.Errorf("no pipeline provided")
+	}
+
+	if r.Build == nil {
+		return fmt.Errorf("no build provided")
+	}
+
+	if len(r.Build.GetRepo().GetOrg()) == 0 || len(r.Build.GetRepo().GetName()) == 0 {
+		return fmt.Errorf("no repo org and name provided for build")
+	}
+
+	if r.Build.GetNumber() <= 0 {
+		return fmt.Errorf("invalid build number provided: %d", r.Build.GetNumber())
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-vela/worker/router/middleware/perm"
+)
+
+func TestAPI_RunBuild(t *testing.T) {
+	// setup types
+	body := `{"build":{"number":1,"repo":{"org":"github","name":"octocat"}},"pipeline":{"id":"github_octocat_1"}}`
+
+	// the executors are busy running builds or stopped while draining
+	busy := make(chan *BuildRequest, 1)
+	busy <- new(BuildRequest)
+
+	// setup tests
+	tests := []struct {
+		name   string
+		body   string
+		token  string
+		builds any
+		want   int
+	}{
+		{
+			name:   "submitted",
+			body:   body,
+			token:  "superSecret",
+			builds: make(chan *BuildRequest, 1),
+			want:   http.StatusCreated,
+		},
+		{
+			name:   "busy or draining",
+			body:   body,
+			token:  "superSecret",
+			builds: busy,
+			want:   http.StatusServiceUnavailable,
+		},
+		{
+			name:   "wrong secret",
+			body:   body,
+			token:  "foo",
+			builds: make(chan *BuildRequest, 1),
+			want:   http.StatusUnauthorized,
+		},
+		{
+			name:   "bad payload",
+			body:   `{"build":"foo"}`,
+			token:  "superSecret",
+			builds: make(chan *BuildRequest, 1),
+			want:   http.StatusBadRequest,
+		},
+		{
+			name:   "no pipeline",
+			body:   `{"build":{"number":1,"repo":{"org":"github","name":"octocat"}}}`,
+			token:  "superSecret",
+			builds: make(chan *BuildRequest, 1),
+			want:   http.StatusBadRequest,
+		},
+		{
+			name:   "no build number",
+			body:   `{"build":{"repo":{"org":"github","name":"octocat"}},"pipeline":{"id":"github_octocat_1"}}`,
+			token:  "superSecret",
+			builds: make(chan *BuildRequest, 1),
+			want:   http.StatusBadRequest,
+		},
+		{
+			name:  "no builds channel",
+			body:  body,
+			token: "superSecret",
+			want:  http.StatusInternalServerError,
+		},
+		{
+			name:   "not standalone",
+			body:   body,
+			token:  "superSecret",
+			builds: (chan *BuildRequest)(nil),
+			want:   http.StatusInternalServerError,
+		},
+	}
+
+	// setup context
+	gin.SetMode(gin.TestMode)
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := httptest.NewRecorder()
+			context, engine := gin.CreateTestContext(resp)
+			context.Request, _ = http.NewRequestWithContext(t.Context(), http.MethodPost, "/api/v1/builds", strings.NewReader(test.body))
+			context.Request.Header.Set("Authorization", "Bearer "+test.token)
+			context.Request.Header.Set("Content-Type", "application/json")
+
+			// setup mock server
+			engine.Use(func(c *gin.Context) {
+				c.Set("standalone-secret", "superSecret")
+
+				if test.builds != nil {
+					c.Set("builds", test.builds)
+				}
+
+				c.Next()
+			})
+			engine.POST("/api/v1/builds", perm.MustServer(), RunBuild)
+
+			engine.ServeHTTP(context.Writer, context.Request)
+
+			if resp.Code != test.want {
+				t.Errorf("RunBuild returned %v, want %v", resp.Code, test.want)
+			}
+
+			bChan, ok := test.builds.(chan *BuildRequest)
+			if !ok || bChan == nil || bChan == busy {
+				return // continue to next test
+			}
+
+			if test.want != http.StatusCreated {
+				if len(bChan) > 0 {
+					t.Errorf("RunBuild submitted %v, want nothing", <-bChan)
+				}
+
+				return // continue to next test
+			}
+
+			if len(bChan) == 0 {
+				t.Errorf("RunBuild submitted nothing, want build 1")
+
+				return // continue to next test
+			}
+
+			req := <-bChan
+
+			if req.Build.GetNumber() != 1 || req.Pipeline.ID != "github_octocat_1" {
+				t.Errorf("RunBuild submitted %v, want build 1 for pipeline github_octocat_1", req)
+			}
+		})
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/go-vela/worker/internal/health"
 )
 
 // swagger:operation GET /health system Health
@@ -26,3 +28,87 @@ import (
 func Health(c *gin.Context) {
 	c.JSON(http.StatusOK, "ok")
 }
+
+// swagger:operation GET /live system Live
+//
+// Check if the worker is alive
+//
+// ---
+// produces:
+// - application/json
+// parameters:
+// responses:
+//   '200':
+//     description: Worker is alive
+//     type: json
+//   '503':
+//     description: Worker is not alive and should be restarted
+//     type: json
+
+// Live reports the liveness of the worker by checking
+// the operator and executors are still running.
+func Live(c *gin.Context) {
+	h, ok := retrieveHealth(c)
+	if !ok {
+		return
+	}
+
+	report(c, h.Live(c.Request.Context()))
+}
+
+// swagger:operation GET /ready system Ready
+//
+// Check if the worker is ready to execute builds
+//
+// ---
+// produces:
+// - application/json
+// parameters:
+// responses:
+//   '200':
+//     description: Worker is ready to execute builds
+//     type: json
+//   '503':
+//     description: Worker is not ready to execute builds
+//     type: json
+
+// Ready reports the readiness of the worker by checking it is
+// not draining along with the runtime, queue and server check-in.
+// The usage of the executors is reported without affecting it.
+func Ready(c *gin.Context) {
+	h, ok := retrieveHealth(c)
+	if !ok {
+		return
+	}
+
+	report(c, h.Ready(c.Request.Context()))
+}
+
+// retrieveHealth is a helper function to capture
+// the health checker packed into the gin context.
+func retrieveHealth(c *gin.Context) (*health.Checker, bool) {
+	v, ok := c.Get("health")
+	if !ok {
+		c.JSON(http.StatusInternalServerError, "no health checker in the context")
+		return nil, false
+	}
+
+	h, ok := v.(*health.Checker)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, "health checker in the context is the wrong type")
+		return nil, false
+	}
+
+	return h, true
+}
+
+// report is a helper function to respond with
+// the health report and matching status code.
+func report(c *gin.Context, r *health.Report) {
+	if !r.OK() {
+		c.JSON(http.StatusServiceUnavailable, r)
+		return
+	}
+
+	c.JSON(http.StatusOK, r)
+}
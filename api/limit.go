@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BuildLimit represents the request body
+// for updating the build limit of the worker.
+//
+// swagger:model BuildLimit
+type BuildLimit struct {
+	BuildLimit int32 `json:"build_limit"`
+}
+
+// swagger:operation PUT /api/v1/build-limit system UpdateBuildLimit
+//
+// Update the number of builds the worker runs concurrently
+//
+// ---
+// produces:
+// - application/json
+// parameters:
+// - in: body
+//   name: body
+//   description: The new build limit for the worker
+//   required: true
+//   schema:
+//     "$ref": "#/definitions/BuildLimit"
+// security:
+//   - ApiKeyAuth: []
+// responses:
+//   '200':
+//     description: Successfully updated the build limit of the worker
+//     schema:
+//       type: string
+//   '400':
+//     description: Invalid build limit provided
+//     schema:
+//       type: string
+//   '500':
+//     description: Unable to update the build limit of the worker
+//     schema:
+//       type: string
+
+// UpdateBuildLimit represents the API handler to change
+// the number of builds the worker runs concurrently.
+//
+// Increasing the limit starts new executors immediately
+// while decreasing the limit retires executors once the
+// build they are running completes.
+func UpdateBuildLimit(c *gin.Context) {
+	// extract the build limit channel that was packed into gin context
+	v, ok := c.Get("build-limit")
+	if !ok {
+		c.JSON(http.StatusInternalServerError, "no build limit channel in the context")
+		return
+	}
+
+	// make sure we configured the channel properly
+	lChan, ok := v.(chan int32)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, "build limit channel in the context is the wrong type")
+		return
+	}
+
+	input := new(BuildLimit)
+
+	err := c.ShouldBindJSON(input)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, fmt.Sprintf("unable to decode JSON for build limit: %v", err))
+		return
+	}
+
+	if input.BuildLimit <= 0 {
+		c.JSON(http.StatusBadRequest, fmt.Sprintf("invalid build limit provided: %d", input.BuildLimit))
+		return
+	}
+
+	// signal the worker to resize the executors
+	select {
+	case lChan <- input.BuildLimit:
+	case <-c.Request.Context().Done():
+		c.JSON(http.StatusInternalServerError, "unable to update the build limit of the worker")
+		return
+	}
+
+	c.JSON(http.StatusOK, fmt.Sprintf("build limit of the worker updated to %d", input.BuildLimit))
+}
@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAPI_UpdateBuildLimit(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name  string
+		body  string
+		limit any
+		want  int
+		sent  int32
+	}{
+		{
+			name:  "grow",
+			body:  `{"build_limit":4}`,
+			limit: make(chan int32, 1),
+			want:  http.StatusOK,
+			sent:  4,
+		},
+		{
+			name:  "shrink",
+			body:  `{"build_limit":1}`,
+			limit: make(chan int32, 1),
+			want:  http.StatusOK,
+			sent:  1,
+		},
+		{
+			name:  "zero limit",
+			body:  `{"build_limit":0}`,
+			limit: make(chan int32, 1),
+			want:  http.StatusBadRequest,
+		},
+		{
+			name:  "negative limit",
+			body:  `{"build_limit":-1}`,
+			limit: make(chan int32, 1),
+			want:  http.StatusBadRequest,
+		},
+		{
+			name:  "bad payload",
+			body:  `{"build_limit":"foo"}`,
+			limit: make(chan int32, 1),
+			want:  http.StatusBadRequest,
+		},
+		{
+			name: "no build limit channel",
+			body: `{"build_limit":4}`,
+			want: http.StatusInternalServerError,
+		},
+		{
+			name:  "wrong type",
+			body:  `{"build_limit":4}`,
+			limit: make(chan int, 1),
+			want:  http.StatusInternalServerError,
+		},
+	}
+
+	// setup context
+	gin.SetMode(gin.TestMode)
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := httptest.NewRecorder()
+			context, engine := gin.CreateTestContext(resp)
+			context.Request, _ = http.NewRequestWithContext(t.Context(), http.MethodPut, "/api/v1/build-limit", strings.NewReader(test.body))
+			context.Request.Header.Set("Content-Type", "application/json")
+
+			// setup mock server
+			engine.Use(func(c *gin.Context) {
+				if test.limit != nil {
+					c.Set("build-limit", test.limit)
+				}
+
+				c.Next()
+			})
+			engine.PUT("/api/v1/build-limit", UpdateBuildLimit)
+
+			engine.ServeHTTP(context.Writer, context.Request)
+
+			if resp.Code != test.want {
+				t.Errorf("UpdateBuildLimit returned %v, want %v", resp.Code, test.want)
+			}
+
+			lChan, ok := test.limit.(chan int32)
+			if !ok {
+				return // continue to next test
+			}
+
+			if test.sent == 0 {
+				if len(lChan) > 0 {
+					t.Errorf("UpdateBuildLimit sent %d, want nothing", <-lChan)
+				}
+
+				return // continue to next test
+			}
+
+			if len(lChan) == 0 {
+				t.Errorf("UpdateBuildLimit sent nothing, want %d", test.sent)
+
+				return // continue to next test
+			}
+
+			if got := <-lChan; got != test.sent {
+				t.Errorf("UpdateBuildLimit sent %d, want %d", got, test.sent)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	api "github.com/go-vela/server/api/types"
+	"github.com/go-vela/server/compiler/types/pipeline"
+	"github.com/go-vela/worker/executor"
+	exec "github.com/go-vela/worker/router/middleware/executor"
+)
+
+// swagger:operation GET /api/v1/executors/{executor}/logs/{step} log StreamStepLogs
+//
+// Stream the live logs for a step as Server-Sent Events
+//
+// ---
+// produces:
+// - text/event-stream
+// parameters:
+// - in: path
+//   name: executor
+//   description: The executor running the build
+//   required: true
+//   type: string
+// - in: path
+//   name: step
+//   description: The name or number of the step
+//   required: true
+//   type: string
+// security:
+//   - ApiKeyAuth: []
+// responses:
+//   '200':
+//     description: Successfully streamed the logs for the step
+//     type: string
+//   '404':
+//     description: Unable to find the step
+//     type: json
+//   '500':
+//     description: Unable to stream the logs for the step
+//     type: json
+
+// StreamStepLogs represents the API handler to stream the live
+// logs for a step running on an executor with Server-Sent Events.
+func StreamStepLogs(c *gin.Context) {
+	e := exec.Retrieve(c)
+
+	p, err := e.GetPipeline()
+	if err != nil {
+		msg := fmt.Errorf("unable to read pipeline: %w", err).Error()
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, api.Error{Message: &msg})
+
+		return
+	}
+
+	// gather the steps for the pipeline including the steps within stages
+	steps := slices.Clone(p.Steps)
+	for _, stage := range p.Stages {
+		steps = append(steps, stage.Steps...)
+	}
+
+	streamLogs(c, e, "step", findContainer(steps, c.Param("step")))
+}
+
+// swagger:operation GET /api/v1/executors/{executor}/logs/services/{service} log StreamServiceLogs
+//
+// Stream the live logs for a service as Server-Sent Events
+//
+// ---
+// produces:
+// - text/event-stream
+// parameters:
+// - in: path
+//   name: executor
+//   description: The executor running the build
+//   required: true
+//   type: string
+// - in: path
+//   name: service
+//   description: The name or number of the service
+//   required: true
+//   type: string
+// security:
+//   - ApiKeyAuth: []
+// responses:
+//   '200':
+//     description: Successfully streamed the logs for the service
+//     type: string
+//   '404':
+//     description: Unable to find the service
+//     type: json
+//   '500':
+//     description: Unable to stream the logs for the service
+//     type: json
+
+// StreamServiceLogs represents the API handler to stream the live
+// logs for a service running on an executor with Server-Sent Events.
+func StreamServiceLogs(c *gin.Context) {
+	e := exec.Retrieve(c)
+
+	p, err := e.GetPipeline()
+	if err != nil {
+		msg := fmt.Errorf("unable to read pipeline: %w", err).Error()
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, api.Error{Message: &msg})
+
+		return
+	}
+
+	streamLogs(c, e, "service", findContainer(p.Services, c.Param("service")))
+}
+
+// streamLogs is a helper function to send the live output of
+// the container to the client until the container completes.
+//
+// Each line is sent as a "log" event and an "end" event is
+// sent once no more output will be produced for the container.
+func streamLogs(c *gin.Context, e executor.Engine, kind string, ctn *pipeline.Container) {
+	if ctn == nil {
+		msg := fmt.Sprintf("unable to find %s %s", kind, c.Param(kind))
+
+		c.AbortWithStatusJSON(http.StatusNotFound, api.Error{Message: &msg})
+
+		return
+	}
+
+	// the subscription is removed once the client disconnects
+	logs, err := e.TailLogs(c.Request.Context(), ctn)
+	if err != nil {
+		msg := fmt.Errorf("unable to stream logs for %s %s: %w", kind, ctn.Name, err).Error()
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, api.Error{Message: &msg})
+
+		return
+	}
+
+	// prevent proxies from buffering the events
+	c.Header("X-Accel-Buffering", "no")
+
+	// https://pkg.go.dev/github.com/gin-gonic/gin#Context.Stream
+	c.Stream(func(_ io.Writer) bool {
+		line, ok := <-logs
+		if !ok {
+			c.SSEvent("end", ctn.Name)
+
+			return false
+		}
+
+		c.SSEvent("log", string(line))
+
+		return true
+	})
+}
+
+// findContainer is a helper function to return
+// the container with the provided name or number.
+func findContainer(containers pipeline.ContainerSlice, param string) *pipeline.Container {
+	for _, ctn := range containers {
+		if ctn.Name == param || strconv.Itoa(ctn.Number) == param {
+			return ctn
+		}
+	}
+
+	return nil
+}
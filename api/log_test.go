@@ -0,0 +1,270 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-vela/sdk-go/vela"
+	api "github.com/go-vela/server/api/types"
+	"github.com/go-vela/server/compiler/types/pipeline"
+	"github.com/go-vela/server/mock/server"
+	"github.com/go-vela/worker/executor"
+	"github.com/go-vela/worker/executor/linux"
+	"github.com/go-vela/worker/runtime"
+	"github.com/go-vela/worker/runtime/docker"
+)
+
+// tailRuntime is a runtime that produces
+// the provided output for every container.
+type tailRuntime struct {
+	runtime.Engine
+
+	output string
+}
+
+func (r *tailRuntime) TailContainer(context.Context, *pipeline.Container) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(r.output)), nil
+}
+
+// tailExecutor is an executor that signals
+// once a client subscribed to the live logs.
+type tailExecutor struct {
+	executor.Engine
+
+	subscribed chan struct{}
+}
+
+func (e *tailExecutor) TailLogs(ctx context.Context, ctn *pipeline.Container) (<-chan []byte, error) {
+	logs, err := e.Engine.TailLogs(ctx, ctn)
+
+	close(e.subscribed)
+
+	return logs, err
+}
+
+func TestAPI_StreamLogs(t *testing.T) {
+	// setup types
+	_build := new(api.Build)
+	_build.SetNumber(1)
+	_build.SetRepo(new(api.Repo))
+	_build.GetRepo().SetOrg("github")
+	_build.GetRepo().SetName("octocat")
+	_build.GetRepo().SetFullName("github/octocat")
+	_build.GetRepo().SetTimeout(30)
+
+	_step := &pipeline.Container{
+		ID:          "step_github_octocat_1_echo",
+		Directory:   "/vela/src/github.com/github/octocat",
+		Environment: map[string]string{"SECRET_PASSWORD": "secretPass"},
+		Image:       "alpine:latest",
+		Name:        "echo",
+		Number:      2,
+		Pull:        "not_present",
+		Secrets: pipeline.StepSecretSlice{
+			{
+				Source: "password",
+				Target: "SECRET_PASSWORD",
+			},
+		},
+	}
+
+	_service := &pipeline.Container{
+		ID:          "service_github_octocat_1_postgres",
+		Directory:   "/vela/src/github.com/github/octocat",
+		Environment: map[string]string{"SECRET_PASSWORD": "secretPass"},
+		Image:       "postgres:latest",
+		Name:        "postgres",
+		Number:      1,
+		Pull:        "not_present",
+		Secrets: pipeline.StepSecretSlice{
+			{
+				Source: "password",
+				Target: "SECRET_PASSWORD",
+			},
+		},
+	}
+
+	_pipeline := &pipeline.Build{
+		Version:  "1",
+		ID:       "github_octocat_1",
+		Services: pipeline.ContainerSlice{_service},
+		Steps:    pipeline.ContainerSlice{_step},
+	}
+
+	output := "logging in with secretPass\nlogged in\n"
+
+	gin.SetMode(gin.TestMode)
+
+	s := httptest.NewServer(server.FakeHandler())
+	defer s.Close()
+
+	_client, err := vela.NewClient(s.URL, "", nil)
+	if err != nil {
+		t.Errorf("unable to create Vela API client: %v", err)
+	}
+
+	_docker, err := docker.NewMock()
+	if err != nil {
+		t.Errorf("unable to create docker runtime engine: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		name   string
+		path   string
+		stream func(executor.Engine) error
+		want   int
+		end    string
+	}{
+		{
+			name: "step",
+			path: "/logs/echo",
+			stream: func(e executor.Engine) error {
+				err := e.PlanStep(context.Background(), _step)
+				if err != nil {
+					return err
+				}
+
+				return e.StreamStep(context.Background(), _step)
+			},
+			want: http.StatusOK,
+			end:  "echo",
+		},
+		{
+			name: "step by number",
+			path: "/logs/2",
+			stream: func(e executor.Engine) error {
+				err := e.PlanStep(context.Background(), _step)
+				if err != nil {
+					return err
+				}
+
+				return e.StreamStep(context.Background(), _step)
+			},
+			want: http.StatusOK,
+			end:  "echo",
+		},
+		{
+			name: "service",
+			path: "/logs/services/postgres",
+			stream: func(e executor.Engine) error {
+				err := e.PlanService(context.Background(), _service)
+				if err != nil {
+					return err
+				}
+
+				return e.StreamService(context.Background(), _service)
+			},
+			want: http.StatusOK,
+			end:  "postgres",
+		},
+		{
+			name: "missing step",
+			path: "/logs/foo",
+			want: http.StatusNotFound,
+		},
+		{
+			name: "missing service",
+			path: "/logs/services/foo",
+			want: http.StatusNotFound,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_engine, err := linux.New(
+				linux.WithBuild(_build),
+				linux.WithPipeline(_pipeline),
+				linux.WithRuntime(&tailRuntime{Engine: _docker, output: output}),
+				linux.WithVelaClient(_client),
+			)
+			if err != nil {
+				t.Errorf("unable to create executor engine: %v", err)
+			}
+
+			e := &tailExecutor{Engine: _engine, subscribed: make(chan struct{})}
+
+			// setup mock server
+			engine := gin.New()
+			engine.Use(func(c *gin.Context) {
+				executor.WithGinContext(c, e)
+
+				c.Next()
+			})
+			engine.GET("/logs/:step", StreamStepLogs)
+			engine.GET("/logs/services/:service", StreamServiceLogs)
+
+			logs := httptest.NewServer(engine)
+			defer logs.Close()
+
+			// stream the output of the container once the client subscribed
+			streamed := make(chan error, 1)
+
+			if test.stream != nil {
+				go func() {
+					select {
+					case <-e.subscribed:
+						streamed <- test.stream(_engine)
+					case <-time.After(5 * time.Second):
+						streamed <- context.DeadlineExceeded
+					}
+				}()
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, logs.URL+test.path, nil)
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("unable to stream logs: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != test.want {
+				t.Errorf("%s returned %v, want %v", test.path, resp.StatusCode, test.want)
+			}
+
+			if test.stream == nil {
+				return // continue to next test
+			}
+
+			// the stream ends once the container exits
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Errorf("unable to read streamed logs: %v", err)
+			}
+
+			got := string(body)
+
+			// secrets are masked in the streamed lines
+			if strings.Contains(got, "secretPass") || !strings.Contains(got, "event:log\ndata:logging in with ") {
+				t.Errorf("%s streamed %q, want secret masked", test.path, got)
+			}
+
+			if !strings.Contains(got, "event:log\ndata:logged in\n\n") {
+				t.Errorf("%s streamed %q, want logged in line", test.path, got)
+			}
+
+			if !strings.HasSuffix(got, "event:end\ndata:"+test.end+"\n\n") {
+				t.Errorf("%s streamed %q, want end event for %s", test.path, got, test.end)
+			}
+
+			err = <-streamed
+			if err != nil {
+				t.Errorf("unable to stream container output: %v", err)
+			}
+		})
+	}
+}
@@ -3,12 +3,16 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 
+	"github.com/go-vela/sdk-go/vela"
+	api "github.com/go-vela/server/api/types"
+	"github.com/go-vela/server/constants"
 	"github.com/go-vela/worker/router/middleware/token"
 )
 
@@ -28,13 +32,17 @@ import (
 //     schema:
 //       type: string
 //   '401':
-//     description: No token was passed
+//     description: No token was passed or the token is invalid
 //     schema:
 //       "$ref": "#/definitions/Error"
 //   '500':
 //     description: Unable to pass token to worker
 //     schema:
 //       "$ref": "#/definitions/Error"
+//   '502':
+//     description: Unable to verify token with the server
+//     schema:
+//       "$ref": "#/definitions/Error"
 
 // Register will pass the token given in the request header to the register token
 // channel of the worker. This will unblock operation if the worker has not been
@@ -43,21 +51,30 @@ func Register(c *gin.Context) {
 	// extract the worker hostname that was packed into gin context
 	w, ok := c.Get("worker-hostname")
 	if !ok {
-		c.JSON(http.StatusInternalServerError, "no worker hostname in the context")
+		msg := "no worker hostname in the context"
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, api.Error{Message: &msg})
+
 		return
 	}
 
 	// extract the register token channel that was packed into gin context
 	v, ok := c.Get("register-token")
 	if !ok {
-		c.JSON(http.StatusInternalServerError, "no register token channel in the context")
+		msg := "no register token channel in the context"
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, api.Error{Message: &msg})
+
 		return
 	}
 
 	// make sure we configured the channel properly
 	rChan, ok := v.(chan string)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, "register token channel in the context is the wrong type")
+		msg := "register token channel in the context is the wrong type"
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, api.Error{Message: &msg})
+
 		return
 	}
 
@@ -72,57 +89,133 @@ func Register(c *gin.Context) {
 	token, err := token.Retrieve(c.Request)
 	if err != nil {
 		// an error occurs when no token was passed
-		c.JSON(http.StatusUnauthorized, err)
-		return
-	}
+		msg := err.Error()
+
+		c.AbortWithStatusJSON(http.StatusUnauthorized, api.Error{Message: &msg})
 
-	// extract the subject from the token
-	sub, err := getSubjectFromToken(token)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, err)
 		return
 	}
 
 	// make sure we configured the hostname properly
 	hostname, ok := w.(string)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, "worker hostname in the context is the wrong type")
+		msg := "worker hostname in the context is the wrong type"
+
+		c.AbortWithStatusJSON(http.StatusInternalServerError, api.Error{Message: &msg})
+
 		return
 	}
 
-	// if the subject doesn't match the worker hostname return an error
-	if sub != hostname {
-		c.JSON(http.StatusUnauthorized, "worker hostname is invalid")
+	// capture the key the server signs tokens with if configured
+	key := c.GetString("signing-key")
+
+	// validate the claims and signature of the token
+	err = validateRegisterToken(token, hostname, key)
+	if err != nil {
+		msg := err.Error()
+
+		c.AbortWithStatusJSON(http.StatusUnauthorized, api.Error{Message: &msg})
+
 		return
 	}
 
+	// without the signing key, verify the token with the server that minted it
+	if len(key) == 0 {
+		code, err := verifyRegisterToken(c.Request.Context(), c.GetString("server-address"), token)
+		if err != nil {
+			msg := err.Error()
+
+			c.AbortWithStatusJSON(code, api.Error{Message: &msg})
+
+			return
+		}
+	}
+
 	// write registration token to auth token channel
 	rChan <- token
 
 	c.JSON(http.StatusOK, "successfully passed token to worker")
 }
 
-// getSubjectFromToken is a helper function to extract
-// the subject from the token claims.
-func getSubjectFromToken(token string) (string, error) {
-	// create a new JWT parser
-	j := jwt.NewParser()
+// registerClaims represents the claims for
+// the registration token minted by the server.
+type registerClaims struct {
+	TokenType string `json:"token_type"`
+	jwt.RegisteredClaims
+}
 
-	// parse the payload
-	t, _, err := j.ParseUnverified(token, jwt.MapClaims{})
-	if err != nil {
-		return "", fmt.Errorf("unable to parse token")
+// validateRegisterToken is a helper function to ensure the token is
+// an unexpired registration token for the worker. When the key the
+// server signs tokens with is provided, the signature is verified too.
+func validateRegisterToken(token, hostname, key string) error {
+	claims := new(registerClaims)
+
+	// the token must expire and be minted for the worker
+	opts := []jwt.ParserOption{
+		jwt.WithExpirationRequired(),
+		jwt.WithSubject(hostname),
+	}
+
+	if len(key) > 0 {
+		// the server signs tokens with HMAC using its private key
+		opts = append(opts, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+
+		// parse and verify the token
+		_, err := jwt.NewParser(opts...).ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+			return []byte(key), nil
+		})
+		if err != nil {
+			return fmt.Errorf("invalid registration token: %w", err)
+		}
+	} else {
+		// parse the payload
+		_, _, err := jwt.NewParser().ParseUnverified(token, claims)
+		if err != nil {
+			return fmt.Errorf("unable to parse registration token: %w", err)
+		}
+
+		// validate the expiration and subject of the token
+		err = jwt.NewValidator(opts...).Validate(claims)
+		if err != nil {
+			return fmt.Errorf("invalid registration token: %w", err)
+		}
+	}
+
+	// make sure the token was minted for registering workers
+	if claims.TokenType != constants.WorkerRegisterTokenType {
+		return fmt.Errorf("invalid registration token: token type %q is not %s", claims.TokenType, constants.WorkerRegisterTokenType)
 	}
 
-	sub, err := t.Claims.GetSubject()
+	return nil
+}
+
+// verifyRegisterToken is a helper function to verify the token with the
+// server when the worker is not configured with the key to verify it.
+//
+// The server accepts registration tokens for fetching the queue
+// information, which doesn't consume the token for the worker.
+func verifyRegisterToken(ctx context.Context, addr, token string) (int, error) {
+	if len(addr) == 0 {
+		return http.StatusInternalServerError, fmt.Errorf("unable to verify registration token: no signing key or server address configured")
+	}
+
+	// create a temporary client to verify the token
+	client, err := vela.NewClient(addr, "vela-worker", nil)
 	if err != nil {
-		return "", fmt.Errorf("unable to get subject from token")
+		return http.StatusInternalServerError, fmt.Errorf("unable to create vela client: %w", err)
 	}
 
-	// make sure there was a subject defined
-	if len(sub) == 0 {
-		return "", fmt.Errorf("no subject defined in token")
+	client.Authentication.SetTokenAuth(token)
+
+	_, resp, err := client.Queue.GetInfo(ctx)
+	if err != nil {
+		// the server rejected the token
+		if resp != nil && (resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden) {
+			return http.StatusUnauthorized, fmt.Errorf("invalid registration token: rejected by server: %w", err)
+		}
+
+		return http.StatusBadGateway, fmt.Errorf("unable to verify registration token with server: %w", err)
 	}
 
-	return sub, nil
+	return http.StatusOK, nil
 }
@@ -0,0 +1,374 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/go-vela/server/constants"
+)
+
+func TestAPI_validateRegisterToken(t *testing.T) {
+	// setup types
+	key := "superSecretKey"
+	hostname := "worker"
+
+	// setup tests
+	tests := []struct {
+		name    string
+		key     string
+		token   string
+		failure bool
+	}{
+		{
+			name:    "signed token",
+			key:     key,
+			token:   testRegisterToken(t, jwt.SigningMethodHS256, key, constants.WorkerRegisterTokenType, hostname, time.Hour),
+			failure: false,
+		},
+		{
+			name:    "wrong signature",
+			key:     key,
+			token:   testRegisterToken(t, jwt.SigningMethodHS256, "notSecretKey", constants.WorkerRegisterTokenType, hostname, time.Hour),
+			failure: true,
+		},
+		{
+			name:    "alg none",
+			key:     key,
+			token:   testRegisterToken(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, constants.WorkerRegisterTokenType, hostname, time.Hour),
+			failure: true,
+		},
+		{
+			name:    "other algorithm",
+			key:     key,
+			token:   testRegisterToken(t, jwt.SigningMethodHS512, key, constants.WorkerRegisterTokenType, hostname, time.Hour),
+			failure: true,
+		},
+		{
+			name:    "expired token",
+			key:     key,
+			token:   testRegisterToken(t, jwt.SigningMethodHS256, key, constants.WorkerRegisterTokenType, hostname, -time.Hour),
+			failure: true,
+		},
+		{
+			name:    "no expiration",
+			key:     key,
+			token:   testRegisterToken(t, jwt.SigningMethodHS256, key, constants.WorkerRegisterTokenType, hostname, 0),
+			failure: true,
+		},
+		{
+			name:    "wrong sub",
+			key:     key,
+			token:   testRegisterToken(t, jwt.SigningMethodHS256, key, constants.WorkerRegisterTokenType, "other-worker", time.Hour),
+			failure: true,
+		},
+		{
+			name:    "wrong token_type",
+			key:     key,
+			token:   testRegisterToken(t, jwt.SigningMethodHS256, key, constants.WorkerAuthTokenType, hostname, time.Hour),
+			failure: true,
+		},
+		{
+			name:    "unverified token without key",
+			token:   testRegisterToken(t, jwt.SigningMethodHS256, "notSecretKey", constants.WorkerRegisterTokenType, hostname, time.Hour),
+			failure: false,
+		},
+		{
+			name:    "expired token without key",
+			token:   testRegisterToken(t, jwt.SigningMethodHS256, key, constants.WorkerRegisterTokenType, hostname, -time.Hour),
+			failure: true,
+		},
+		{
+			name:    "wrong sub without key",
+			token:   testRegisterToken(t, jwt.SigningMethodHS256, key, constants.WorkerRegisterTokenType, "other-worker", time.Hour),
+			failure: true,
+		},
+		{
+			name:    "wrong token_type without key",
+			token:   testRegisterToken(t, jwt.SigningMethodHS256, key, constants.ServerWorkerTokenType, hostname, time.Hour),
+			failure: true,
+		},
+		{
+			name:    "malformed token without key",
+			token:   "superSecret",
+			failure: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateRegisterToken(test.token, hostname, test.key)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("validateRegisterToken should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("validateRegisterToken returned err: %v", err)
+			}
+		})
+	}
+}
+
+func TestAPI_verifyRegisterToken(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		status  int
+		addr    bool
+		want    int
+		failure bool
+	}{
+		{
+			name:    "accepted by server",
+			status:  http.StatusOK,
+			addr:    true,
+			want:    http.StatusOK,
+			failure: false,
+		},
+		{
+			name:    "unauthorized by server",
+			status:  http.StatusUnauthorized,
+			addr:    true,
+			want:    http.StatusUnauthorized,
+			failure: true,
+		},
+		{
+			name:    "forbidden by server",
+			status:  http.StatusForbidden,
+			addr:    true,
+			want:    http.StatusUnauthorized,
+			failure: true,
+		},
+		{
+			name:    "server error",
+			status:  http.StatusInternalServerError,
+			addr:    true,
+			want:    http.StatusBadGateway,
+			failure: true,
+		},
+		{
+			name:    "server unavailable",
+			status:  http.StatusServiceUnavailable,
+			addr:    true,
+			want:    http.StatusBadGateway,
+			failure: true,
+		},
+		{
+			name:    "no server address",
+			addr:    false,
+			want:    http.StatusInternalServerError,
+			failure: true,
+		},
+	}
+
+	// setup context
+	gin.SetMode(gin.TestMode)
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// setup mock server
+			_, engine := gin.CreateTestContext(httptest.NewRecorder())
+
+			engine.GET("/api/v1/queue/info", func(c *gin.Context) {
+				if c.GetHeader("Authorization") != "Bearer superSecret" {
+					c.AbortWithStatus(http.StatusUnauthorized)
+
+					return
+				}
+
+				if test.status != http.StatusOK {
+					c.AbortWithStatus(test.status)
+
+					return
+				}
+
+				c.JSON(http.StatusOK, map[string]string{"queue_address": "redis://redis:6379"})
+			})
+
+			s := httptest.NewServer(engine)
+			defer s.Close()
+
+			addr := ""
+			if test.addr {
+				addr = s.URL
+			}
+
+			got, err := verifyRegisterToken(t.Context(), addr, "superSecret")
+
+			if got != test.want {
+				t.Errorf("verifyRegisterToken is %v, want %v", got, test.want)
+			}
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("verifyRegisterToken should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("verifyRegisterToken returned err: %v", err)
+			}
+		})
+	}
+}
+
+func TestAPI_Register(t *testing.T) {
+	// setup types
+	key := "superSecretKey"
+	hostname := "worker"
+
+	// setup tests
+	tests := []struct {
+		name   string
+		key    string
+		token  string
+		status int
+		want   int
+		calls  int
+	}{
+		{
+			name:   "verified with signing key",
+			key:    key,
+			token:  testRegisterToken(t, jwt.SigningMethodHS256, key, constants.WorkerRegisterTokenType, hostname, time.Hour),
+			status: http.StatusOK,
+			want:   http.StatusOK,
+			calls:  0,
+		},
+		{
+			name:   "rejected with signing key",
+			key:    key,
+			token:  testRegisterToken(t, jwt.SigningMethodHS256, "notSecretKey", constants.WorkerRegisterTokenType, hostname, time.Hour),
+			status: http.StatusOK,
+			want:   http.StatusUnauthorized,
+			calls:  0,
+		},
+		{
+			name:   "missing key verified with server",
+			token:  testRegisterToken(t, jwt.SigningMethodHS256, key, constants.WorkerRegisterTokenType, hostname, time.Hour),
+			status: http.StatusOK,
+			want:   http.StatusOK,
+			calls:  1,
+		},
+		{
+			name:   "missing key rejected by server",
+			token:  testRegisterToken(t, jwt.SigningMethodHS256, key, constants.WorkerRegisterTokenType, hostname, time.Hour),
+			status: http.StatusUnauthorized,
+			want:   http.StatusUnauthorized,
+			calls:  1,
+		},
+		{
+			name:   "missing key with server error",
+			token:  testRegisterToken(t, jwt.SigningMethodHS256, key, constants.WorkerRegisterTokenType, hostname, time.Hour),
+			status: http.StatusInternalServerError,
+			want:   http.StatusBadGateway,
+			calls:  1,
+		},
+		{
+			name:   "missing key with invalid token",
+			token:  testRegisterToken(t, jwt.SigningMethodHS256, key, constants.WorkerRegisterTokenType, hostname, -time.Hour),
+			status: http.StatusOK,
+			want:   http.StatusUnauthorized,
+			calls:  0,
+		},
+	}
+
+	// setup context
+	gin.SetMode(gin.TestMode)
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls := 0
+
+			// setup mock server
+			_, serverEngine := gin.CreateTestContext(httptest.NewRecorder())
+
+			serverEngine.GET("/api/v1/queue/info", func(c *gin.Context) {
+				calls++
+
+				c.AbortWithStatus(test.status)
+			})
+
+			s := httptest.NewServer(serverEngine)
+			defer s.Close()
+
+			// setup mock worker
+			rChan := make(chan string, 1)
+
+			resp := httptest.NewRecorder()
+			context, engine := gin.CreateTestContext(resp)
+			context.Request, _ = http.NewRequestWithContext(t.Context(), http.MethodPost, "/register", nil)
+			context.Request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", test.token))
+
+			engine.Use(func(c *gin.Context) {
+				c.Set("worker-hostname", hostname)
+				c.Set("register-token", rChan)
+				c.Set("server-address", s.URL)
+				c.Set("signing-key", test.key)
+			})
+			engine.POST("/register", Register)
+
+			// run test
+			engine.ServeHTTP(context.Writer, context.Request)
+
+			if resp.Code != test.want {
+				t.Errorf("Register returned %v, want %v", resp.Code, test.want)
+			}
+
+			if calls != test.calls {
+				t.Errorf("Register verified token with server %d times, want %d", calls, test.calls)
+			}
+
+			// the token is only passed to the worker once it is valid
+			if got := len(rChan) == 1; got != (test.want == http.StatusOK) {
+				t.Errorf("Register passed token to worker is %v, want %v", got, test.want == http.StatusOK)
+			}
+		})
+	}
+}
+
+// testRegisterToken is a test helper function to create a
+// registration token signed with the method and key.
+func testRegisterToken(t *testing.T, method jwt.SigningMethod, key any, tokenType, subject string, expires time.Duration) string {
+	t.Helper()
+
+	claims := &registerClaims{
+		TokenType: tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:  subject,
+			IssuedAt: jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	// a zero duration creates a token that never expires
+	if expires != 0 {
+		claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(expires))
+	}
+
+	if k, ok := key.(string); ok {
+		key = []byte(k)
+	}
+
+	tkn, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatalf("unable to sign token: %v", err)
+	}
+
+	return tkn
+}
@@ -18,8 +18,12 @@ import (
 // security:
 //   - ApiKeyAuth: []
 // responses:
-//   '501':
-//     description: Endpoint is not yet implemented
+//   '200':
+//     description: Successfully started draining the worker
+//     schema:
+//       type: string
+//   '500':
+//     description: Unable to start draining the worker
 //     schema:
 //       type: string
 
@@ -27,8 +31,31 @@ import (
 // executors currently running on an worker.
 //
 // This function performs a soft shut down of a worker.
-// Any build running during this time will safely complete, then
+// The worker stops accepting new builds and any build
+// running during this time will safely complete, then
 // the worker will safely shut itself down.
 func Shutdown(c *gin.Context) {
-	c.JSON(http.StatusNotImplemented, "This endpoint is not yet implemented")
+	// extract the shutdown channel that was packed into gin context
+	v, ok := c.Get("shutdown")
+	if !ok {
+		c.JSON(http.StatusInternalServerError, "no shutdown channel in the context")
+		return
+	}
+
+	// make sure we configured the channel properly
+	sChan, ok := v.(chan struct{})
+	if !ok {
+		c.JSON(http.StatusInternalServerError, "shutdown channel in the context is the wrong type")
+		return
+	}
+
+	// signal the worker to begin draining
+	//
+	// the send is skipped if a shutdown was already requested
+	select {
+	case sChan <- struct{}{}:
+	default:
+	}
+
+	c.JSON(http.StatusOK, "worker is draining and will shut down once running builds complete")
 }
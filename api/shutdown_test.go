@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAPI_Shutdown(t *testing.T) {
+	// setup types
+	full := make(chan struct{}, 1)
+	full <- struct{}{}
+
+	// setup tests
+	tests := []struct {
+		name     string
+		shutdown any
+		want     int
+		signaled bool
+	}{
+		{
+			name:     "signaled",
+			shutdown: make(chan struct{}, 1),
+			want:     http.StatusOK,
+			signaled: true,
+		},
+		{
+			name:     "already requested",
+			shutdown: full,
+			want:     http.StatusOK,
+			signaled: true,
+		},
+		{
+			name: "no shutdown channel",
+			want: http.StatusInternalServerError,
+		},
+		{
+			name:     "wrong type",
+			shutdown: make(chan bool, 1),
+			want:     http.StatusInternalServerError,
+		},
+	}
+
+	// setup context
+	gin.SetMode(gin.TestMode)
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := httptest.NewRecorder()
+			context, engine := gin.CreateTestContext(resp)
+			context.Request, _ = http.NewRequestWithContext(t.Context(), http.MethodPost, "/api/v1/shutdown", nil)
+
+			// setup mock server
+			engine.Use(func(c *gin.Context) {
+				if test.shutdown != nil {
+					c.Set("shutdown", test.shutdown)
+				}
+
+				c.Next()
+			})
+			engine.POST("/api/v1/shutdown", Shutdown)
+
+			engine.ServeHTTP(context.Writer, context.Request)
+
+			if resp.Code != test.want {
+				t.Errorf("Shutdown returned %v, want %v", resp.Code, test.want)
+			}
+
+			sChan, ok := test.shutdown.(chan struct{})
+			if !ok {
+				return // continue to next test
+			}
+
+			if got := len(sChan) == 1; got != test.signaled {
+				t.Errorf("Shutdown signaled is %v, want %v", got, test.signaled)
+			}
+		})
+	}
+}
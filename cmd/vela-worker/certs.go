@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// certCheckInterval represents the minimum time between
+// checks of the certificate and key files for changes.
+const certCheckInterval = 10 * time.Second
+
+// certReloader represents the certificate for the worker API
+// that is reloaded when the certificate or key files change,
+// such as when they are rotated by cert-manager.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	// minimum time between checks of the files for changes
+	interval time.Duration
+
+	mu   sync.Mutex
+	cert *tls.Certificate
+	// time the files were last checked for changes
+	checked time.Time
+	// modification times of the files for the loaded certificate
+	certMod time.Time
+	keyMod  time.Time
+}
+
+// newCertReloader returns a certReloader with
+// the certificate loaded from the provided files.
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		interval: certCheckInterval,
+	}
+
+	err := r.reload()
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// GetCertificate returns the certificate for the TLS handshake,
+// reloading it first if the certificate or key files changed.
+// The files are checked at most once per interval so handshakes
+// don't query the file system every time.
+//
+// https://pkg.go.dev/crypto/tls#Config.GetCertificate
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// serve the loaded certificate until the files are due for a check
+	if time.Since(r.checked) < r.interval {
+		return r.cert, nil
+	}
+
+	// keep serving the loaded certificate if the files are mid-rotation
+	err := r.reload()
+	if err != nil {
+		logrus.Errorf("unable to reload certificate: %v", err)
+	}
+
+	return r.cert, nil
+}
+
+// reload is a helper function to load the certificate
+// if the certificate or key files changed since the last load.
+//
+// The caller must hold the lock for the reloader once it is in use.
+func (r *certReloader) reload() error {
+	r.checked = time.Now()
+
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return fmt.Errorf("expecting certificate file at %s, got %w", r.certFile, err)
+	}
+
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return fmt.Errorf("expecting certificate key at %s, got %w", r.keyFile, err)
+	}
+
+	// skip loading the certificate if the files are unchanged
+	if r.cert != nil && certInfo.ModTime().Equal(r.certMod) && keyInfo.ModTime().Equal(r.keyMod) {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("unable to load certificate: %w", err)
+	}
+
+	if r.cert != nil {
+		logrus.Infof("reloaded certificate from %s", r.certFile)
+	}
+
+	r.cert = &cert
+	r.certMod = certInfo.ModTime()
+	r.keyMod = keyInfo.ModTime()
+
+	return nil
+}
+
+// loadClientCA is a helper function to load the CA bundle
+// for verifying the certificates of clients of the worker API.
+func loadClientCA(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read client CA bundle %s: %w", path, err)
+	}
+
+	pool := x509.NewCertPool()
+
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in client CA bundle %s", path)
+	}
+
+	return pool, nil
+}
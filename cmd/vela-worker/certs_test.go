@@ -0,0 +1,365 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testCert is a test helper for a certificate and key.
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTestCert is a helper function to create a certificate
+// for tests that is signed by the parent or self-signed.
+func newTestCert(t *testing.T, name string, parent *testCert) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatalf("unable to generate serial number: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	signer, signerKey := template, key
+
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("unable to create certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("unable to parse certificate: %v", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("unable to marshal key: %v", err)
+	}
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+// tlsCertificate is a helper function to create
+// the TLS certificate for a client in tests.
+func (c *testCert) tlsCertificate(t *testing.T) tls.Certificate {
+	t.Helper()
+
+	cert, err := tls.X509KeyPair(c.certPEM, c.keyPEM)
+	if err != nil {
+		t.Fatalf("unable to load key pair: %v", err)
+	}
+
+	return cert
+}
+
+// writeTestCert is a helper function to write the certificate
+// and key to disk with the provided modification time.
+func writeTestCert(t *testing.T, certFile, keyFile string, c *testCert, mod time.Time) {
+	t.Helper()
+
+	for path, data := range map[string][]byte{certFile: c.certPEM, keyFile: c.keyPEM} {
+		err := os.WriteFile(path, data, 0o600)
+		if err != nil {
+			t.Fatalf("unable to write %s: %v", path, err)
+		}
+
+		err = os.Chtimes(path, mod, mod)
+		if err != nil {
+			t.Fatalf("unable to set modification time for %s: %v", path, err)
+		}
+	}
+}
+
+func TestWorker_certReloader_GetCertificate(t *testing.T) {
+	// setup types
+	ca := newTestCert(t, "ca", nil)
+	current := newTestCert(t, "localhost", ca)
+	rotated := newTestCert(t, "localhost", ca)
+
+	// setup tests
+	tests := []struct {
+		name     string
+		interval time.Duration
+		rotate   func(t *testing.T, certFile, keyFile string)
+		want     *testCert
+	}{
+		{
+			name: "rotated",
+			rotate: func(t *testing.T, certFile, keyFile string) {
+				writeTestCert(t, certFile, keyFile, rotated, time.Now().Add(time.Minute))
+			},
+			want: rotated,
+		},
+		{
+			name:     "rotated before next check",
+			interval: time.Hour,
+			rotate: func(t *testing.T, certFile, keyFile string) {
+				writeTestCert(t, certFile, keyFile, rotated, time.Now().Add(time.Minute))
+			},
+			want: current,
+		},
+		{
+			name: "mid-rotation",
+			rotate: func(t *testing.T, certFile, _ string) {
+				// the certificate is rotated before the key
+				writeTestCert(t, certFile, filepath.Join(t.TempDir(), "tls.key"), rotated, time.Now().Add(time.Minute))
+			},
+			want: current,
+		},
+		{
+			name: "removed",
+			rotate: func(t *testing.T, certFile, _ string) {
+				err := os.Remove(certFile)
+				if err != nil {
+					t.Fatalf("unable to remove %s: %v", certFile, err)
+				}
+			},
+			want: current,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			certFile := filepath.Join(dir, "tls.crt")
+			keyFile := filepath.Join(dir, "tls.key")
+
+			writeTestCert(t, certFile, keyFile, current, time.Now())
+
+			r, err := newCertReloader(certFile, keyFile)
+			if err != nil {
+				t.Fatalf("newCertReloader returned err: %v", err)
+			}
+
+			r.interval = test.interval
+
+			// serve a handshake so the next one is checked against the interval
+			_, err = r.GetCertificate(nil)
+			if err != nil {
+				t.Errorf("GetCertificate returned err: %v", err)
+			}
+
+			test.rotate(t, certFile, keyFile)
+
+			got, err := r.GetCertificate(nil)
+			if err != nil {
+				t.Errorf("GetCertificate returned err: %v", err)
+			}
+
+			if got == nil || got.Leaf == nil || !got.Leaf.Equal(test.want.cert) {
+				t.Errorf("GetCertificate did not return the %s certificate", test.want.cert.SerialNumber)
+			}
+		})
+	}
+}
+
+func TestWorker_certReloader_Serve(t *testing.T) {
+	// setup types
+	ca := newTestCert(t, "ca", nil)
+	current := newTestCert(t, "localhost", ca)
+	rotated := newTestCert(t, "localhost", ca)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+
+	writeTestCert(t, certFile, keyFile, current, time.Now())
+
+	r, err := newCertReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("newCertReloader returned err: %v", err)
+	}
+
+	r.interval = 0
+
+	// setup server
+	s := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	s.TLS = &tls.Config{GetCertificate: r.GetCertificate}
+	s.StartTLS()
+	defer s.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+
+	// served returns the certificate served for a new connection
+	served := func() *x509.Certificate {
+		conn, err := tls.Dial("tcp", s.Listener.Addr().String(), &tls.Config{
+			RootCAs:    pool,
+			ServerName: "localhost",
+		})
+		if err != nil {
+			t.Fatalf("unable to connect to server: %v", err)
+		}
+		defer conn.Close()
+
+		return conn.ConnectionState().PeerCertificates[0]
+	}
+
+	if got := served(); !got.Equal(current.cert) {
+		t.Errorf("server returned certificate %s, want %s", got.SerialNumber, current.cert.SerialNumber)
+	}
+
+	writeTestCert(t, certFile, keyFile, rotated, time.Now().Add(time.Minute))
+
+	if got := served(); !got.Equal(rotated.cert) {
+		t.Errorf("server returned certificate %s, want %s", got.SerialNumber, rotated.cert.SerialNumber)
+	}
+}
+
+func TestWorker_loadClientCA(t *testing.T) {
+	// setup types
+	ca := newTestCert(t, "ca", nil)
+	other := newTestCert(t, "other", nil)
+	server := newTestCert(t, "localhost", ca)
+
+	dir := t.TempDir()
+	bundle := filepath.Join(dir, "ca.crt")
+	empty := filepath.Join(dir, "empty.crt")
+
+	err := os.WriteFile(bundle, ca.certPEM, 0o600)
+	if err != nil {
+		t.Fatalf("unable to write %s: %v", bundle, err)
+	}
+
+	err = os.WriteFile(empty, []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("unable to write %s: %v", empty, err)
+	}
+
+	pool, err := loadClientCA(bundle)
+	if err != nil {
+		t.Fatalf("loadClientCA returned err: %v", err)
+	}
+
+	// setup server
+	s := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	s.TLS = &tls.Config{
+		Certificates: []tls.Certificate{server.tlsCertificate(t)},
+		ClientCAs:    pool,
+		ClientAuth:   tls.VerifyClientCertIfGiven,
+	}
+	s.StartTLS()
+	defer s.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	// setup tests
+	tests := []struct {
+		name    string
+		failure bool
+		client  *testCert
+	}{
+		{
+			name:    "signed by CA",
+			failure: false,
+			client:  newTestCert(t, "client", ca),
+		},
+		{
+			name:    "signed by other CA",
+			failure: true,
+			client:  newTestCert(t, "client", other),
+		},
+		{
+			name:    "no client certificate",
+			failure: false,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &tls.Config{
+				RootCAs:    roots,
+				ServerName: "localhost",
+			}
+
+			// always send the client certificate regardless
+			// of the authorities accepted by the server
+			if test.client != nil {
+				cert := test.client.tlsCertificate(t)
+
+				cfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+					return &cert, nil
+				}
+			}
+
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: cfg}}
+
+			resp, err := client.Get(s.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("server should have rejected the client certificate")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("server returned err: %v", err)
+			}
+		})
+	}
+
+	// setup failure tests
+	for _, path := range []string{empty, filepath.Join(dir, "missing.crt")} {
+		_, err := loadClientCA(path)
+		if err == nil {
+			t.Errorf("loadClientCA for %s should have returned err", path)
+		}
+	}
+}
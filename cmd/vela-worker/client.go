@@ -7,13 +7,14 @@ import (
 
 	"github.com/go-vela/sdk-go/vela"
 	api "github.com/go-vela/server/api/types"
+	"github.com/go-vela/worker/internal/tracing"
 )
 
 // helper function to setup the vela API client for worker check-in and build executable retrieval.
 func setupClient(s *Server, token string) (*vela.Client, error) {
 	logrus.Debug("creating vela client from worker configuration")
 
-	vela, err := vela.NewClient(s.Address, "", nil)
+	vela, err := vela.NewClient(s.Address, "", tracing.HTTPClient())
 	if err != nil {
 		return nil, err
 	}
@@ -27,7 +28,7 @@ func setupClient(s *Server, token string) (*vela.Client, error) {
 func setupExecClient(s *Server, buildToken, scmToken string, exp int64, build *api.Build) (*vela.Client, error) {
 	logrus.Debug("creating vela client from worker configuration")
 
-	vela, err := vela.NewClient(s.Address, "", nil)
+	vela, err := vela.NewClient(s.Address, "", tracing.HTTPClient())
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+	"sigs.k8s.io/yaml"
+)
+
+// configFileKey is the context key for the configuration file.
+type configFileKey struct{}
+
+// configFile represents the YAML or JSON file
+// with the configuration for the worker.
+type configFile struct {
+	// path is the location of the file
+	path string
+	// overrides are the settings provided by flags or environment variables
+	overrides map[string]bool
+	// values are the settings from the file when the worker started
+	values map[string][]string
+}
+
+// loadConfigFile is a helper function to apply the settings from the
+// configuration file to the flags for the worker. Settings provided by
+// flags or environment variables take precedence over the file.
+func loadConfigFile(ctx context.Context, c *cli.Command) (context.Context, error) {
+	path := c.String("config")
+	if len(path) == 0 {
+		return ctx, nil
+	}
+
+	values, err := readConfigFile(path)
+	if err != nil {
+		return ctx, err
+	}
+
+	file := &configFile{
+		path:      path,
+		overrides: make(map[string]bool),
+		values:    values,
+	}
+
+	// sort the settings so they are applied in a consistent order
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		flag := lookupFlag(c, name)
+		if flag == nil {
+			return ctx, fmt.Errorf("unknown setting %q in configuration file %s", name, path)
+		}
+
+		// skip settings provided by flags or environment variables
+		if c.IsSet(name) {
+			file.overrides[name] = true
+
+			continue
+		}
+
+		for _, value := range values[name] {
+			err = c.Set(name, value)
+			if err != nil {
+				return ctx, fmt.Errorf("invalid value %q for setting %q in configuration file %s: %w", value, name, path, err)
+			}
+		}
+
+		// run the validation for the flag since actions
+		// only run for flags provided on the command line
+		if action, ok := flag.(cli.ActionableFlag); ok {
+			err = action.RunAction(ctx, c)
+			if err != nil {
+				return ctx, err
+			}
+		}
+	}
+
+	logrus.Debugf("loaded configuration file %s", path)
+
+	return context.WithValue(ctx, configFileKey{}, file), nil
+}
+
+// readConfigFile is a helper function to parse the configuration file
+// into the values for each setting keyed by the name of the flag.
+//
+// Nested objects are joined with a period so the file can either
+// use the flag names directly or group them by their prefix:
+//
+//	build:
+//	  limit: 2
+//	runtime.privileged-images:
+//	  - target/vela-docker
+func readConfigFile(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read configuration file %s: %w", path, err)
+	}
+
+	raw := make(map[string]any)
+
+	// YAML is a superset of JSON so both formats are supported
+	//
+	// https://pkg.go.dev/sigs.k8s.io/yaml#Unmarshal
+	err = yaml.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse configuration file %s: %w", path, err)
+	}
+
+	values := make(map[string][]string)
+
+	err = flattenConfig("", raw, values)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+
+	return values, nil
+}
+
+// flattenConfig is a helper function to capture the values for the
+// nested objects in the configuration file keyed by their full name.
+func flattenConfig(prefix string, raw map[string]any, values map[string][]string) error {
+	for key, value := range raw {
+		name := key
+		if len(prefix) > 0 {
+			name = prefix + "." + key
+		}
+
+		switch v := value.(type) {
+		case nil:
+			continue
+		case map[string]any:
+			err := flattenConfig(name, v, values)
+			if err != nil {
+				return err
+			}
+		case []any:
+			list := make([]string, 0, len(v))
+
+			for _, item := range v {
+				s, err := configValue(name, item)
+				if err != nil {
+					return err
+				}
+
+				list = append(list, s)
+			}
+
+			values[name] = list
+		default:
+			s, err := configValue(name, v)
+			if err != nil {
+				return err
+			}
+
+			values[name] = []string{s}
+		}
+	}
+
+	return nil
+}
+
+// configValue is a helper function to convert a
+// scalar from the configuration file to a string.
+func configValue(name string, value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		// avoid exponents for large numbers
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("unsupported value %v for setting %q", value, name)
+	}
+}
+
+// lookupFlag is a helper function to return
+// the flag for the worker with the name.
+func lookupFlag(c *cli.Command, name string) cli.Flag {
+	for _, flag := range c.Flags {
+		if slices.Contains(flag.Names(), name) {
+			return flag
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,221 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/urfave/cli/v3"
+)
+
+func TestWorker_readConfigFile(t *testing.T) {
+	// setup types
+	want := map[string][]string{
+		"build.limit":               {"2"},
+		"build.timeout":             {"30m"},
+		"log.level":                 {"debug"},
+		"runtime.privileged-images": {"target/vela-docker", "target/vela-kaniko"},
+	}
+
+	// setup tests
+	tests := []struct {
+		name    string
+		failure bool
+		path    string
+		want    map[string][]string
+	}{
+		{
+			name:    "nested yaml",
+			failure: false,
+			path:    "testdata/config/nested.yml",
+			want:    want,
+		},
+		{
+			name:    "flat json",
+			failure: false,
+			path:    "testdata/config/flat.json",
+			want:    want,
+		},
+		{
+			name:    "invalid file",
+			failure: true,
+			path:    "testdata/config/invalid.yml",
+		},
+		{
+			name:    "unsupported value",
+			failure: true,
+			path:    "testdata/config/unsupported.yml",
+		},
+		{
+			name:    "missing file",
+			failure: true,
+			path:    "testdata/config/missing.yml",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := readConfigFile(test.path)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("readConfigFile should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("readConfigFile returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("readConfigFile is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWorker_loadConfigFile(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name      string
+		failure   bool
+		args      []string
+		env       map[string]string
+		level     string
+		limit     int
+		images    []string
+		overrides map[string]bool
+	}{
+		{
+			name:      "file",
+			failure:   false,
+			args:      []string{"--config", "testdata/config/nested.yml"},
+			level:     "debug",
+			limit:     2,
+			images:    []string{"target/vela-docker", "target/vela-kaniko"},
+			overrides: map[string]bool{},
+		},
+		{
+			name:      "flag takes precedence",
+			failure:   false,
+			args:      []string{"--config", "testdata/config/nested.yml", "--log.level", "warn"},
+			level:     "warn",
+			limit:     2,
+			images:    []string{"target/vela-docker", "target/vela-kaniko"},
+			overrides: map[string]bool{"log.level": true},
+		},
+		{
+			name:      "environment takes precedence",
+			failure:   false,
+			args:      []string{"--config", "testdata/config/nested.yml"},
+			env:       map[string]string{"WORKER_BUILD_LIMIT": "5"},
+			level:     "debug",
+			limit:     5,
+			images:    []string{"target/vela-docker", "target/vela-kaniko"},
+			overrides: map[string]bool{"build.limit": true},
+		},
+		{
+			name:    "no file",
+			failure: false,
+			args:    []string{},
+			level:   "info",
+			limit:   1,
+		},
+		{
+			name:    "unknown setting",
+			failure: true,
+			args:    []string{"--config", "testdata/config/unknown.yml"},
+		},
+		{
+			name:    "invalid value",
+			failure: true,
+			args:    []string{"--config", "testdata/config/bad_value.yml"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for key, value := range test.env {
+				t.Setenv(key, value)
+			}
+
+			var (
+				file   *configFile
+				level  string
+				limit  int
+				images []string
+			)
+
+			cmd := &cli.Command{
+				Name: "vela-worker",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "config"},
+					&cli.StringFlag{Name: "log.level", Value: "info"},
+					&cli.IntFlag{Name: "build.limit", Sources: cli.EnvVars("WORKER_BUILD_LIMIT"), Value: 1},
+					&cli.StringFlag{Name: "build.timeout"},
+					&cli.StringSliceFlag{Name: "runtime.privileged-images"},
+				},
+				Before: loadConfigFile,
+				Action: func(ctx context.Context, c *cli.Command) error {
+					file, _ = ctx.Value(configFileKey{}).(*configFile)
+					level = c.String("log.level")
+					limit = c.Int("build.limit")
+					images = c.StringSlice("runtime.privileged-images")
+
+					return nil
+				},
+			}
+
+			err := cmd.Run(context.Background(), append([]string{"vela-worker"}, test.args...))
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("loadConfigFile should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("loadConfigFile returned err: %v", err)
+			}
+
+			if level != test.level {
+				t.Errorf("loadConfigFile log level is %s, want %s", level, test.level)
+			}
+
+			if limit != test.limit {
+				t.Errorf("loadConfigFile build limit is %d, want %d", limit, test.limit)
+			}
+
+			if !slices.Equal(images, test.images) {
+				t.Errorf("loadConfigFile privileged images are %v, want %v", images, test.images)
+			}
+
+			if test.overrides == nil {
+				if file != nil {
+					t.Errorf("loadConfigFile file is %v, want nil", file)
+				}
+
+				return // continue to next test
+			}
+
+			if file == nil {
+				t.Errorf("loadConfigFile file is nil")
+
+				return // continue to next test
+			}
+
+			if !reflect.DeepEqual(file.overrides, test.overrides) {
+				t.Errorf("loadConfigFile overrides are %v, want %v", file.overrides, test.overrides)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"maps"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/go-vela/server/constants"
+	"github.com/go-vela/worker/executor"
+)
+
+// workerStatusDraining represents the status reported for the
+// worker while it finishes running builds before shutting down.
+const workerStatusDraining = "draining"
+
+// drain is a helper function to stop the Worker from
+// accepting new builds and wait for the running builds
+// to complete and the executors to exit. Any builds still
+// running once the drain timeout is exceeded, or another
+// signal is received, are canceled.
+func (w *Worker) drain(ctx context.Context, force <-chan os.Signal) {
+	// signal the operator subprocesses to stop polling the queue
+	w.drainOnce.Do(func() {
+		close(w.Draining)
+	})
+
+	logrus.Infof("draining worker, waiting up to %s for running builds to complete", w.Config.Build.DrainTimeout)
+
+	// one second ticker for checking the running builds
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	// create the timer for the maximum drain time
+	timer := time.NewTimer(w.Config.Build.DrainTimeout)
+	defer timer.Stop()
+
+	for {
+		// check if all running builds have completed and the executors
+		// have exited, so no build is popped from the queue after draining
+		if w.runningBuildCount() == 0 && w.runningExecutorCount() == 0 {
+			logrus.Info("all running builds completed, worker drained")
+
+			// remove the executors for the completed builds
+			w.clearExecutors()
+
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			logrus.Info("completed draining worker")
+
+			return
+		case sig := <-force:
+			logrus.Warnf("received signal %s while draining, canceling running builds", sig)
+
+			w.cancelRunningBuilds()
+		case <-timer.C:
+			logrus.Warnf("drain timeout of %s exceeded, canceling running builds", w.Config.Build.DrainTimeout)
+
+			w.cancelRunningBuilds()
+		case <-ticker.C:
+		}
+	}
+}
+
+// isDraining is a helper function to determine
+// if the Worker has started draining.
+func (w *Worker) isDraining() bool {
+	select {
+	case <-w.Draining:
+		return true
+	default:
+		return false
+	}
+}
+
+// drainContext is a helper function to create a context
+// that is canceled once the Worker starts draining, so
+// the executors stop waiting on an item from the queue.
+// An item popped as the Worker starts draining is still
+// run since the drain waits for the running builds.
+func (w *Worker) drainContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-w.Draining:
+			cancel()
+		}
+	}()
+
+	return ctx, cancel
+}
+
+// runningBuildCount is a helper function to capture
+// the number of builds running on the Worker.
+func (w *Worker) runningBuildCount() int {
+	w.RunningBuildsMutex.Lock()
+	defer w.RunningBuildsMutex.Unlock()
+
+	return len(w.RunningBuilds)
+}
+
+// runningExecutorCount is a helper function to capture
+// the number of executors running on the Worker.
+func (w *Worker) runningExecutorCount() int {
+	w.limitMutex.Lock()
+	defer w.limitMutex.Unlock()
+
+	return w.executorLoops
+}
+
+// setExecutor is a helper function to add
+// the executor for a build to the Worker.
+func (w *Worker) setExecutor(id int, e executor.Engine) {
+	w.ExecutorsMutex.Lock()
+	defer w.ExecutorsMutex.Unlock()
+
+	w.Executors[id] = e
+}
+
+// clearExecutors is a helper function to
+// remove all the executors from the Worker.
+func (w *Worker) clearExecutors() {
+	w.ExecutorsMutex.Lock()
+	defer w.ExecutorsMutex.Unlock()
+
+	clear(w.Executors)
+}
+
+// executors is a helper function to capture a
+// snapshot of the executors on the Worker.
+func (w *Worker) executors() map[int]executor.Engine {
+	w.ExecutorsMutex.RLock()
+	defer w.ExecutorsMutex.RUnlock()
+
+	return maps.Clone(w.Executors)
+}
+
+// cancelRunningBuilds is a helper function to cancel
+// the builds still running on the Worker executors.
+func (w *Worker) cancelRunningBuilds() {
+	for id, e := range w.executors() {
+		// get build on executor
+		b, err := e.GetBuild()
+		if err != nil {
+			continue
+		}
+
+		// skip executors that are not running a build
+		if !strings.EqualFold(b.GetStatus(), constants.StatusRunning) {
+			continue
+		}
+
+		logrus.Infof("canceling build %s #%d on executor %d", b.GetRepo().GetFullName(), b.GetNumber(), id)
+
+		_, err = e.CancelBuild()
+		if err != nil {
+			logrus.Errorf("unable to cancel build %s #%d: %v", b.GetRepo().GetFullName(), b.GetNumber(), err)
+		}
+	}
+}
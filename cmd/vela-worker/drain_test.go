@@ -0,0 +1,213 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	api "github.com/go-vela/server/api/types"
+	"github.com/go-vela/server/constants"
+	"github.com/go-vela/worker/executor"
+)
+
+// drainExecutor is an executor running a build
+// that completes once the build is canceled.
+type drainExecutor struct {
+	executor.Engine
+
+	w        *Worker
+	build    *api.Build
+	canceled bool
+}
+
+func (e *drainExecutor) GetBuild() (*api.Build, error) {
+	return e.build, nil
+}
+
+func (e *drainExecutor) CancelBuild() (*api.Build, error) {
+	e.canceled = true
+
+	// complete the build on the worker
+	e.w.RunningBuildsMutex.Lock()
+	e.w.RunningBuilds = nil
+	e.w.RunningBuildsMutex.Unlock()
+
+	return e.build, nil
+}
+
+func TestWorker_drain(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name      string
+		timeout   time.Duration
+		running   bool
+		executors int
+		signal    bool
+		canceled  bool
+	}{
+		{
+			name:    "no running builds",
+			timeout: time.Minute,
+		},
+		{
+			name:      "waits for executors",
+			timeout:   time.Minute,
+			executors: 1,
+		},
+		{
+			name:     "drain timeout exceeded",
+			timeout:  time.Millisecond,
+			running:  true,
+			canceled: true,
+		},
+		{
+			name:     "forced",
+			timeout:  time.Minute,
+			running:  true,
+			signal:   true,
+			canceled: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := &Worker{
+				Config: &Config{
+					Build: &Build{
+						DrainTimeout: test.timeout,
+					},
+				},
+				Draining:      make(chan struct{}),
+				Executors:     make(map[int]executor.Engine),
+				executorLoops: test.executors,
+			}
+
+			build := new(api.Build)
+			build.SetStatus(constants.StatusRunning)
+
+			_executor := &drainExecutor{w: w, build: build}
+
+			if test.running {
+				w.RunningBuilds = []*api.Build{build}
+				w.setExecutor(0, _executor)
+			}
+
+			// stop the executors after the drain started
+			if test.executors > 0 {
+				go func() {
+					<-w.Draining
+
+					for range test.executors {
+						w.stopExecutor()
+					}
+				}()
+			}
+
+			force := make(chan os.Signal, 1)
+			if test.signal {
+				force <- syscall.SIGTERM
+			}
+
+			done := make(chan struct{})
+
+			go func() {
+				w.drain(context.Background(), force)
+
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(10 * time.Second):
+				t.Fatal("drain did not return")
+			}
+
+			if !w.isDraining() {
+				t.Errorf("drain did not start draining")
+			}
+
+			if w.runningBuildCount() != 0 || w.runningExecutorCount() != 0 {
+				t.Errorf("drain returned with %d builds and %d executors running", w.runningBuildCount(), w.runningExecutorCount())
+			}
+
+			if _executor.canceled != test.canceled {
+				t.Errorf("drain canceled is %v, want %v", _executor.canceled, test.canceled)
+			}
+
+			if len(w.executors()) != 0 {
+				t.Errorf("drain returned with executors %v, want none", w.executors())
+			}
+		})
+	}
+}
+
+func TestWorker_executors(t *testing.T) {
+	// setup types
+	w := &Worker{
+		Executors: make(map[int]executor.Engine),
+	}
+
+	want := new(testExecutor)
+
+	// run test
+	var wg sync.WaitGroup
+
+	for i := range 10 {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			w.setExecutor(i, want)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			_ = w.executors()
+		}()
+	}
+
+	wg.Wait()
+
+	got := w.executors()
+
+	if len(got) != 10 {
+		t.Errorf("executors is %v, want 10 executors", got)
+	}
+
+	// modifying the snapshot doesn't modify the worker
+	delete(got, 0)
+
+	if !reflect.DeepEqual(w.executors()[0], executor.Engine(want)) {
+		t.Errorf("executors snapshot modified the worker executors")
+	}
+}
+
+func TestWorker_drainContext(t *testing.T) {
+	w := &Worker{
+		Draining: make(chan struct{}),
+	}
+
+	ctx, cancel := w.drainContext(context.Background())
+	defer cancel()
+
+	if ctx.Err() != nil {
+		t.Errorf("drainContext is canceled before draining: %v", ctx.Err())
+	}
+
+	close(w.Draining)
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(10 * time.Second):
+		t.Errorf("drainContext is not canceled after draining")
+	}
+}
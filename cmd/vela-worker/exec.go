@@ -7,10 +7,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/go-vela/sdk-go/vela"
 	api "github.com/go-vela/server/api/types"
@@ -18,10 +21,18 @@ import (
 	"github.com/go-vela/server/constants"
 	"github.com/go-vela/server/queue/models"
 	"github.com/go-vela/worker/executor"
+	"github.com/go-vela/worker/internal/build"
+	"github.com/go-vela/worker/internal/infra"
+	"github.com/go-vela/worker/internal/metrics"
+	"github.com/go-vela/worker/internal/tracing"
 	"github.com/go-vela/worker/runtime"
 	"github.com/go-vela/worker/version"
 )
 
+// infraRestartBackoff is the delay before restarting a build after an
+// infrastructure failure, which grows with each restart of the build.
+var infraRestartBackoff = 10 * time.Second
+
 // exec is a helper function to poll the queue
 // and execute Vela pipelines for the Worker.
 //
@@ -69,8 +80,21 @@ func (w *Worker) exec(ctx context.Context, index int, config *api.Worker) error
 
 		// capture an item from the queue only on first loop iteration (failures here return nil)
 		if i == 0 {
-			item, err = w.Queue.Pop(ctx, worker.GetRoutes())
+			popped := time.Now()
+
+			// stop waiting on the queue once the worker starts draining
+			popCtx, cancel := w.drainContext(ctx)
+
+			item, err = w.Queue.Pop(popCtx, worker.GetRoutes())
+
+			cancel()
+
 			if err != nil {
+				// the pop was canceled since the worker is draining
+				if w.isDraining() {
+					return nil
+				}
+
 				logrus.Errorf("queue pop failed: %v", err)
 
 				// returning immediately on queue pop fail will attempt
@@ -85,6 +109,31 @@ func (w *Worker) exec(ctx context.Context, index int, config *api.Worker) error
 			if item == nil {
 				return nil
 			}
+
+			// trace the build from the time the queue was polled
+			var span trace.Span
+
+			ctx, span = tracing.StartAt(ctx, "exec", popped,
+				attribute.String("vela.repo", item.Build.GetRepo().GetFullName()),
+				attribute.Int64("vela.build", item.Build.GetNumber()),
+			)
+			defer span.End()
+
+			_, pop := tracing.StartAt(ctx, "queue.Pop", popped)
+			pop.End()
+
+			metrics.ObserveQueuePop(metrics.Labels{
+				Repo:     item.Build.GetRepo().GetFullName(),
+				Runtime:  w.Config.Runtime.Driver,
+				Executor: w.Config.Executor.Driver,
+			}, time.Since(popped))
+
+			// handle stale item queued before a Vela upgrade or downgrade
+			if item.ItemVersion != models.ItemVersion {
+				// If the ItemVersion is older or newer than what we expect, then it might
+				// not be safe to process the build. Requeue the build instead.
+				return w.handleStale(ctx, item)
+			}
 		}
 
 		// retrieve a build token from the server to setup the execBuildClient
@@ -137,16 +186,11 @@ func (w *Worker) exec(ctx context.Context, index int, config *api.Worker) error
 		}
 
 		// get the build pipeline from the build executable
-		p = new(pipeline.Build)
-
-		err = json.Unmarshal(execBuildExecutable.GetData(), p)
+		p, err = preparePipeline(item, execBuildExecutable.GetData())
 		if err != nil {
 			return err
 		}
 
-		// prepare pipeline by hydrating container ID values based on build information
-		p.Prepare(item.Build.GetRepo().GetOrg(), item.Build.GetRepo().GetName(), item.Build.GetNumber(), false)
-
 		// setup exec client with scm token and build token
 		execBuildClient, err = setupExecClient(w.Config.Server, bt.GetToken(), p.Token, p.TokenExp, item.Build)
 		if err != nil {
@@ -156,12 +200,6 @@ func (w *Worker) exec(ctx context.Context, index int, config *api.Worker) error
 		break
 	}
 
-	// dereference configured outputs ctn config and set the outputs container ID for the executor
-	//
-	// need to dereference to avoid executors sharing the last set outputs container config
-	execOutputCtn := *w.Config.Executor.OutputCtn
-	execOutputCtn.ID = fmt.Sprintf("outputs_%s", p.ID)
-
 	// create logger with extra metadata
 	//
 	// https://pkg.go.dev/github.com/sirupsen/logrus#WithFields
@@ -175,67 +213,211 @@ func (w *Worker) exec(ctx context.Context, index int, config *api.Worker) error
 		"version":  v.Semantic(),
 	})
 
+	// create labels for the build metrics
+	labels := metrics.Labels{
+		Repo:     item.Build.GetRepo().GetFullName(),
+		Runtime:  w.Config.Runtime.Driver,
+		Executor: w.Config.Executor.Driver,
+	}
+
 	// lock and append the build to the list
 	w.RunningBuildsMutex.Lock()
 
 	w.RunningBuilds = append(w.RunningBuilds, item.Build)
 
-	config.SetRunningBuilds(w.RunningBuilds)
+	running := slices.Clone(w.RunningBuilds)
 
 	w.RunningBuildsMutex.Unlock()
 
-	// set worker status
-	updateStatus := w.getWorkerStatusFromConfig(config)
-	config.SetStatus(updateStatus)
-	config.SetLastStatusUpdateAt(time.Now().Unix())
-	config.SetLastBuildStartedAt(time.Now().Unix())
-
 	// update worker in the database
-	_, _, err = w.VelaClient.Worker.Update(ctx, config.GetHostname(), config)
+	_, err = w.updateWorker(ctx, config, func(c *api.Worker) {
+		c.SetRunningBuilds(running)
+
+		// set worker status
+		c.SetStatus(w.getWorkerStatusFromConfig(c))
+		c.SetLastStatusUpdateAt(time.Now().Unix())
+		c.SetLastBuildStartedAt(time.Now().Unix())
+	})
 	if err != nil {
 		logger.Errorf("unable to update worker: %v", err)
 	}
 
-	// handle stale item queued before a Vela upgrade or downgrade.
-	if item.ItemVersion != models.ItemVersion {
-		// If the ItemVersion is older or newer than what we expect, then it might
-		// not be safe to process the build. Fail the build and loop to the next item.
-		// TODO: Ask the server to re-compile and requeue the build instead of failing it.
-		logrus.Errorf("Failing stale queued build due to wrong item version: want %d, got %d", models.ItemVersion, item.ItemVersion)
+	// capture the settings that can be reloaded while the build runs
+	s := w.settings()
 
-		build := item.Build
-		build.SetError("Unable to process stale build (queued before Vela upgrade/downgrade).")
-		build.SetStatus(constants.StatusError)
-		build.SetFinished(time.Now().UTC().Unix())
+	// start is a helper function to setup a new executor with the
+	// runtime for every attempt to run the build
+	start := func(restartable bool) (executor.Engine, error) {
+		// the executor modifies the pipeline, so get a fresh
+		// copy of it when the build is restarted
+		if _executor != nil {
+			p, err = preparePipeline(item, execBuildExecutable.GetData())
+			if err != nil {
+				return nil, err
+			}
+		}
 
-		_, _, err := execBuildClient.Build.Update(ctx, build)
+		// dereference configured outputs ctn config and set the outputs container ID for the executor
+		//
+		// need to dereference to avoid executors sharing the last set outputs container config
+		execOutputCtn := *w.Config.Executor.OutputCtn
+		execOutputCtn.ID = fmt.Sprintf("outputs_%s", p.ID)
+
+		// setup the executor with the runtime for the build
+		e, err := w.newExecutor(logger, s, item.Build, p, execBuildClient, &execOutputCtn, restartable)
 		if err != nil {
-			logrus.Errorf("Unable to set build status to %s: %s", constants.StatusFailure, err)
-			return err
+			return nil, err
+		}
+
+		_executor = e
+		// add the executor to the worker
+		w.setExecutor(index, _executor)
+
+		// record the build in the journal to clean up after a crash or restart
+		//
+		// https://pkg.go.dev/github.com/go-vela/worker/internal/journal#Journal.Record
+		err = w.Journal.Record(w.journalEntry(item, p, &execOutputCtn))
+		if err != nil {
+			logger.Errorf("unable to record build in journal: %v", err)
+		}
+
+		return _executor, nil
+	}
+
+	// setup the executor for the first attempt to run the build
+	_, err = start(w.Config.Build.InfraRestartLimit > 0)
+	if err != nil {
+		return err
+	}
+
+	// this gets deferred first so that it runs AFTER the build is destroyed
+	defer func() {
+		// remove the build from the journal
+		err = w.Journal.Remove(item.Build.GetID())
+		if err != nil {
+			logger.Errorf("unable to remove build from journal: %v", err)
+		}
+
+		// capture the build to record the final status
+		if _build, err := _executor.GetBuild(); err == nil {
+			metrics.BuildFinished(labels, _build.GetStatus())
+		}
+
+		// lock and remove the build from the list
+		w.RunningBuildsMutex.Lock()
+
+		for i, v := range w.RunningBuilds {
+			if v.GetID() == item.Build.GetID() {
+				w.RunningBuilds = append(w.RunningBuilds[:i], w.RunningBuilds[i+1:]...)
+			}
+		}
+
+		running := slices.Clone(w.RunningBuilds)
+
+		w.RunningBuildsMutex.Unlock()
+
+		// update worker in the database
+		_, err := w.updateWorker(ctx, config, func(c *api.Worker) {
+			c.SetRunningBuilds(running)
+
+			// set worker status
+			c.SetStatus(w.getWorkerStatusFromConfig(c))
+			c.SetLastStatusUpdateAt(time.Now().Unix())
+			c.SetLastBuildFinishedAt(time.Now().Unix())
+		})
+		if err != nil {
+			logger.Errorf("unable to update worker: %v", err)
 		}
+	}()
+
+	metrics.BuildStarted(labels)
+
+	// run the build through the lifecycle of the executor
+	w.execBuild(ctx, logger, _executor, start, execBuildClient, s.buildTimeout(item.Build))
 
-		return nil
+	return nil
+}
+
+// getWorkerStatusFromConfig is a helper function
+// to determine the appropriate worker status.
+func (w *Worker) getWorkerStatusFromConfig(config *api.Worker) string {
+	// preserve the draining status until the worker shuts down
+	if w.isDraining() {
+		return workerStatusDraining
 	}
 
+	limit := int(w.buildLimit())
+
+	switch rb := len(config.GetRunningBuilds()); {
+	case rb == 0:
+		return constants.WorkerStatusIdle
+	case rb < limit:
+		return constants.WorkerStatusAvailable
+	default:
+		// running builds exceed the limit while executors
+		// finish their builds after the limit decreased
+		return constants.WorkerStatusBusy
+	}
+}
+
+// preparePipeline is a helper function to get the
+// pipeline for the build from the build executable.
+func preparePipeline(item *models.Item, data []byte) (*pipeline.Build, error) {
+	p := new(pipeline.Build)
+
+	err := json.Unmarshal(data, p)
+	if err != nil {
+		return nil, err
+	}
+
+	// prepare pipeline by hydrating container ID values based on build information
+	p.Prepare(item.Build.GetRepo().GetOrg(), item.Build.GetRepo().GetName(), item.Build.GetNumber(), false)
+
+	return p, nil
+}
+
+// newExecutor is a helper function to setup the
+// executor with the runtime for running a build.
+//
+// The restartable flag leaves reporting infrastructure failures before
+// any step runs to the worker so the build can be restarted.
+func (w *Worker) newExecutor(logger *logrus.Entry, s settings, build *api.Build, p *pipeline.Build, client *vela.Client, outputCtn *pipeline.Container, restartable bool) (executor.Engine, error) {
 	// setup the runtime
 	//
 	// https://pkg.go.dev/github.com/go-vela/worker/runtime#New
-	w.Runtime, err = runtime.New(&runtime.Setup{
-		Logger:           logger,
-		Mock:             w.Config.Mock,
-		Driver:           w.Config.Runtime.Driver,
-		ConfigFile:       w.Config.Runtime.ConfigFile,
-		HostVolumes:      w.Config.Runtime.HostVolumes,
-		Namespace:        w.Config.Runtime.Namespace,
-		PodsTemplateName: w.Config.Runtime.PodsTemplateName,
-		PodsTemplateFile: w.Config.Runtime.PodsTemplateFile,
-		PrivilegedImages: w.Config.Runtime.PrivilegedImages,
-		DropCapabilities: w.Config.Runtime.DropCapabilities,
+	_runtime, err := w.Runtimes.New(&runtime.Setup{
+		Logger:              logger,
+		Mock:                w.Config.Mock,
+		Driver:              w.Config.Runtime.Driver,
+		ConfigFile:          w.Config.Runtime.ConfigFile,
+		HostVolumes:         s.HostVolumes,
+		Namespace:           w.Config.Runtime.Namespace,
+		PodsTemplateName:    w.Config.Runtime.PodsTemplateName,
+		PodsTemplateFile:    w.Config.Runtime.PodsTemplateFile,
+		PrivilegedImages:    s.PrivilegedImages,
+		DropCapabilities:    w.Config.Runtime.DropCapabilities,
+		DockerConfig:        w.Config.Runtime.DockerConfig,
+		RegistryCredentials: w.Config.Runtime.RegistryCredentials,
+		DefaultCPULimit:     w.Config.Runtime.DefaultCPULimit,
+		DefaultMemoryLimit:  w.Config.Runtime.DefaultMemoryLimit,
+		MaxCPULimit:         w.Config.Runtime.MaxCPULimit,
+		MaxMemoryLimit:      w.Config.Runtime.MaxMemoryLimit,
+		Org:                 build.GetRepo().GetOrg(),
+		Repo:                build.GetRepo().GetFullName(),
+		BuildNumber:         build.GetNumber(),
+		Hostname:            w.Config.API.Address.Hostname(),
+		StopGracePeriod:     w.Config.Runtime.StopGracePeriod,
+		StartTimeout:        w.Config.Runtime.StartTimeout,
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	// create a span for every call to the runtime
+	//
+	// https://pkg.go.dev/github.com/go-vela/worker/runtime#WithTracing
+	_runtime = runtime.WithTracing(_runtime)
+
 	// setup the executor
 	//
 	// https://pkg.go.dev/github.com/go-vela/worker/executor#New
@@ -247,78 +429,116 @@ func (w *Worker) exec(ctx context.Context, index int, config *api.Worker) error
 		FileSizeLimit:       w.Config.Executor.FileSizeLimit,
 		BuildFileSizeLimit:  w.Config.Executor.BuildFileSizeLimit,
 		LogStreamingTimeout: w.Config.Executor.LogStreamingTimeout,
+		LogSpoolDir:         w.Config.Executor.LogSpoolDir,
+		StepTimeout:         w.Config.Executor.StepTimeout,
+		StageTimeout:        w.Config.Executor.StageTimeout,
+		RetryAttempts:       w.Config.Executor.RetryAttempts,
+		RetryBackoff:        w.Config.Executor.RetryBackoff,
+		RetryExitCodes:      w.Config.Executor.RetryExitCodes,
+		Restartable:         restartable,
 		EnforceTrustedRepos: w.Config.Executor.EnforceTrustedRepos,
-		PrivilegedImages:    w.Config.Runtime.PrivilegedImages,
-		Client:              execBuildClient,
+		PrivilegedImages:    s.PrivilegedImages,
+		Client:              client,
 		Hostname:            w.Config.API.Address.Hostname(),
-		Runtime:             w.Runtime,
-		Build:               item.Build,
+		Runtime:             _runtime,
+		Build:               build,
 		Pipeline:            p.Sanitize(w.Config.Runtime.Driver),
-		Version:             v.Semantic(),
-		OutputCtn:           &execOutputCtn,
+		Version:             version.New().Semantic(),
+		OutputCtn:           outputCtn,
 	}
 
-	_executor, err = executor.New(setup)
+	_executor, err := executor.New(setup)
 	if err != nil {
 		logger.Errorf("unable to setup executor: %v", err)
-		return err
+		return nil, err
 	}
-	// add the executor to the worker
-	w.Executors[index] = _executor
 
+	// create a span for every phase of the build lifecycle
+	//
+	// https://pkg.go.dev/github.com/go-vela/worker/executor#WithTracing
+	return executor.WithTracing(_executor), nil
+}
+
+// execBuild is a helper function to run the build with the executor
+// and restart it with a new executor from start when it fails due to
+// the infrastructure before any step ran, e.g. the runtime was briefly
+// unavailable or an image could not be pulled from the registry.
+//
+// Once the restarts are exhausted, the executor reports the failure.
+func (w *Worker) execBuild(ctx context.Context, logger *logrus.Entry, _executor executor.Engine, start func(bool) (executor.Engine, error), client *vela.Client, t time.Duration) {
+	limit := w.Config.Build.InfraRestartLimit
+
+	for attempt := 1; ; attempt++ {
+		// run the build through the lifecycle of the executor
+		err := w.runBuild(ctx, logger, _executor, t)
+
+		// check if the build failed due to the infrastructure before any step ran
+		//
+		// https://pkg.go.dev/github.com/go-vela/worker/internal/infra#Is
+		if attempt > limit || !infra.Is(err) {
+			return
+		}
+
+		logger.Warnf("restarting build after infrastructure failure (restart %d of %d): %v", attempt, limit, err)
+
+		var (
+			next    executor.Engine
+			restart error
+		)
+
+		// wait before restarting the build, backing off with each attempt
+		select {
+		case <-ctx.Done():
+			restart = ctx.Err()
+		case <-time.After(time.Duration(attempt) * infraRestartBackoff):
+			// setup a new executor for the next attempt, which leaves reporting
+			// the failure to the worker unless it is the last attempt
+			next, restart = start(attempt < limit)
+		}
+
+		if restart != nil {
+			logger.Errorf("unable to restart build: %v", restart)
+
+			// report the failure the executor left to the worker
+			//
+			// https://pkg.go.dev/github.com/go-vela/worker/internal/build#Snapshot
+			if _build, e := _executor.GetBuild(); e == nil {
+				build.Snapshot(context.WithoutCancel(ctx), _build, client, err, logger)
+			}
+
+			return
+		}
+
+		_executor = next
+	}
+}
+
+// runBuild is a helper function to run the build through the
+// lifecycle of the executor and destroy it once complete.
+//
+// The error is returned if the build failed before it was executed,
+// i.e. while creating, planning or assembling the build.
+func (w *Worker) runBuild(ctx context.Context, logger *logrus.Entry, _executor executor.Engine, t time.Duration) error {
 	// This WaitGroup delays calling DestroyBuild until the StreamBuild goroutine finishes.
 	var wg sync.WaitGroup
 
 	// this gets deferred first so that DestroyBuild runs AFTER the
 	// new contexts (buildCtx and timeoutCtx) have been canceled
 	defer func() {
-		// if exec() exits before starting StreamBuild, this returns immediately.
+		// if runBuild() exits before starting StreamBuild, this returns immediately.
 		wg.Wait()
 
 		logger.Info("destroying build")
 
 		// destroy the build with the executor
-		err = _executor.DestroyBuild(ctx)
+		err := _executor.DestroyBuild(ctx)
 		if err != nil {
 			logger.Errorf("unable to destroy build: %v", err)
 		}
 
 		logger.Info("completed build")
-
-		// lock and remove the build from the list
-		w.RunningBuildsMutex.Lock()
-
-		for i, v := range w.RunningBuilds {
-			if v.GetID() == item.Build.GetID() {
-				w.RunningBuilds = append(w.RunningBuilds[:i], w.RunningBuilds[i+1:]...)
-			}
-		}
-
-		config.SetRunningBuilds(w.RunningBuilds)
-
-		w.RunningBuildsMutex.Unlock()
-
-		// set worker status
-		updateStatus := w.getWorkerStatusFromConfig(config)
-		config.SetStatus(updateStatus)
-		config.SetLastStatusUpdateAt(time.Now().Unix())
-		config.SetLastBuildFinishedAt(time.Now().Unix())
-
-		// update worker in the database
-		_, _, err := w.VelaClient.Worker.Update(ctx, config.GetHostname(), config)
-		if err != nil {
-			logger.Errorf("unable to update worker: %v", err)
-		}
 	}()
 
-	// capture the configured build timeout
-	t := w.Config.Build.Timeout
-	// check if the repository has a custom timeout
-	if item.Build.GetRepo().GetTimeout() > 0 {
-		// update timeout variable to repository custom timeout
-		t = time.Duration(item.Build.GetRepo().GetTimeout()) * time.Minute
-	}
-
 	// create a build context
 	buildCtx, done := context.WithCancel(ctx)
 	defer done()
@@ -330,10 +550,10 @@ func (w *Worker) exec(ctx context.Context, index int, config *api.Worker) error
 
 	logger.Info("creating build")
 	// create the build with the executor
-	err = _executor.CreateBuild(timeoutCtx)
+	err := _executor.CreateBuild(timeoutCtx)
 	if err != nil {
 		logger.Errorf("unable to create build: %v", err)
-		return nil
+		return err
 	}
 
 	logger.Info("planning build")
@@ -341,7 +561,7 @@ func (w *Worker) exec(ctx context.Context, index int, config *api.Worker) error
 	err = _executor.PlanBuild(timeoutCtx)
 	if err != nil {
 		logger.Errorf("unable to plan build: %v", err)
-		return nil
+		return err
 	}
 
 	logger.Info("assembling build")
@@ -349,7 +569,7 @@ func (w *Worker) exec(ctx context.Context, index int, config *api.Worker) error
 	err = _executor.AssembleBuild(timeoutCtx)
 	if err != nil {
 		logger.Errorf("unable to assemble build: %v", err)
-		return nil
+		return err
 	}
 
 	// add StreamBuild goroutine to WaitGroup
@@ -358,7 +578,7 @@ func (w *Worker) exec(ctx context.Context, index int, config *api.Worker) error
 	wg.Go(func() {
 		logger.Info("streaming build logs")
 		// execute the build with the executor
-		err = _executor.StreamBuild(buildCtx)
+		err := _executor.StreamBuild(buildCtx)
 		if err != nil {
 			logger.Errorf("unable to stream build logs: %v", err)
 		}
@@ -369,23 +589,7 @@ func (w *Worker) exec(ctx context.Context, index int, config *api.Worker) error
 	err = _executor.ExecBuild(timeoutCtx)
 	if err != nil {
 		logger.Errorf("unable to execute build: %v", err)
-		return nil
 	}
 
 	return nil
 }
-
-// getWorkerStatusFromConfig is a helper function
-// to determine the appropriate worker status.
-func (w *Worker) getWorkerStatusFromConfig(config *api.Worker) string {
-	switch rb := len(config.GetRunningBuilds()); {
-	case rb == 0:
-		return constants.WorkerStatusIdle
-	case rb < int(w.Config.Build.Limit):
-		return constants.WorkerStatusAvailable
-	case rb == int(w.Config.Build.Limit):
-		return constants.WorkerStatusBusy
-	default:
-		return constants.WorkerStatusError
-	}
-}
@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	api "github.com/go-vela/server/api/types"
+	"github.com/go-vela/worker/executor"
+	"github.com/go-vela/worker/internal/infra"
+)
+
+// testExecutor is an executor that fails to create
+// the build with the provided error.
+type testExecutor struct {
+	executor.Engine
+
+	err       error
+	destroyed bool
+}
+
+func (e *testExecutor) GetBuild() (*api.Build, error) {
+	return new(api.Build), nil
+}
+
+func (e *testExecutor) CreateBuild(context.Context) error {
+	return e.err
+}
+
+func (e *testExecutor) PlanBuild(context.Context) error {
+	return nil
+}
+
+func (e *testExecutor) AssembleBuild(context.Context) error {
+	return nil
+}
+
+func (e *testExecutor) ExecBuild(context.Context) error {
+	return nil
+}
+
+func (e *testExecutor) StreamBuild(context.Context) error {
+	return nil
+}
+
+func (e *testExecutor) DestroyBuild(context.Context) error {
+	e.destroyed = true
+
+	return nil
+}
+
+func TestWorker_execBuild(t *testing.T) {
+	// setup types
+	_infra := infra.Wrap(errors.New("connection refused"))
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// setup tests
+	tests := []struct {
+		name    string
+		limit   int
+		ctx     context.Context
+		backoff time.Duration
+		errs    []error
+		want    []bool
+	}{
+		{
+			name:  "success",
+			limit: 2,
+			ctx:   context.Background(),
+			errs:  []error{nil},
+			want:  []bool{},
+		},
+		{
+			name:  "restarted after infrastructure failure",
+			limit: 2,
+			ctx:   context.Background(),
+			errs:  []error{_infra, nil},
+			want:  []bool{true},
+		},
+		{
+			name:  "restarts exhausted",
+			limit: 2,
+			ctx:   context.Background(),
+			errs:  []error{_infra, _infra, _infra},
+			want:  []bool{true, false},
+		},
+		{
+			name:  "pipeline failure",
+			limit: 2,
+			ctx:   context.Background(),
+			errs:  []error{errors.New("unable to load init step from pipeline")},
+			want:  []bool{},
+		},
+		{
+			name:  "restarts disabled",
+			limit: 0,
+			ctx:   context.Background(),
+			errs:  []error{_infra},
+			want:  []bool{},
+		},
+		{
+			name:    "canceled",
+			limit:   2,
+			ctx:     canceled,
+			backoff: time.Minute,
+			errs:    []error{_infra},
+			want:    []bool{},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			infraRestartBackoff = test.backoff
+
+			w := &Worker{
+				Config: &Config{
+					Build: &Build{
+						InfraRestartLimit: test.limit,
+					},
+				},
+			}
+
+			executors := []*testExecutor{{err: test.errs[0]}}
+			got := []bool{}
+
+			start := func(restartable bool) (executor.Engine, error) {
+				got = append(got, restartable)
+
+				_executor := &testExecutor{err: test.errs[len(executors)]}
+				executors = append(executors, _executor)
+
+				return _executor, nil
+			}
+
+			w.execBuild(test.ctx, logrus.NewEntry(logrus.StandardLogger()), executors[0], start, nil, time.Minute)
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("execBuild restarts are %v, want %v", got, test.want)
+			}
+
+			for i, _executor := range executors {
+				if !_executor.destroyed {
+					t.Errorf("execBuild did not destroy build for attempt %d", i+1)
+				}
+			}
+		})
+	}
+
+	infraRestartBackoff = 10 * time.Second
+}
@@ -21,6 +21,12 @@ import (
 func flags() []cli.Flag {
 	f := []cli.Flag{
 
+		&cli.StringFlag{
+			Name:    "config",
+			Usage:   "path to a YAML or JSON file with the configuration for the worker (flags and environment variables take precedence)",
+			Sources: cli.EnvVars("WORKER_CONFIG", "VELA_WORKER_CONFIG"),
+		},
+
 		&cli.StringFlag{
 			Name:    "worker.addr",
 			Usage:   "Worker server address as a fully qualified url (<scheme>://<host>)",
@@ -61,6 +67,29 @@ func flags() []cli.Flag {
 			Sources: cli.EnvVars("WORKER_BUILD_TIMEOUT", "VELA_BUILD_TIMEOUT", "BUILD_TIMEOUT"),
 			Value:   30 * time.Minute,
 		},
+		&cli.DurationFlag{
+			Name:    "build.drain-timeout",
+			Usage:   "maximum amount of time to wait for running builds to complete on shutdown before canceling them",
+			Sources: cli.EnvVars("WORKER_BUILD_DRAIN_TIMEOUT", "VELA_BUILD_DRAIN_TIMEOUT", "BUILD_DRAIN_TIMEOUT"),
+			Value:   30 * time.Minute,
+		},
+		&cli.StringFlag{
+			Name:    "build.journal-dir",
+			Usage:   "directory for recording running builds to clean up after a crash or restart of the worker - must persist across restarts (i.e. a mounted volume); an empty value disables the journal",
+			Sources: cli.EnvVars("WORKER_BUILD_JOURNAL_DIR", "VELA_BUILD_JOURNAL_DIR", "BUILD_JOURNAL_DIR"),
+		},
+		&cli.IntFlag{
+			Name:    "build.infra-restart-limit",
+			Usage:   "maximum number of times to restart a build that failed due to the infrastructure before any step ran",
+			Sources: cli.EnvVars("WORKER_BUILD_INFRA_RESTART_LIMIT", "VELA_BUILD_INFRA_RESTART_LIMIT", "BUILD_INFRA_RESTART_LIMIT"),
+			Value:   2,
+		},
+		&cli.IntFlag{
+			Name:    "build.requeue-limit",
+			Usage:   "maximum number of times to push a build queued by a different version of Vela back onto the queue before failing it",
+			Sources: cli.EnvVars("WORKER_BUILD_REQUEUE_LIMIT", "VELA_BUILD_REQUEUE_LIMIT", "BUILD_REQUEUE_LIMIT"),
+			Value:   3,
+		},
 		&cli.IntFlag{
 			Name:    "storage.file-size-limit",
 			Usage:   "maximum file size (in MB) for a single file upload. 0 means no limit.",
@@ -102,6 +131,11 @@ func flags() []cli.Flag {
 			Sources: cli.EnvVars("WORKER_SERVER_SECRET", "VELA_SERVER_SECRET", "SERVER_SECRET"),
 			Value:   "",
 		},
+		&cli.StringFlag{
+			Name:    "server.signing-key",
+			Usage:   "key the server signs tokens with (VELA_SERVER_PRIVATE_KEY) for verifying registration tokens - tokens are verified with the server when empty",
+			Sources: cli.EnvVars("WORKER_SERVER_SIGNING_KEY", "VELA_SERVER_SIGNING_KEY", "SERVER_SIGNING_KEY"),
+		},
 		&cli.StringFlag{
 			Name:    "server.cert",
 			Usage:   "optional TLS certificate for https",
@@ -112,12 +146,49 @@ func flags() []cli.Flag {
 			Usage:   "optional TLS certificate key",
 			Sources: cli.EnvVars("WORKER_SERVER_CERT_KEY", "VELA_SERVER_CERT_KEY", "SERVER_CERT_KEY"),
 		},
+		&cli.StringFlag{
+			Name:    "server.client-ca",
+			Usage:   "optional CA bundle for verifying client certificates - the worker API requires a verified client certificate when set",
+			Sources: cli.EnvVars("WORKER_SERVER_CLIENT_CA", "VELA_SERVER_CLIENT_CA", "SERVER_CLIENT_CA"),
+		},
 		&cli.StringFlag{
 			Name:    "server.tls-min-version",
 			Usage:   "optional TLS minimum version requirement",
 			Sources: cli.EnvVars("WORKER_SERVER_TLS_MIN_VERSION", "VELA_SERVER_TLS_MIN_VERSION", "SERVER_TLS_MIN_VERSION"),
 			Value:   "1.2",
 		},
+
+		// Standalone Flags
+
+		&cli.BoolFlag{
+			Name:    "standalone",
+			Usage:   "run builds submitted to the worker API instead of the server queue (requests authenticate with the server secret)",
+			Sources: cli.EnvVars("WORKER_STANDALONE", "VELA_STANDALONE"),
+		},
+		&cli.StringFlag{
+			Name:    "standalone.report-dir",
+			Usage:   "directory to write the results of builds to in standalone mode (results are discarded when empty)",
+			Sources: cli.EnvVars("WORKER_STANDALONE_REPORT_DIR", "VELA_STANDALONE_REPORT_DIR"),
+		},
+
+		// Tracing Flags
+
+		&cli.BoolFlag{
+			Name:    "tracing.enable",
+			Usage:   "enable exporting OpenTelemetry traces for the builds executed by the worker",
+			Sources: cli.EnvVars("WORKER_TRACING_ENABLE", "VELA_TRACING_ENABLE"),
+		},
+		&cli.StringFlag{
+			Name:    "tracing.endpoint",
+			Usage:   "OTLP HTTP endpoint to export traces to (the OTEL_EXPORTER_OTLP_* environment variables are used when empty)",
+			Sources: cli.EnvVars("WORKER_TRACING_ENDPOINT", "VELA_TRACING_ENDPOINT"),
+		},
+		&cli.Float64Flag{
+			Name:    "tracing.sample-ratio",
+			Usage:   "ratio of builds to sample for tracing between 0 and 1",
+			Sources: cli.EnvVars("WORKER_TRACING_SAMPLE_RATIO", "VELA_TRACING_SAMPLE_RATIO"),
+			Value:   1,
+		},
 	}
 
 	// Executor Flags
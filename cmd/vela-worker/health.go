@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/go-vela/worker/internal/health"
+	"github.com/go-vela/worker/runtime"
+)
+
+// health is a helper function to create the checks
+// for the liveness and readiness of the Worker.
+//
+// The liveness checks only verify the operator for the worker
+// is still running since restarting the worker does not help
+// it recover from losing access to its dependencies.
+func (w *Worker) health() *health.Checker {
+	h := health.New(health.DefaultTimeout)
+
+	h.AddLive("operator", w.operatorCheck())
+
+	h.AddLive("executors", w.executorsCheck())
+
+	h.AddReady("draining", func(context.Context) error {
+		if w.isDraining() {
+			return errors.New("worker is draining")
+		}
+
+		return nil
+	})
+
+	h.AddReady("runtime", w.runtimeCheck())
+
+	// report the usage of the executors without failing
+	// the readiness check when the worker is busy
+	h.AddInfo("executors", func() any {
+		return executorsInfo{
+			Running: w.runningBuildCount(),
+			Limit:   w.buildLimit(),
+		}
+	})
+
+	// the queue and server are not used in standalone mode
+	if w.Config.Standalone.Enabled {
+		return h
+	}
+
+	h.AddReady("queue", func(context.Context) error {
+		if w.Queue == nil {
+			return errors.New("queue is not configured")
+		}
+
+		if !w.QueueCheckedIn {
+			return errors.New("worker is not checked in with the queue")
+		}
+
+		return nil
+	})
+
+	h.AddReady("server", func(context.Context) error {
+		if !w.CheckedIn {
+			return errors.New("worker is not checked in with the server")
+		}
+
+		return nil
+	})
+
+	return h
+}
+
+// executorsInfo represents the usage of the executors for the Worker.
+type executorsInfo struct {
+	Running int   `json:"running"`
+	Limit   int32 `json:"limit"`
+}
+
+// operatorCheck is a helper function to create the check
+// for verifying the operator for the Worker is running.
+func (w *Worker) operatorCheck() health.CheckFunc {
+	return func(context.Context) error {
+		select {
+		case <-w.operatorDone:
+			return errors.New("worker operator stopped")
+		default:
+			return nil
+		}
+	}
+}
+
+// executorsCheck is a helper function to create the check for verifying
+// an executor is running for every slot below the build limit. Executors
+// stop when the worker is draining, so the check only applies otherwise.
+func (w *Worker) executorsCheck() health.CheckFunc {
+	return func(context.Context) error {
+		if w.isDraining() {
+			return nil
+		}
+
+		w.limitMutex.Lock()
+		defer w.limitMutex.Unlock()
+
+		// executors are not started until the worker is registered
+		if w.slots == nil {
+			return nil
+		}
+
+		if w.executorLoops < int(w.Config.Build.Limit) {
+			return fmt.Errorf("%d of %d executors running", w.executorLoops, w.Config.Build.Limit)
+		}
+
+		return nil
+	}
+}
+
+// runtimeCheck is a helper function to create the check for
+// verifying the runtime environment is reachable by the Worker.
+//
+// The runtime for the check is created once with the connections
+// shared by the pool and reused for every check.
+func (w *Worker) runtimeCheck() health.CheckFunc {
+	var (
+		mutex    sync.Mutex
+		_runtime runtime.Engine
+	)
+
+	return func(ctx context.Context) error {
+		mutex.Lock()
+
+		if _runtime == nil {
+			setup := *w.Config.Runtime
+			setup.Logger = logrus.NewEntry(logrus.StandardLogger())
+			setup.Mock = w.Config.Mock
+
+			// https://pkg.go.dev/github.com/go-vela/worker/runtime#Pool.New
+			r, err := w.Runtimes.New(&setup)
+			if err != nil {
+				mutex.Unlock()
+
+				return fmt.Errorf("unable to setup runtime: %w", err)
+			}
+
+			_runtime = r
+		}
+
+		r := _runtime
+
+		mutex.Unlock()
+
+		return r.Ping(ctx)
+	}
+}
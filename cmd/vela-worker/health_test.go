@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWorker_operatorCheck(t *testing.T) {
+	// setup types
+	done := make(chan struct{})
+	close(done)
+
+	// setup tests
+	tests := []struct {
+		name    string
+		failure bool
+		done    chan struct{}
+	}{
+		{
+			name:    "running",
+			failure: false,
+			done:    make(chan struct{}),
+		},
+		{
+			name:    "stopped",
+			failure: true,
+			done:    done,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := &Worker{operatorDone: test.done}
+
+			err := w.operatorCheck()(context.Background())
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("operatorCheck should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("operatorCheck returned err: %v", err)
+			}
+		})
+	}
+}
+
+func TestWorker_executorsCheck(t *testing.T) {
+	// setup types
+	draining := make(chan struct{})
+	close(draining)
+
+	// setup tests
+	tests := []struct {
+		name     string
+		failure  bool
+		slots    map[int]bool
+		loops    int
+		draining chan struct{}
+	}{
+		{
+			name:     "running",
+			failure:  false,
+			slots:    map[int]bool{0: true, 1: true},
+			loops:    2,
+			draining: make(chan struct{}),
+		},
+		{
+			name:     "not started",
+			failure:  false,
+			slots:    nil,
+			loops:    0,
+			draining: make(chan struct{}),
+		},
+		{
+			name:     "stopped",
+			failure:  true,
+			slots:    map[int]bool{0: true, 1: true},
+			loops:    1,
+			draining: make(chan struct{}),
+		},
+		{
+			name:     "draining",
+			failure:  false,
+			slots:    map[int]bool{0: true, 1: true},
+			loops:    0,
+			draining: draining,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := &Worker{
+				Config:        &Config{Build: &Build{Limit: 2}},
+				Draining:      test.draining,
+				slots:         test.slots,
+				executorLoops: test.loops,
+			}
+
+			err := w.executorsCheck()(context.Background())
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("executorsCheck should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("executorsCheck returned err: %v", err)
+			}
+		})
+	}
+}
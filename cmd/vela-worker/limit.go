@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+
+	api "github.com/go-vela/server/api/types"
+)
+
+// buildLimit is a helper function to return the
+// number of builds the worker runs concurrently.
+func (w *Worker) buildLimit() int32 {
+	w.limitMutex.Lock()
+	defer w.limitMutex.Unlock()
+
+	return w.Config.Build.Limit
+}
+
+// setBuildLimit is a helper function to update the
+// number of builds the worker runs concurrently.
+func (w *Worker) setBuildLimit(limit int32) error {
+	if limit <= 0 {
+		return fmt.Errorf("invalid build limit provided: %d", limit)
+	}
+
+	w.limitMutex.Lock()
+	defer w.limitMutex.Unlock()
+
+	w.Config.Build.Limit = limit
+
+	return nil
+}
+
+// requestBuildLimit is a helper function to signal the operator
+// to resize the executors without blocking the caller.
+//
+// The request is dropped if another one is pending since
+// the next check-in with the server will request it again.
+func (w *Worker) requestBuildLimit(limit int32) {
+	select {
+	case w.BuildLimit <- limit:
+	default:
+	}
+}
+
+// spawnExecutors is a helper function to start an executor
+// for every slot below the build limit that is not running.
+func (w *Worker) spawnExecutors(executors *errgroup.Group, run func(id int) error) {
+	w.limitMutex.Lock()
+	defer w.limitMutex.Unlock()
+
+	if w.slots == nil {
+		w.slots = make(map[int]bool)
+	}
+
+	for id := range int(w.Config.Build.Limit) {
+		// skip slots with an executor already running
+		if w.slots[id] {
+			continue
+		}
+
+		w.slots[id] = true
+		w.executorLoops++
+
+		// log a message indicating the start of an operator thread
+		//
+		// https://pkg.go.dev/github.com/sirupsen/logrus#Info
+		logrus.Infof("thread ID %d listening to queue...", id)
+
+		// spawn errgroup routine for operator subprocess
+		//
+		// https://pkg.go.dev/golang.org/x/sync/errgroup#Group.Go
+		executors.Go(func() error {
+			defer w.stopExecutor()
+
+			return run(id)
+		})
+	}
+}
+
+// stopExecutor is a helper function to record
+// an executor stopped running for any reason.
+func (w *Worker) stopExecutor() {
+	w.limitMutex.Lock()
+	defer w.limitMutex.Unlock()
+
+	w.executorLoops--
+}
+
+// retireExecutor is a helper function to determine if the slot for an
+// executor is above the build limit and release it if so. Executors
+// check this between builds so running builds are never interrupted.
+func (w *Worker) retireExecutor(id int) bool {
+	w.limitMutex.Lock()
+	defer w.limitMutex.Unlock()
+
+	if id < int(w.Config.Build.Limit) {
+		return false
+	}
+
+	delete(w.slots, id)
+
+	return true
+}
+
+// resizeExecutors is a helper function to apply a new build limit
+// to the executors and report the new capacity to the server.
+func (w *Worker) resizeExecutors(ctx context.Context, executors *errgroup.Group, registryWorker *api.Worker, limit int32, run func(id int) error) {
+	current := w.buildLimit()
+	if limit == current {
+		return
+	}
+
+	err := w.setBuildLimit(limit)
+	if err != nil {
+		logrus.Errorf("unable to update build limit: %v", err)
+
+		return
+	}
+
+	logrus.Infof("updating build limit from %d to %d", current, limit)
+
+	// start the executors for the new slots while
+	// executors above the limit retire on their own
+	w.spawnExecutors(executors, run)
+
+	// keep the new limit until it is reported to the server, so
+	// a check in before then doesn't revert it to the old limit
+	w.registryMutex.Lock()
+
+	registryWorker.SetBuildLimit(limit)
+
+	w.limitPending = true
+
+	w.registryMutex.Unlock()
+
+	// report the new capacity on the next check in if the worker isn't checked in
+	if w.VelaClient == nil || !w.CheckedIn {
+		return
+	}
+
+	w.refreshWorkerStatus(ctx, registryWorker)
+}
+
+// watchBuildLimit is a helper function to resize the executors
+// when the build limit changes until the context is done.
+func (w *Worker) watchBuildLimit(ctx context.Context, executors *errgroup.Group, registryWorker *api.Worker, run func(id int) error) {
+	for {
+		select {
+		case <-ctx.Done():
+			logrus.Info("completed looping on worker build limit")
+
+			return
+		case limit := <-w.BuildLimit:
+			w.resizeExecutors(ctx, executors, registryWorker, limit, run)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/sync/errgroup"
+
+	api "github.com/go-vela/server/api/types"
+)
+
+// limitServer is a fake server recording the
+// build limits reported for the worker.
+type limitServer struct {
+	mutex sync.Mutex
+
+	limit  int32
+	limits []int32
+}
+
+func (s *limitServer) handler() http.Handler {
+	gin.SetMode(gin.TestMode)
+
+	e := gin.New()
+
+	e.GET("/api/v1/workers/:worker", func(c *gin.Context) {
+		worker := new(api.Worker)
+		worker.SetHostname(c.Param("worker"))
+		worker.SetBuildLimit(s.limit)
+
+		c.JSON(http.StatusOK, worker)
+	})
+
+	e.POST("/api/v1/workers/:worker/refresh", func(c *gin.Context) {
+		token := new(api.Token)
+		token.SetToken("workerToken")
+
+		c.JSON(http.StatusOK, token)
+	})
+
+	e.PUT("/api/v1/workers/:worker", func(c *gin.Context) {
+		worker := new(api.Worker)
+
+		err := c.Bind(worker)
+		if err != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+
+			return
+		}
+
+		s.mutex.Lock()
+		s.limits = append(s.limits, worker.GetBuildLimit())
+		s.mutex.Unlock()
+
+		c.JSON(http.StatusOK, worker)
+	})
+
+	return e
+}
+
+func TestWorker_resizeExecutors(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		current int32
+		limit   int32
+		want    int32
+		spawned []int
+		retired []int
+		pushed  []int32
+	}{
+		{
+			name:    "grow",
+			current: 2,
+			limit:   4,
+			want:    4,
+			spawned: []int{2, 3},
+			pushed:  []int32{4},
+		},
+		{
+			name:    "shrink",
+			current: 4,
+			limit:   1,
+			want:    1,
+			retired: []int{1, 2, 3},
+			pushed:  []int32{1},
+		},
+		{
+			name:    "unchanged",
+			current: 2,
+			limit:   2,
+			want:    2,
+		},
+		{
+			name:    "invalid limit",
+			current: 2,
+			limit:   0,
+			want:    2,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := new(limitServer)
+
+			s := httptest.NewServer(fake.handler())
+			defer s.Close()
+
+			client, err := setupClient(&Server{Address: s.URL}, "superSecretToken")
+			if err != nil {
+				t.Errorf("unable to create Vela API client: %v", err)
+			}
+
+			w := &Worker{
+				Config: &Config{
+					Build: &Build{Limit: test.current},
+				},
+				CheckedIn:  true,
+				VelaClient: client,
+				slots:      make(map[int]bool),
+			}
+
+			// the executors for the current limit are running
+			for id := range int(test.current) {
+				w.slots[id] = true
+			}
+
+			registryWorker := new(api.Worker)
+			registryWorker.SetHostname("localhost")
+			registryWorker.SetBuildLimit(test.current)
+
+			var (
+				mutex   sync.Mutex
+				spawned []int
+			)
+
+			run := func(id int) error {
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				spawned = append(spawned, id)
+
+				return nil
+			}
+
+			executors := new(errgroup.Group)
+
+			w.resizeExecutors(context.Background(), executors, registryWorker, test.limit, run)
+
+			err = executors.Wait()
+			if err != nil {
+				t.Errorf("resizeExecutors returned err: %v", err)
+			}
+
+			if got := w.buildLimit(); got != test.want {
+				t.Errorf("resizeExecutors limit is %d, want %d", got, test.want)
+			}
+
+			if got := registryWorker.GetBuildLimit(); got != test.want {
+				t.Errorf("resizeExecutors registry limit is %d, want %d", got, test.want)
+			}
+
+			slices.Sort(spawned)
+
+			if !slices.Equal(spawned, test.spawned) {
+				t.Errorf("resizeExecutors spawned %v, want %v", spawned, test.spawned)
+			}
+
+			// executors above the new limit retire between builds
+			var retired []int
+
+			for id := range int(test.current) {
+				if w.retireExecutor(id) {
+					retired = append(retired, id)
+				}
+			}
+
+			if !slices.Equal(retired, test.retired) {
+				t.Errorf("resizeExecutors retired %v, want %v", retired, test.retired)
+			}
+
+			// the new limit is reported to the server right away
+			if !slices.Equal(fake.limits, test.pushed) {
+				t.Errorf("resizeExecutors reported %v, want %v", fake.limits, test.pushed)
+			}
+
+			if w.limitPending {
+				t.Errorf("resizeExecutors limit is pending after it was reported")
+			}
+		})
+	}
+}
+
+func TestWorker_checkIn_BuildLimit(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name      string
+		local     int32
+		server    int32
+		pending   bool
+		checkedIn bool
+		want      int32
+		requested bool
+	}{
+		{
+			name:      "changed on the server",
+			local:     2,
+			server:    3,
+			checkedIn: true,
+			want:      3,
+			requested: true,
+		},
+		{
+			name:      "changed through the worker API",
+			local:     4,
+			server:    2,
+			pending:   true,
+			checkedIn: true,
+			want:      4,
+		},
+		{
+			name:   "first check in",
+			local:  2,
+			server: 3,
+			want:   2,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := &limitServer{limit: test.server}
+
+			s := httptest.NewServer(fake.handler())
+			defer s.Close()
+
+			client, err := setupClient(&Server{Address: s.URL}, "superSecretToken")
+			if err != nil {
+				t.Errorf("unable to create Vela API client: %v", err)
+			}
+
+			w := &Worker{
+				Config: &Config{
+					API:   &API{Address: &url.URL{Host: "localhost"}},
+					Build: &Build{Limit: test.local},
+				},
+				BuildLimit:   make(chan int32, 1),
+				CheckedIn:    test.checkedIn,
+				VelaClient:   client,
+				limitPending: test.pending,
+			}
+
+			registryWorker := new(api.Worker)
+			registryWorker.SetHostname("localhost")
+			registryWorker.SetBuildLimit(test.local)
+
+			_, _, err = w.checkIn(context.Background(), registryWorker)
+			if err != nil {
+				t.Errorf("checkIn returned err: %v", err)
+			}
+
+			if got := registryWorker.GetBuildLimit(); got != test.want {
+				t.Errorf("checkIn limit is %d, want %d", got, test.want)
+			}
+
+			if got := len(w.BuildLimit) > 0; got != test.requested {
+				t.Errorf("checkIn requested build limit is %v, want %v", got, test.requested)
+			}
+
+			// the limit kept by the worker is reported to the server
+			if !slices.Equal(fake.limits, []int32{test.want}) {
+				t.Errorf("checkIn reported %v, want %v", fake.limits, []int32{test.want})
+			}
+
+			if w.limitPending {
+				t.Errorf("checkIn limit is pending after it was reported")
+			}
+		})
+	}
+}
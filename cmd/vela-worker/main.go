@@ -48,6 +48,7 @@ func main() {
 	cmd := cli.Command{
 		Name:    "vela-worker",
 		Version: v.Semantic(),
+		Before:  loadConfigFile,
 		Action:  run,
 		Usage:   "Vela build daemon designed for executing pipelines",
 	}
@@ -20,6 +20,11 @@ import (
 //
 //nolint:funlen // refactor candidate
 func (w *Worker) operate(ctx context.Context) error {
+	// run the builds submitted to the worker API without a server
+	if w.Config.Standalone.Enabled {
+		return w.operateStandalone(ctx)
+	}
+
 	var err error
 	// create the errgroup for managing operator subprocesses
 	//
@@ -31,17 +36,25 @@ func (w *Worker) operate(ctx context.Context) error {
 	registryWorker.SetHostname(w.Config.API.Address.Hostname())
 	registryWorker.SetAddress(w.Config.API.Address.String())
 	registryWorker.SetActive(true)
-	registryWorker.SetBuildLimit(w.Config.Build.Limit)
+	registryWorker.SetBuildLimit(w.buildLimit())
 
 	// set routes from config if set or defaulted to `vela`
-	if (len(w.Config.Queue.Routes) > 0) && (w.Config.Queue.Routes[0] != "NONE" && w.Config.Queue.Routes[0] != "") {
-		registryWorker.SetRoutes(w.Config.Queue.Routes)
+	if routes := w.settings().queueRoutes(); len(routes) > 0 {
+		registryWorker.SetRoutes(routes)
 	}
 
 	// pull registration token from configuration if provided; wait if not
 	logrus.Trace("waiting for register token")
 
-	token := <-w.RegisterToken
+	var token string
+
+	select {
+	case <-ctx.Done():
+		logrus.Info("worker stopped before receiving register token")
+
+		return nil
+	case token = <-w.RegisterToken:
+	}
 
 	logrus.Trace("received register token")
 	logrus.Trace("setting up vela client")
@@ -77,6 +90,27 @@ func (w *Worker) operate(ctx context.Context) error {
 		w.updateWorkerStatus(ctx, registryWorker, constants.WorkerStatusError)
 	}
 
+	// clean up builds interrupted by a crash or restart of the worker
+	w.reconcile(ctx)
+
+	// upload the logs left on disk by builds before the restart
+	w.recoverLogs(ctx)
+
+	// setup the janitor for removing orphaned runtime resources
+	janitor, err := w.janitor()
+	if err != nil {
+		logrus.Errorf("unable to setup runtime janitor: %v", err)
+	}
+
+	// spawn goroutine for removing orphaned runtime resources
+	if janitor != nil {
+		executors.Go(func() error {
+			janitor.Run(gctx)
+
+			return nil
+		})
+	}
+
 	// spawn goroutine for phoning home
 	executors.Go(func() error {
 		// five second ticker for signal handling
@@ -91,7 +125,19 @@ func (w *Worker) operate(ctx context.Context) error {
 			case <-gctx.Done():
 				logrus.Info("completed looping on worker registration")
 				return nil
+			case <-w.reloaded:
+				// check in right away to report the reloaded routes
+				timer = time.After(0)
+
+				continue
 			case <-timer:
+				// report the routes reloaded from the configuration file
+				if routes := w.settings().queueRoutes(); len(routes) > 0 {
+					w.registryMutex.Lock()
+					registryWorker.SetRoutes(routes)
+					w.registryMutex.Unlock()
+				}
+
 				// check in attempt loop
 				for {
 					// register or update the worker
@@ -152,7 +198,7 @@ func (w *Worker) operate(ctx context.Context) error {
 				}
 
 				// set timer to next check in
-				timer = time.After(w.Config.CheckIn)
+				timer = time.After(w.settings().CheckIn)
 			}
 
 			// five second ticker
@@ -160,86 +206,121 @@ func (w *Worker) operate(ctx context.Context) error {
 		}
 	})
 
-	// iterate till the configured build limit
-	for i := 0; i < int(w.Config.Build.Limit); i++ {
-		// evaluate and capture i at each iteration
-		//
-		// https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
-		id := i
-
-		// log a message indicating the start of an operator thread
-		//
-		// https://pkg.go.dev/github.com/sirupsen/logrus#Info
-		logrus.Infof("thread ID %d listening to queue...", id)
-
-		// spawn errgroup routine for operator subprocess
-		//
-		// https://pkg.go.dev/golang.org/x/sync/errgroup#Group.Go
-		executors.Go(func() error {
-			// create an infinite loop to poll for builds
-			for {
-				// do not pull from queue unless worker is checked in with server
-				if !w.CheckedIn {
-					time.Sleep(5 * time.Second)
-					logrus.Info("worker not checked in, skipping queue read")
-
-					continue
-				}
-				// do not pull from queue unless queue setup is done and connected
-				if !w.QueueCheckedIn {
-					time.Sleep(5 * time.Second)
-					logrus.Info("queue ping failed, skipping queue read")
-
-					continue
-				}
+	// spawn goroutine for reporting the draining status
+	executors.Go(func() error {
+		select {
+		case <-gctx.Done():
+			return nil
+		case <-w.Draining:
+			logrus.Info("worker draining, updating status with the server")
 
-				select {
-				case <-gctx.Done():
-					logrus.WithFields(logrus.Fields{
-						"id": id,
-					}).Info("completed looping on worker executor")
-
-					return nil
-				default:
-					logrus.WithFields(logrus.Fields{
-						"id": id,
-					}).Info("running worker executor exec")
-
-					// exec operator subprocess to poll and execute builds
-					// (pass background context to avoid errors in one
-					// executor+build inadvertently canceling other builds)
-					//
-					//nolint:contextcheck // see above
-					err = w.exec(context.Background(), id, registryWorker)
-					if err != nil {
-						// log the error received from the executor
-						//
-						// https://pkg.go.dev/github.com/sirupsen/logrus#Errorf
-						logrus.Errorf("failing worker executor: %v", err)
-						registryWorker.SetStatus(constants.WorkerStatusError)
-						_, resp, logErr := w.VelaClient.Worker.Update(ctx, registryWorker.GetHostname(), registryWorker)
-
-						if resp == nil {
-							// log the error instead of returning so the operation doesn't block worker deployment
-							logrus.Error("status update response is nil")
-						}
+			// set to draining so the server stops routing builds to the worker
+			w.updateWorkerStatus(gctx, registryWorker, workerStatusDraining)
 
-						if logErr != nil {
-							if resp != nil {
-								// log the error instead of returning so the operation doesn't block worker deployment
-								logrus.Errorf("status code: %v, unable to update worker %s status with the server: %v", resp.StatusCode, registryWorker.GetHostname(), logErr)
-							}
-						}
+			return nil
+		}
+	})
 
-						return err
-					}
-				}
-			}
-		})
+	// run the executor loop for each slot
+	run := func(id int) error {
+		return w.operateExecutor(ctx, gctx, id, registryWorker)
 	}
 
+	// spawn goroutine for resizing the executors when the build limit changes
+	executors.Go(func() error {
+		w.watchBuildLimit(gctx, executors, registryWorker, run)
+
+		return nil
+	})
+
+	// spawn an executor for every slot below the build limit
+	w.spawnExecutors(executors, run)
+
 	// wait for errors from operator subprocesses
 	//
 	// https://pkg.go.dev/golang.org/x/sync/errgroup#Group.Wait
 	return executors.Wait()
 }
+
+// operateExecutor is a helper function to poll the queue and execute
+// builds until the worker stops or the slot for the executor is retired.
+func (w *Worker) operateExecutor(ctx, gctx context.Context, id int, registryWorker *api.Worker) error {
+	// create an infinite loop to poll for builds
+	for {
+		// stop polling once the build limit drops below the slot
+		if w.retireExecutor(id) {
+			logrus.WithFields(logrus.Fields{
+				"id": id,
+			}).Info("build limit decreased, completed looping on worker executor")
+
+			return nil
+		}
+
+		// do not pull from queue once the worker is draining
+		if w.isDraining() {
+			logrus.WithFields(logrus.Fields{
+				"id": id,
+			}).Info("worker draining, completed looping on worker executor")
+
+			return nil
+		}
+
+		// do not pull from queue unless worker is checked in with server
+		if !w.CheckedIn {
+			time.Sleep(5 * time.Second)
+			logrus.Info("worker not checked in, skipping queue read")
+
+			continue
+		}
+		// do not pull from queue unless queue setup is done and connected
+		if !w.QueueCheckedIn {
+			time.Sleep(5 * time.Second)
+			logrus.Info("queue ping failed, skipping queue read")
+
+			continue
+		}
+
+		select {
+		case <-gctx.Done():
+			logrus.WithFields(logrus.Fields{
+				"id": id,
+			}).Info("completed looping on worker executor")
+
+			return nil
+		default:
+			logrus.WithFields(logrus.Fields{
+				"id": id,
+			}).Info("running worker executor exec")
+
+			// exec operator subprocess to poll and execute builds
+			// (pass background context to avoid errors in one
+			// executor+build inadvertently canceling other builds)
+			//
+			//nolint:contextcheck // see above
+			err := w.exec(context.Background(), id, registryWorker)
+			if err != nil {
+				// log the error received from the executor
+				//
+				// https://pkg.go.dev/github.com/sirupsen/logrus#Errorf
+				logrus.Errorf("failing worker executor: %v", err)
+				resp, logErr := w.updateWorker(ctx, registryWorker, func(c *api.Worker) {
+					c.SetStatus(constants.WorkerStatusError)
+				})
+
+				if resp == nil {
+					// log the error instead of returning so the operation doesn't block worker deployment
+					logrus.Error("status update response is nil")
+				}
+
+				if logErr != nil {
+					if resp != nil {
+						// log the error instead of returning so the operation doesn't block worker deployment
+						logrus.Errorf("status code: %v, unable to update worker %s status with the server: %v", resp.StatusCode, registryWorker.GetHostname(), logErr)
+					}
+				}
+
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,298 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/go-vela/sdk-go/vela"
+	api "github.com/go-vela/server/api/types"
+	"github.com/go-vela/server/compiler/types/pipeline"
+	"github.com/go-vela/server/constants"
+	"github.com/go-vela/server/queue/models"
+	"github.com/go-vela/worker/internal/journal"
+	"github.com/go-vela/worker/internal/spooler"
+	"github.com/go-vela/worker/runtime/docker"
+)
+
+// reconcile is a helper function to clean up the runtime
+// resources and report the builds recorded in the journal
+// that were interrupted by a crash or restart of the worker.
+func (w *Worker) reconcile(ctx context.Context) {
+	entries, err := w.Journal.List()
+	if err != nil {
+		logrus.Errorf("unable to read build journal: %v", err)
+	}
+
+	for _, entry := range entries {
+		// create logger with extra metadata
+		//
+		// https://pkg.go.dev/github.com/sirupsen/logrus#WithFields
+		logger := logrus.WithFields(logrus.Fields{
+			"build":   entry.Number,
+			"host":    w.Config.API.Address.Hostname(),
+			"repo":    fmt.Sprintf("%s/%s", entry.Org, entry.Repo),
+			"runtime": entry.Runtime,
+		})
+
+		logger.Warn("reconciling build interrupted by worker restart")
+
+		// setup the runtime the build was executed with
+		setup := *w.Config.Runtime
+		setup.Logger = logger
+		setup.Mock = w.Config.Mock
+
+		if len(entry.Runtime) > 0 {
+			setup.Driver = entry.Runtime
+		}
+
+		// https://pkg.go.dev/github.com/go-vela/worker/runtime#Pool.New
+		_runtime, err := w.Runtimes.New(&setup)
+		if err != nil {
+			logger.Errorf("unable to setup runtime to reconcile build: %v", err)
+
+			continue
+		}
+
+		logger.Info("removing leftover runtime resources")
+		// remove the runtime resources left behind for the build
+		err = _runtime.ReconcileBuild(ctx, journalPipeline(entry))
+		if err != nil {
+			// keep the entry to retry on the next start
+			logger.Errorf("unable to remove leftover runtime resources: %v", err)
+
+			continue
+		}
+
+		logger.Info("reporting interrupted build")
+		// report the build as errored to the server
+		err = w.reportInterrupted(ctx, entry)
+		if err != nil {
+			logger.Errorf("unable to report interrupted build: %v", err)
+		}
+
+		err = w.Journal.Remove(entry.BuildID)
+		if err != nil {
+			logger.Errorf("unable to remove build from journal: %v", err)
+		}
+	}
+}
+
+// reportInterrupted is a helper function to report a build
+// interrupted by a crash or restart of the worker as errored.
+func (w *Worker) reportInterrupted(ctx context.Context, entry *journal.Entry) error {
+	clients, errs := w.buildClients(ctx, entry.Org, entry.Repo, entry.Number)
+
+	for _, client := range clients {
+		// send API call to capture the build
+		//
+		// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#BuildService.Get
+		build, _, err := client.Build.Get(ctx, entry.Org, entry.Repo, entry.Number)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		// skip builds that already completed
+		switch build.GetStatus() {
+		case constants.StatusPending, constants.StatusRunning:
+		default:
+			return nil
+		}
+
+		build.SetStatus(constants.StatusError)
+		build.SetError(fmt.Sprintf("build interrupted by a crash or restart of worker %s", w.Config.API.Address.Hostname()))
+		build.SetFinished(time.Now().UTC().Unix())
+
+		// send API call to update the build
+		//
+		// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#BuildService.Update
+		_, _, err = client.Build.Update(ctx, build)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		return nil
+	}
+
+	if len(errs) == 0 {
+		return errors.New("no client available to report build")
+	}
+
+	return errors.Join(errs...)
+}
+
+// buildClients is a helper function to create the clients
+// for updating a build recorded before the worker restarted.
+// The client with a token for the build is preferred since
+// it has access to the build.
+func (w *Worker) buildClients(ctx context.Context, org, repo string, number int64) ([]*vela.Client, []error) {
+	clients := []*vela.Client{}
+
+	var errs []error
+
+	if w.VelaClient == nil {
+		return clients, errs
+	}
+
+	// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#BuildService.GetBuildToken
+	bt, _, err := w.VelaClient.Build.GetBuildToken(ctx, org, repo, number)
+	if err == nil {
+		client, err := setupClient(w.Config.Server, bt.GetToken())
+		if err == nil {
+			clients = append(clients, client)
+		}
+	} else {
+		errs = append(errs, fmt.Errorf("unable to retrieve build token: %w", err))
+	}
+
+	return append(clients, w.VelaClient), errs
+}
+
+// recoverLogs is a helper function to upload the logs
+// spilled to disk by builds that were unable to upload
+// them before the worker stopped.
+func (w *Worker) recoverLogs(ctx context.Context) {
+	// https://pkg.go.dev/github.com/go-vela/worker/internal/spooler#Recover
+	err := spooler.Recover(ctx, w.Config.Executor.LogSpoolDir, w.uploadLog)
+	if err != nil {
+		logrus.Errorf("unable to upload spilled logs: %v", err)
+	}
+}
+
+// uploadLog is a helper function to upload the
+// contents of a spilled log for a container.
+func (w *Worker) uploadLog(ctx context.Context, key string, data []byte) error {
+	k, err := spooler.ParseKey(key)
+	if err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"build": k.Build,
+		"repo":  fmt.Sprintf("%s/%s", k.Org, k.Repo),
+	}).Infof("uploading spilled logs for %s %d", k.Kind, k.Number)
+
+	_log := new(api.Log)
+	_log.SetData(data)
+
+	clients, errs := w.buildClients(ctx, k.Org, k.Repo, k.Build)
+
+	for _, client := range clients {
+		switch k.Kind {
+		case spooler.KindService:
+			// send API call to update the logs for the service
+			//
+			// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#LogService.UpdateService
+			_, err = client.Log.UpdateService(ctx, k.Org, k.Repo, k.Build, k.Number, _log)
+		default:
+			// send API call to update the logs for the step
+			//
+			// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#LogService.UpdateStep
+			_, err = client.Log.UpdateStep(ctx, k.Org, k.Repo, k.Build, k.Number, _log)
+		}
+
+		if err == nil {
+			return nil
+		}
+
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return errors.New("no client available to upload logs")
+	}
+
+	return errors.Join(errs...)
+}
+
+// janitor is a helper function to setup the janitor for removing the
+// runtime resources left behind by builds no longer running on the worker.
+func (w *Worker) janitor() (*docker.Janitor, error) {
+	setup := *w.Config.Runtime
+	setup.Logger = logrus.WithField("host", w.Config.API.Address.Hostname())
+	setup.Mock = w.Config.Mock
+	setup.Hostname = w.Config.API.Address.Hostname()
+
+	// https://pkg.go.dev/github.com/go-vela/worker/runtime#Setup.Janitor
+	return setup.Janitor(w.runningPipelines)
+}
+
+// runningPipelines is a helper function to capture the
+// pipeline IDs for the builds running on the worker.
+func (w *Worker) runningPipelines() []string {
+	w.RunningBuildsMutex.Lock()
+	defer w.RunningBuildsMutex.Unlock()
+
+	ids := make([]string, 0, len(w.RunningBuilds))
+
+	for _, b := range w.RunningBuilds {
+		// prepare pipeline to capture the ID based on build information
+		p := new(pipeline.Build)
+		p.Prepare(b.GetRepo().GetOrg(), b.GetRepo().GetName(), b.GetNumber(), false)
+
+		ids = append(ids, p.ID)
+	}
+
+	return ids
+}
+
+// journalEntry is a helper function to create the
+// entry recorded in the journal for a build.
+func (w *Worker) journalEntry(item *models.Item, p *pipeline.Build, outputs *pipeline.Container) *journal.Entry {
+	entry := &journal.Entry{
+		BuildID:    item.Build.GetID(),
+		Org:        item.Build.GetRepo().GetOrg(),
+		Repo:       item.Build.GetRepo().GetName(),
+		Number:     item.Build.GetNumber(),
+		PipelineID: p.ID,
+		Runtime:    w.Config.Runtime.Driver,
+		Started:    time.Now().Unix(),
+	}
+
+	// capture all containers for the pipeline
+	containers := append(pipeline.ContainerSlice{}, p.Services...)
+	containers = append(containers, p.Steps...)
+
+	for _, stage := range p.Stages {
+		containers = append(containers, stage.Steps...)
+	}
+
+	for _, secret := range p.Secrets {
+		if !secret.Origin.Empty() {
+			containers = append(containers, secret.Origin)
+		}
+	}
+
+	if outputs != nil && len(outputs.Image) > 0 {
+		containers = append(containers, outputs)
+	}
+
+	for _, ctn := range containers {
+		entry.Containers = append(entry.Containers, ctn.ID)
+	}
+
+	return entry
+}
+
+// journalPipeline is a helper function to create the pipeline
+// with the runtime resources recorded in the journal for a build.
+func journalPipeline(entry *journal.Entry) *pipeline.Build {
+	p := &pipeline.Build{
+		ID: entry.PipelineID,
+	}
+
+	for _, id := range entry.Containers {
+		p.Steps = append(p.Steps, &pipeline.Container{ID: id})
+	}
+
+	return p
+}
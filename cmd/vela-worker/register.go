@@ -10,6 +10,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/go-vela/sdk-go/vela"
 	api "github.com/go-vela/server/api/types"
 	"github.com/go-vela/server/constants"
 )
@@ -32,7 +33,7 @@ func (w *Worker) checkIn(ctx context.Context, config *api.Worker) (bool, string,
 			time.Sleep(time.Duration(i*10) * time.Second)
 		}
 
-		_, resp, err := w.VelaClient.Worker.Get(ctx, config.GetHostname())
+		existing, resp, err := w.VelaClient.Worker.Get(ctx, config.GetHostname())
 		if err != nil {
 			respErr := fmt.Errorf("unable to retrieve worker %s from the server: %w", config.GetHostname(), err)
 			// if server is down, the worker status will not be updated
@@ -58,6 +59,29 @@ func (w *Worker) checkIn(ctx context.Context, config *api.Worker) (bool, string,
 		// if we were able to GET the worker, update it
 		logrus.Infof("checking worker %s into the server", config.GetHostname())
 
+		// apply a build limit changed on the server after the worker checked in
+		//
+		// the limit from the configuration is kept on the first check in so a
+		// stale limit on the server doesn't override a redeployed worker, and
+		// a limit changed through the worker API wins until the server has it
+		limit := existing.GetBuildLimit()
+
+		w.registryMutex.Lock()
+
+		changed := w.CheckedIn && !w.limitPending && limit > 0 && limit != config.GetBuildLimit()
+		if changed {
+			// keep the new limit when updating the worker below
+			config.SetBuildLimit(limit)
+		}
+
+		w.registryMutex.Unlock()
+
+		if changed {
+			logrus.Infof("build limit for worker %s changed on the server to %d", config.GetHostname(), limit)
+
+			w.requestBuildLimit(limit)
+		}
+
 		tkn, _, err = w.VelaClient.Worker.RefreshAuth(ctx, config.GetHostname())
 		if err != nil {
 			if i < retries-1 {
@@ -73,10 +97,8 @@ func (w *Worker) checkIn(ctx context.Context, config *api.Worker) (bool, string,
 			return false, "", fmt.Errorf("unable to refresh auth for worker %s on the server: %w", config.GetHostname(), err)
 		}
 
-		status := w.getWorkerStatusFromConfig(config)
-
 		// update worker status to Idle when checkIn is successful.
-		w.updateWorkerStatus(ctx, config, status)
+		w.refreshWorkerStatus(ctx, config)
 
 		break
 	}
@@ -88,11 +110,17 @@ func (w *Worker) checkIn(ctx context.Context, config *api.Worker) (bool, string,
 func (w *Worker) register(ctx context.Context, config *api.Worker) (bool, string, error) {
 	logrus.Infof("worker %s not found, registering it with the server", config.GetHostname())
 
+	w.registryMutex.Lock()
+
 	// status Idle will be set for worker upon first time registration
 	// if worker cannot be registered, no status will be set.
 	config.SetStatus(constants.WorkerStatusIdle)
 
-	tkn, _, err := w.VelaClient.Worker.Add(ctx, config)
+	worker := *config
+
+	w.registryMutex.Unlock()
+
+	tkn, _, err := w.VelaClient.Worker.Add(ctx, &worker)
 	if err != nil {
 		// log the error instead of returning so the operation doesn't block worker deployment
 		return false, "", fmt.Errorf("unable to register worker %s with the server: %w", config.GetHostname(), err)
@@ -115,10 +143,8 @@ func (w *Worker) queueCheckIn(ctx context.Context, registryWorker *api.Worker) (
 		return false, pErr
 	}
 
-	status := w.getWorkerStatusFromConfig(registryWorker)
-
 	// update worker status to Idle when setup and ping are good.
-	w.updateWorkerStatus(ctx, registryWorker, status)
+	w.refreshWorkerStatus(ctx, registryWorker)
 
 	return true, nil
 }
@@ -126,8 +152,9 @@ func (w *Worker) queueCheckIn(ctx context.Context, registryWorker *api.Worker) (
 // updateWorkerStatus is a helper function to update worker status
 // logs the error if it can't update status.
 func (w *Worker) updateWorkerStatus(ctx context.Context, config *api.Worker, status string) {
-	config.SetStatus(status)
-	_, resp, logErr := w.VelaClient.Worker.Update(ctx, config.GetHostname(), config)
+	resp, logErr := w.updateWorker(ctx, config, func(c *api.Worker) {
+		c.SetStatus(status)
+	})
 
 	if resp == nil {
 		// log the error instead of returning so the operation doesn't block worker deployment
@@ -148,3 +175,43 @@ func (w *Worker) updateWorkerStatus(ctx context.Context, config *api.Worker, sta
 		}
 	}
 }
+
+// refreshWorkerStatus is a helper function to update the
+// worker status from the builds running on the worker.
+func (w *Worker) refreshWorkerStatus(ctx context.Context, config *api.Worker) {
+	w.registryMutex.Lock()
+	status := w.getWorkerStatusFromConfig(config)
+	w.registryMutex.Unlock()
+
+	w.updateWorkerStatus(ctx, config, status)
+}
+
+// updateWorker is a helper function to apply the changes to the
+// worker and send them to the server. The worker is shared by the
+// operator subprocesses, so the changes are applied while holding
+// the lock and a copy of the worker is sent to the server.
+func (w *Worker) updateWorker(ctx context.Context, config *api.Worker, update func(c *api.Worker)) (*vela.Response, error) {
+	w.registryMutex.Lock()
+
+	update(config)
+
+	worker := *config
+
+	w.registryMutex.Unlock()
+
+	_, resp, err := w.VelaClient.Worker.Update(ctx, worker.GetHostname(), &worker)
+	if err != nil {
+		return resp, err
+	}
+
+	w.registryMutex.Lock()
+	defer w.registryMutex.Unlock()
+
+	// the server has the build limit changed through the worker API
+	// unless it changed again while the worker was being updated
+	if worker.GetBuildLimit() == config.GetBuildLimit() {
+		w.limitPending = false
+	}
+
+	return resp, nil
+}
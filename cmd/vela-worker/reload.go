@@ -0,0 +1,299 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	api "github.com/go-vela/server/api/types"
+	"github.com/go-vela/server/constants"
+)
+
+// reloadable represents the settings from the configuration
+// file that are safe to reload while the worker runs.
+var reloadable = []string{
+	"log.level",
+	"runtime.privileged-images",
+	"runtime.volumes",
+	"queue.routes",
+	"build.timeout",
+	"checkIn",
+}
+
+// settings represents the worker configuration
+// that is safe to reload while the worker runs.
+type settings struct {
+	LogLevel         string
+	PrivilegedImages []string
+	HostVolumes      []string
+	Routes           []string
+	BuildTimeout     time.Duration
+	CheckIn          time.Duration
+}
+
+// settings is a helper function to capture the worker
+// configuration that is safe to reload while the worker runs.
+func (w *Worker) settings() settings {
+	w.configMutex.RLock()
+	defer w.configMutex.RUnlock()
+
+	return settings{
+		LogLevel:         w.Config.Logger.Level,
+		PrivilegedImages: w.Config.Runtime.PrivilegedImages,
+		HostVolumes:      w.Config.Runtime.HostVolumes,
+		Routes:           w.Config.Queue.Routes,
+		BuildTimeout:     w.Config.Build.Timeout,
+		CheckIn:          w.Config.CheckIn,
+	}
+}
+
+// reload is a helper function to reload the registry credentials
+// and the settings that are safe to reload from the configuration file.
+func (w *Worker) reload() error {
+	var errs []error
+
+	// reload the registry credentials so rotated credentials
+	// are used by the builds started after the reload
+	if w.Config.Runtime.Driver == constants.DriverDocker {
+		logrus.Info("reloading registry credentials")
+
+		// https://pkg.go.dev/github.com/go-vela/worker/runtime#Pool.LoadRegistryAuth
+		err := w.Runtimes.LoadRegistryAuth(w.Config.Runtime.DockerConfig, w.Config.Runtime.RegistryCredentials)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to reload registry credentials: %w", err))
+		}
+	}
+
+	// check if a configuration file was provided
+	if w.configFile == nil {
+		return errors.Join(errs...)
+	}
+
+	err := w.reloadConfig()
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// reloadConfig is a helper function to apply the settings that are
+// safe to reload from the configuration file and log what changed.
+func (w *Worker) reloadConfig() error {
+	logrus.Infof("reloading configuration file %s", w.configFile.path)
+
+	values, err := readConfigFile(w.configFile.path)
+	if err != nil {
+		return err
+	}
+
+	err = w.configFile.check(values)
+	if err != nil {
+		return err
+	}
+
+	current := w.settings()
+
+	next, err := current.load(values, w.configFile.overrides)
+	if err != nil {
+		return err
+	}
+
+	changes := current.diff(next)
+	if len(changes) == 0 {
+		logrus.Info("no changes found in configuration file")
+
+		return nil
+	}
+
+	w.configMutex.Lock()
+
+	w.Config.Logger.Level = next.LogLevel
+	w.Config.Runtime.PrivilegedImages = next.PrivilegedImages
+	w.Config.Runtime.HostVolumes = next.HostVolumes
+	w.Config.Queue.Routes = next.Routes
+	w.Config.Build.Timeout = next.BuildTimeout
+	w.Config.CheckIn = next.CheckIn
+
+	w.configMutex.Unlock()
+
+	// the level was validated when loading the settings, and the mode
+	// for the API is only set on startup since it is not safe to change
+	// while the API is serving requests
+	level, _ := logLevel(next.LogLevel)
+
+	logrus.SetLevel(level)
+
+	// signal the worker to check in with the reloaded routes
+	if !slices.Equal(current.Routes, next.Routes) {
+		select {
+		case w.reloaded <- struct{}{}:
+		default:
+		}
+	}
+
+	for _, change := range changes {
+		logrus.Infof("reloaded %s", change)
+	}
+
+	return nil
+}
+
+// load is a helper function to return the settings with the values from
+// the configuration file applied. Settings provided by flags or environment
+// variables and settings missing from the file keep their current value.
+func (s settings) load(values map[string][]string, overrides map[string]bool) (settings, error) {
+	// lookup returns the values from the file for a setting
+	lookup := func(name string) ([]string, bool) {
+		if overrides[name] {
+			return nil, false
+		}
+
+		v, ok := values[name]
+
+		return v, ok && len(v) > 0
+	}
+
+	// duration returns the value from the file for a duration setting
+	duration := func(name string, current time.Duration) (time.Duration, error) {
+		v, ok := lookup(name)
+		if !ok {
+			return current, nil
+		}
+
+		d, err := time.ParseDuration(v[0])
+		if err != nil {
+			return 0, fmt.Errorf("invalid value %q for setting %q: %w", v[0], name, err)
+		}
+
+		if d <= 0 {
+			return 0, fmt.Errorf("invalid value %q for setting %q: must be greater than zero", v[0], name)
+		}
+
+		return d, nil
+	}
+
+	var err error
+
+	if v, ok := lookup("log.level"); ok {
+		_, err = logLevel(v[0])
+		if err != nil {
+			return s, fmt.Errorf("invalid value %q for setting %q: %w", v[0], "log.level", err)
+		}
+
+		s.LogLevel = v[0]
+	}
+
+	if v, ok := lookup("runtime.privileged-images"); ok {
+		s.PrivilegedImages = v
+	}
+
+	if v, ok := lookup("runtime.volumes"); ok {
+		s.HostVolumes = v
+	}
+
+	if v, ok := lookup("queue.routes"); ok {
+		s.Routes = v
+	}
+
+	s.BuildTimeout, err = duration("build.timeout", s.BuildTimeout)
+	if err != nil {
+		return s, err
+	}
+
+	s.CheckIn, err = duration("checkIn", s.CheckIn)
+	if err != nil {
+		return s, err
+	}
+
+	return s, nil
+}
+
+// diff is a helper function to describe
+// the changes between the settings.
+func (s settings) diff(next settings) []string {
+	var changes []string
+
+	if s.LogLevel != next.LogLevel {
+		changes = append(changes, fmt.Sprintf("log.level: %s -> %s", s.LogLevel, next.LogLevel))
+	}
+
+	if !slices.Equal(s.PrivilegedImages, next.PrivilegedImages) {
+		changes = append(changes, fmt.Sprintf("runtime.privileged-images: %v -> %v", s.PrivilegedImages, next.PrivilegedImages))
+	}
+
+	if !slices.Equal(s.HostVolumes, next.HostVolumes) {
+		changes = append(changes, fmt.Sprintf("runtime.volumes: %v -> %v", s.HostVolumes, next.HostVolumes))
+	}
+
+	if !slices.Equal(s.Routes, next.Routes) {
+		changes = append(changes, fmt.Sprintf("queue.routes: %v -> %v", s.Routes, next.Routes))
+	}
+
+	if s.BuildTimeout != next.BuildTimeout {
+		changes = append(changes, fmt.Sprintf("build.timeout: %s -> %s", s.BuildTimeout, next.BuildTimeout))
+	}
+
+	if s.CheckIn != next.CheckIn {
+		changes = append(changes, fmt.Sprintf("checkIn: %s -> %s", s.CheckIn, next.CheckIn))
+	}
+
+	return changes
+}
+
+// queueRoutes is a helper function to return the routes
+// to register with the server or nil for the default routes.
+func (s settings) queueRoutes() []string {
+	if len(s.Routes) == 0 || s.Routes[0] == "NONE" || s.Routes[0] == "" {
+		return nil
+	}
+
+	return s.Routes
+}
+
+// buildTimeout is a helper function to return the maximum time
+// the build can run for, preferring the timeout for the repo.
+func (s settings) buildTimeout(b *api.Build) time.Duration {
+	// check if the repository has a custom timeout
+	if b.GetRepo().GetTimeout() > 0 {
+		return time.Duration(b.GetRepo().GetTimeout()) * time.Minute
+	}
+
+	return s.BuildTimeout
+}
+
+// check is a helper function to reject the changes to the settings in
+// the configuration file that are not safe to reload while the worker
+// runs. Settings provided by flags or environment variables are skipped
+// since the file has no effect on them.
+func (f *configFile) check(values map[string][]string) error {
+	names := slices.Sorted(maps.Keys(f.values))
+
+	for name := range values {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	var errs []error
+
+	for _, name := range names {
+		if slices.Contains(reloadable, name) || f.overrides[name] {
+			continue
+		}
+
+		if !slices.Equal(f.values[name], values[name]) {
+			errs = append(errs, fmt.Errorf("unable to reload setting %q: restart the worker to apply it", name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,285 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-vela/server/queue"
+	"github.com/go-vela/worker/runtime"
+)
+
+func TestWorker_settings_load(t *testing.T) {
+	// setup types
+	current := settings{
+		LogLevel:         "info",
+		PrivilegedImages: []string{"target/vela-docker"},
+		Routes:           []string{"vela"},
+		BuildTimeout:     30 * time.Minute,
+		CheckIn:          15 * time.Minute,
+	}
+
+	// setup tests
+	tests := []struct {
+		name      string
+		failure   bool
+		values    map[string][]string
+		overrides map[string]bool
+		want      settings
+	}{
+		{
+			name:    "reloaded",
+			failure: false,
+			values: map[string][]string{
+				"log.level":                 {"debug"},
+				"runtime.privileged-images": {"target/vela-kaniko"},
+				"queue.routes":              {"vela", "large"},
+				"build.timeout":             {"1h"},
+				"checkIn":                   {"5m"},
+			},
+			want: settings{
+				LogLevel:         "debug",
+				PrivilegedImages: []string{"target/vela-kaniko"},
+				Routes:           []string{"vela", "large"},
+				BuildTimeout:     time.Hour,
+				CheckIn:          5 * time.Minute,
+			},
+		},
+		{
+			name:    "missing settings",
+			failure: false,
+			values:  map[string][]string{},
+			want:    current,
+		},
+		{
+			name:      "flag takes precedence",
+			failure:   false,
+			values:    map[string][]string{"log.level": {"debug"}},
+			overrides: map[string]bool{"log.level": true},
+			want:      current,
+		},
+		{
+			name:    "invalid log level",
+			failure: true,
+			values:  map[string][]string{"log.level": {"foo"}},
+		},
+		{
+			name:    "invalid duration",
+			failure: true,
+			values:  map[string][]string{"build.timeout": {"foo"}},
+		},
+		{
+			name:    "negative duration",
+			failure: true,
+			values:  map[string][]string{"checkIn": {"-5m"}},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := current.load(test.values, test.overrides)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("load should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("load returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("load is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestWorker_configFile_check(t *testing.T) {
+	// setup types
+	file := &configFile{
+		overrides: map[string]bool{"server.addr": true},
+		values: map[string][]string{
+			"build.limit": {"2"},
+			"log.level":   {"info"},
+			"server.addr": {"http://localhost:8080"},
+		},
+	}
+
+	// setup tests
+	tests := []struct {
+		name    string
+		failure bool
+		values  map[string][]string
+	}{
+		{
+			name:    "unchanged",
+			failure: false,
+			values:  file.values,
+		},
+		{
+			name:    "reloadable setting changed",
+			failure: false,
+			values: map[string][]string{
+				"build.limit": {"2"},
+				"log.level":   {"debug"},
+				"server.addr": {"http://localhost:8080"},
+			},
+		},
+		{
+			name:    "overridden setting changed",
+			failure: false,
+			values: map[string][]string{
+				"build.limit": {"2"},
+				"log.level":   {"info"},
+				"server.addr": {"http://vela.example.com"},
+			},
+		},
+		{
+			name:    "unsafe setting changed",
+			failure: true,
+			values: map[string][]string{
+				"build.limit": {"4"},
+				"log.level":   {"info"},
+				"server.addr": {"http://localhost:8080"},
+			},
+		},
+		{
+			name:    "unsafe setting removed",
+			failure: true,
+			values: map[string][]string{
+				"log.level":   {"info"},
+				"server.addr": {"http://localhost:8080"},
+			},
+		},
+		{
+			name:    "unsafe setting added",
+			failure: true,
+			values: map[string][]string{
+				"build.limit":    {"2"},
+				"log.level":      {"info"},
+				"server.addr":    {"http://localhost:8080"},
+				"runtime.driver": {"kubernetes"},
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := file.check(test.values)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("check should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("check returned err: %v", err)
+			}
+		})
+	}
+}
+
+func TestWorker_reloadConfig(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		failure bool
+		data    string
+		want    settings
+	}{
+		{
+			name:    "reloaded",
+			failure: false,
+			data:    "build:\n  limit: 2\nlog.level: debug\nqueue.routes: [vela, large]\n",
+			want: settings{
+				LogLevel:     "debug",
+				Routes:       []string{"vela", "large"},
+				BuildTimeout: 30 * time.Minute,
+				CheckIn:      15 * time.Minute,
+			},
+		},
+		{
+			name:    "unsafe setting changed",
+			failure: true,
+			data:    "build:\n  limit: 4\nlog.level: debug\n",
+		},
+		{
+			name:    "invalid log level",
+			failure: true,
+			data:    "build:\n  limit: 2\nlog.level: foo\n",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yml")
+
+			err := os.WriteFile(path, []byte(test.data), 0o600)
+			if err != nil {
+				t.Fatalf("unable to write configuration file: %v", err)
+			}
+
+			w := &Worker{
+				Config: &Config{
+					Build:   &Build{Timeout: 30 * time.Minute},
+					CheckIn: 15 * time.Minute,
+					Logger:  &Logger{Level: "info"},
+					Queue:   &queue.Setup{Routes: []string{"vela"}},
+					Runtime: &runtime.Setup{},
+				},
+				configFile: &configFile{
+					path:      path,
+					overrides: map[string]bool{},
+					values: map[string][]string{
+						"build.limit": {"2"},
+						"log.level":   {"info"},
+					},
+				},
+				reloaded: make(chan struct{}, 1),
+			}
+
+			current := w.settings()
+
+			err = w.reloadConfig()
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("reloadConfig should have returned err")
+				}
+
+				// the settings are not changed when the reload is rejected
+				if got := w.settings(); !reflect.DeepEqual(got, current) {
+					t.Errorf("reloadConfig settings are %v, want %v", got, current)
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("reloadConfig returned err: %v", err)
+			}
+
+			if got := w.settings(); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("reloadConfig settings are %v, want %v", got, test.want)
+			}
+
+			if len(w.reloaded) == 0 {
+				t.Errorf("reloadConfig did not signal the reloaded routes")
+			}
+		})
+	}
+}
@@ -17,8 +17,13 @@ import (
 	"github.com/go-vela/server/compiler/types/pipeline"
 	"github.com/go-vela/server/constants"
 	"github.com/go-vela/server/queue"
+	workerAPI "github.com/go-vela/worker/api"
 	"github.com/go-vela/worker/executor"
+	"github.com/go-vela/worker/internal/journal"
+	"github.com/go-vela/worker/internal/report"
+	"github.com/go-vela/worker/internal/tracing"
 	"github.com/go-vela/worker/runtime"
+	"github.com/go-vela/worker/version"
 )
 
 // run executes the worker based
@@ -35,30 +40,22 @@ func run(ctx context.Context, c *cli.Command) error {
 	}
 
 	// set log level for the worker
-	switch c.String("log.level") {
-	case "t", "trace", "Trace", "TRACE":
-		gin.SetMode(gin.DebugMode)
-		logrus.SetLevel(logrus.TraceLevel)
-	case "d", "debug", "Debug", "DEBUG":
+	level, err := logLevel(c.String("log.level"))
+	if err != nil {
+		return err
+	}
+
+	logrus.SetLevel(level)
+
+	// set the mode for the API based on the log level, which is
+	// only done on startup since the mode is not safe to change
+	// while the API is serving requests
+	//
+	// https://pkg.go.dev/github.com/gin-gonic/gin#SetMode
+	if level >= logrus.DebugLevel {
 		gin.SetMode(gin.DebugMode)
-		logrus.SetLevel(logrus.DebugLevel)
-	case "w", "warn", "Warn", "WARN":
-		gin.SetMode(gin.ReleaseMode)
-		logrus.SetLevel(logrus.WarnLevel)
-	case "e", "error", "Error", "ERROR":
-		gin.SetMode(gin.ReleaseMode)
-		logrus.SetLevel(logrus.ErrorLevel)
-	case "f", "fatal", "Fatal", "FATAL":
-		gin.SetMode(gin.ReleaseMode)
-		logrus.SetLevel(logrus.FatalLevel)
-	case "p", "panic", "Panic", "PANIC":
+	} else {
 		gin.SetMode(gin.ReleaseMode)
-		logrus.SetLevel(logrus.PanicLevel)
-	case "i", "info", "Info", "INFO":
-		fallthrough
-	default:
-		gin.SetMode(gin.ReleaseMode)
-		logrus.SetLevel(logrus.InfoLevel)
 	}
 
 	// create a log entry with extra metadata
@@ -96,8 +93,12 @@ func run(ctx context.Context, c *cli.Command) error {
 			},
 			// build configuration
 			Build: &Build{
-				Limit:   c.Int32("build.limit"),
-				Timeout: c.Duration("build.timeout"),
+				Limit:             c.Int32("build.limit"),
+				Timeout:           c.Duration("build.timeout"),
+				DrainTimeout:      c.Duration("build.drain-timeout"),
+				JournalDir:        c.String("build.journal-dir"),
+				InfraRestartLimit: c.Int("build.infra-restart-limit"),
+				RequeueLimit:      c.Int("build.requeue-limit"),
 			},
 			// build configuration
 			CheckIn: c.Duration("checkIn"),
@@ -108,6 +109,12 @@ func run(ctx context.Context, c *cli.Command) error {
 				FileSizeLimit:       c.Int("storage.file-size-limit"),
 				BuildFileSizeLimit:  c.Int("storage.build-file-size-limit"),
 				LogStreamingTimeout: c.Duration("executor.log_streaming_timeout"),
+				LogSpoolDir:         c.String("executor.log-spool-dir"),
+				StepTimeout:         c.Duration("executor.step-timeout"),
+				StageTimeout:        c.Duration("executor.stage-timeout"),
+				RetryAttempts:       c.Int("executor.retry-attempts"),
+				RetryBackoff:        c.Duration("executor.retry-backoff"),
+				RetryExitCodes:      c.Int32Slice("executor.retry-exit-codes"),
 				EnforceTrustedRepos: c.Bool("executor.enforce-trusted-repos"),
 				OutputCtn:           outputsCtn,
 			},
@@ -118,50 +125,90 @@ func run(ctx context.Context, c *cli.Command) error {
 			},
 			// runtime configuration
 			Runtime: &runtime.Setup{
-				Driver:           c.String("runtime.driver"),
-				ConfigFile:       c.String("runtime.config"),
-				Namespace:        c.String("runtime.namespace"),
-				PodsTemplateName: c.String("runtime.pods-template-name"),
-				PodsTemplateFile: c.String("runtime.pods-template-file"),
-				HostVolumes:      c.StringSlice("runtime.volumes"),
-				PrivilegedImages: c.StringSlice("runtime.privileged-images"),
-				DropCapabilities: c.StringSlice("runtime.drop-capabilities"),
+				Driver:              c.String("runtime.driver"),
+				ConfigFile:          c.String("runtime.config"),
+				Namespace:           c.String("runtime.namespace"),
+				PodsTemplateName:    c.String("runtime.pods-template-name"),
+				PodsTemplateFile:    c.String("runtime.pods-template-file"),
+				HostVolumes:         c.StringSlice("runtime.volumes"),
+				PrivilegedImages:    c.StringSlice("runtime.privileged-images"),
+				DropCapabilities:    c.StringSlice("runtime.drop-capabilities"),
+				DockerConfig:        c.String("runtime.docker-config"),
+				RegistryCredentials: c.String("runtime.registry-credentials"),
+				DefaultCPULimit:     c.String("runtime.default-cpu-limit"),
+				DefaultMemoryLimit:  c.String("runtime.default-memory-limit"),
+				MaxCPULimit:         c.String("runtime.max-cpu-limit"),
+				MaxMemoryLimit:      c.String("runtime.max-memory-limit"),
+				JanitorInterval:     c.Duration("runtime.janitor-interval"),
+				JanitorMaxAge:       c.Duration("runtime.janitor-max-age"),
+				StopGracePeriod:     c.Duration("runtime.stop-grace-period"),
+				StartTimeout:        c.Duration("runtime.start-timeout"),
 			},
 			// queue configuration
 			Queue: &queue.Setup{
-				Address: c.String("queue.addr"),
-				Driver:  c.String("queue.driver"),
-				Cluster: c.Bool("queue.cluster"),
-				Routes:  c.StringSlice("queue.routes"),
-				Timeout: c.Duration("queue.pop.timeout"),
+				Address:    c.String("queue.addr"),
+				Driver:     c.String("queue.driver"),
+				Cluster:    c.Bool("queue.cluster"),
+				Routes:     c.StringSlice("queue.routes"),
+				Timeout:    c.Duration("queue.pop.timeout"),
+				PrivateKey: c.String("queue.private-key"),
 			},
 			// server configuration
 			Server: &Server{
-				Address: c.String("server.addr"),
-				Secret:  c.String("server.secret"),
+				Address:    c.String("server.addr"),
+				Secret:     c.String("server.secret"),
+				SigningKey: c.String("server.signing-key"),
+			},
+			// standalone configuration
+			Standalone: &Standalone{
+				Enabled:   c.Bool("standalone"),
+				ReportDir: c.String("standalone.report-dir"),
 			},
 			// Certificate configuration
 			Certificate: &Certificate{
-				Cert: c.String("server.cert"),
-				Key:  c.String("server.cert-key"),
+				Cert:     c.String("server.cert"),
+				Key:      c.String("server.cert-key"),
+				ClientCA: c.String("server.client-ca"),
 			},
 			// TLS minimum version enforced
 			TLSMinVersion: c.String("server.tls-min-version"),
+			// tracing configuration
+			Tracing: &tracing.Config{
+				Enabled:     c.Bool("tracing.enable"),
+				Endpoint:    c.String("tracing.endpoint"),
+				SampleRatio: c.Float64("tracing.sample-ratio"),
+				Version:     version.New().Semantic(),
+			},
 		},
 		Executors: make(map[int]executor.Engine),
 
 		RegisterToken: make(chan string, 1),
 
 		RunningBuilds: make([]*api.Build, 0),
+
+		Runtimes: runtime.NewPool(),
+
+		Shutdown: make(chan struct{}, 1),
+
+		BuildLimit: make(chan int32, 1),
+
+		Draining: make(chan struct{}),
+
+		operatorDone: make(chan struct{}),
+
+		reloaded: make(chan struct{}, 1),
 	}
 
+	// capture the configuration file for reloading the worker
+	w.configFile, _ = ctx.Value(configFileKey{}).(*configFile)
+
 	// set the worker address if no flag was provided
 	if len(w.Config.API.Address.String()) == 0 {
 		w.Config.API.Address, _ = url.Parse(fmt.Sprintf("http://%s", hostname))
 	}
 
 	// if server secret is provided, use as register token on start up
-	if len(c.String("server.secret")) > 0 {
+	if len(c.String("server.secret")) > 0 && !w.Config.Standalone.Enabled {
 		logrus.Trace("registering worker with embedded server secret")
 
 		w.RegisterToken <- c.String("server.secret")
@@ -173,6 +220,81 @@ func run(ctx context.Context, c *cli.Command) error {
 		return err
 	}
 
+	// setup the journal for recording running builds
+	//
+	// https://pkg.go.dev/github.com/go-vela/worker/internal/journal#New
+	w.Journal, err = journal.New(w.Config.Build.JournalDir)
+	if err != nil {
+		// the journal only cleans up after a crash, so run
+		// the builds without it instead of failing to start
+		logrus.Warnf("disabling build journal: %v", err)
+	}
+
+	// load the registry credentials once for the builds on the worker
+	//
+	// https://pkg.go.dev/github.com/go-vela/worker/runtime#Pool.LoadRegistryAuth
+	if w.Config.Runtime.Driver == constants.DriverDocker {
+		err = w.Runtimes.LoadRegistryAuth(w.Config.Runtime.DockerConfig, w.Config.Runtime.RegistryCredentials)
+		if err != nil {
+			return err
+		}
+	}
+
+	// setup the reporter for running builds without a server
+	if w.Config.Standalone.Enabled {
+		logrus.Info("running worker in standalone mode")
+
+		// https://pkg.go.dev/github.com/go-vela/worker/internal/report#New
+		w.Reporter, err = report.New(w.Config.Standalone.ReportDir)
+		if err != nil {
+			return err
+		}
+
+		w.Builds = make(chan *workerAPI.BuildRequest)
+	}
+
+	// capture the hostname for the traces of the builds
+	w.Config.Tracing.Hostname = w.Config.API.Address.Hostname()
+
+	// setup the exporter for the traces of the builds
+	//
+	// https://pkg.go.dev/github.com/go-vela/worker/internal/tracing#Setup
+	shutdown, err := tracing.Setup(ctx, w.Config.Tracing)
+	if err != nil {
+		return err
+	}
+
+	// flush the traces pending export once the worker stops
+	defer func() {
+		//nolint:contextcheck // ctx is canceled once the worker stops
+		err := shutdown(context.Background())
+		if err != nil {
+			logrus.Errorf("unable to flush traces: %v", err)
+		}
+	}()
+
 	// start the worker
 	return w.Start(ctx)
 }
+
+// logLevel is a helper function to parse the log level for the worker.
+func logLevel(level string) (logrus.Level, error) {
+	switch level {
+	case "t", "trace", "Trace", "TRACE":
+		return logrus.TraceLevel, nil
+	case "d", "debug", "Debug", "DEBUG":
+		return logrus.DebugLevel, nil
+	case "i", "info", "Info", "INFO":
+		return logrus.InfoLevel, nil
+	case "w", "warn", "Warn", "WARN":
+		return logrus.WarnLevel, nil
+	case "e", "error", "Error", "ERROR":
+		return logrus.ErrorLevel, nil
+	case "f", "fatal", "Fatal", "FATAL":
+		return logrus.FatalLevel, nil
+	case "p", "panic", "Panic", "PANIC":
+		return logrus.PanicLevel, nil
+	default:
+		return logrus.InfoLevel, fmt.Errorf("invalid log level provided: %s", level)
+	}
+}
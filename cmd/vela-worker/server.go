@@ -5,7 +5,6 @@ package main
 import (
 	"crypto/tls"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
@@ -29,10 +28,17 @@ func (w *Worker) server() (http.Handler, *tls.Config) {
 	_server := router.Load(
 		middleware.RequestVersion,
 		middleware.ServerAddress(w.Config.Server.Address),
+		middleware.SigningKey(w.Config.Server.SigningKey),
 		middleware.WorkerHostname(w.Config.API.Address.Hostname()),
-		middleware.Executors(w.Executors),
+		middleware.Executors(w.executors),
 		middleware.Logger(logrus.StandardLogger(), time.RFC3339, true),
 		middleware.RegisterToken(w.RegisterToken),
+		middleware.Shutdown(w.Shutdown),
+		middleware.BuildLimit(w.BuildLimit),
+		middleware.Builds(w.Builds),
+		middleware.StandaloneSecret(w.standaloneSecret()),
+		middleware.ClientCertificate(len(w.Config.Certificate.ClientCA) > 0),
+		middleware.Health(w.health()),
 	)
 
 	// log a message indicating the start of serving traffic
@@ -42,23 +48,34 @@ func (w *Worker) server() (http.Handler, *tls.Config) {
 
 	// if running with HTTPS, check certs are provided and run with TLS.
 	if strings.EqualFold(w.Config.API.Address.Scheme, "https") {
-		if len(w.Config.Certificate.Cert) > 0 && len(w.Config.Certificate.Key) > 0 {
-			// check that the certificate and key are both populated
-			_, err := os.Stat(w.Config.Certificate.Cert)
-			if err != nil {
-				logrus.Fatalf("expecting certificate file at %s, got %v", w.Config.Certificate.Cert, err)
-			}
-
-			_, err = os.Stat(w.Config.Certificate.Key)
-			if err != nil {
-				logrus.Fatalf("expecting certificate key at %s, got %v", w.Config.Certificate.Key, err)
-			}
-		} else {
+		if len(w.Config.Certificate.Cert) == 0 || len(w.Config.Certificate.Key) == 0 {
 			logrus.Fatal("unable to run with TLS: No certificate provided")
 		}
 
+		// load the certificate and reload it when the files are rotated
+		reloader, err := newCertReloader(w.Config.Certificate.Cert, w.Config.Certificate.Key)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+
 		// define TLS config struct for server start up
-		tlsCfg := new(tls.Config)
+		tlsCfg := &tls.Config{
+			GetCertificate: reloader.GetCertificate,
+		}
+
+		// if a client CA bundle is supplied, verify the certificates of clients
+		//
+		// certificates are verified when provided so the health and metrics
+		// endpoints stay available to probes, and the API requires them
+		if len(w.Config.Certificate.ClientCA) > 0 {
+			pool, err := loadClientCA(w.Config.Certificate.ClientCA)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+
+			tlsCfg.ClientCAs = pool
+			tlsCfg.ClientAuth = tls.VerifyClientCertIfGiven
+		}
 
 		// if a TLS minimum version is supplied, set that in the config
 		if len(w.Config.TLSMinVersion) > 0 {
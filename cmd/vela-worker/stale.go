@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/go-vela/sdk-go/vela"
+	api "github.com/go-vela/server/api/types"
+	"github.com/go-vela/server/constants"
+	"github.com/go-vela/server/queue/models"
+)
+
+// requeuedHost represents the prefix of the host set on the build
+// for a stale item pushed back onto the queue by a worker.
+//
+// The queue item has no field for the number of times it was requeued,
+// so it is recorded in the host of the queued build instead. The host
+// is only reported to the server once a worker runs the build, and the
+// executor replaces it with the host of that worker before then.
+const requeuedHost = "vela-requeued:"
+
+// handleStale is a helper function to recover a build for an item
+// queued before a Vela upgrade or downgrade. The item is pushed back
+// onto the queue so a worker running the same version of Vela as the
+// server that queued it can run the build. The build is failed once
+// it was requeued the maximum number of times or is unable to be.
+//
+// The item is handled before the build executable is requested since
+// the server only provides the executable for a build once.
+func (w *Worker) handleStale(ctx context.Context, item *models.Item) error {
+	build := item.Build
+
+	// create logger with extra metadata
+	//
+	// https://pkg.go.dev/github.com/sirupsen/logrus#WithFields
+	logger := logrus.WithFields(logrus.Fields{
+		"build": build.GetNumber(),
+		"host":  w.Config.API.Address.Hostname(),
+		"repo":  build.GetRepo().GetFullName(),
+	})
+
+	logger.Warnf("stale queued build due to wrong item version: want %d, got %d", models.ItemVersion, item.ItemVersion)
+
+	requeues := requeueCount(build)
+
+	// check if the item can be pushed back onto the queue again
+	if requeues < w.Config.Build.RequeueLimit {
+		logger.Infof("requeueing stale build - attempt %d of %d", requeues+1, w.Config.Build.RequeueLimit)
+
+		err := w.requeueStale(ctx, item, requeues+1)
+		if err == nil {
+			return nil
+		}
+
+		logger.Errorf("unable to requeue stale build: %v", err)
+	}
+
+	// the build is unable to be recovered so fail it
+	logger.Errorf("failing stale queued build due to wrong item version: want %d, got %d", models.ItemVersion, item.ItemVersion)
+
+	// remove the requeue count before reporting the build
+	build.SetHost(w.Config.API.Address.Hostname())
+
+	// retrieve a build token from the server to update the build
+	//
+	// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#BuildService.GetBuildToken
+	bt, resp, err := w.VelaClient.Build.GetBuildToken(ctx, build.GetRepo().GetOrg(), build.GetRepo().GetName(), build.GetNumber())
+	if err != nil {
+		// build is not in pending state — user canceled build while it was in queue
+		if resp != nil && resp.StatusCode == http.StatusConflict {
+			return nil
+		}
+
+		return fmt.Errorf("unable to retrieve build token: %w", err)
+	}
+
+	client, err := setupClient(w.Config.Server, bt.GetToken())
+	if err != nil {
+		return err
+	}
+
+	return failBuild(ctx, build, client, "Unable to process stale build (queued before Vela upgrade/downgrade).")
+}
+
+// requeueStale is a helper function to push an item queued
+// by a different version of Vela back onto the queue for
+// the build, recording the number of times it was requeued.
+func (w *Worker) requeueStale(ctx context.Context, item *models.Item, requeues int) error {
+	if w.Queue == nil {
+		return errors.New("no queue available")
+	}
+
+	// the queue signs the items pushed onto it
+	if len(w.Config.Queue.PrivateKey) == 0 {
+		return errors.New("no queue signing key provided")
+	}
+
+	route := item.Build.GetRoute()
+	if len(route) == 0 {
+		return errors.New("no queue route for build")
+	}
+
+	host := item.Build.GetHost()
+
+	item.Build.SetHost(fmt.Sprintf("%s%d", requeuedHost, requeues))
+
+	data, err := json.Marshal(item)
+
+	// restore the host on the popped build
+	item.Build.SetHost(host)
+
+	if err != nil {
+		return err
+	}
+
+	// https://pkg.go.dev/github.com/go-vela/server/queue#Service
+	err = w.Queue.Push(ctx, route, data)
+	if err != nil {
+		return err
+	}
+
+	// wait before polling the queue again to give
+	// another worker the chance to process the item
+	select {
+	case <-ctx.Done():
+	case <-time.After(w.Config.Queue.Timeout):
+	}
+
+	return nil
+}
+
+// failBuild is a helper function to fail a build
+// popped from the queue that the worker is unable to run.
+func failBuild(ctx context.Context, build *api.Build, client *vela.Client, msg string) error {
+	build.SetError(msg)
+	build.SetStatus(constants.StatusError)
+	build.SetFinished(time.Now().UTC().Unix())
+
+	_, _, err := client.Build.Update(ctx, build)
+	if err != nil {
+		logrus.Errorf("unable to set build status to %s: %s", constants.StatusError, err)
+
+		return err
+	}
+
+	return nil
+}
+
+// requeueCount is a helper function to capture the number
+// of times a stale build was pushed back onto the queue.
+func requeueCount(b *api.Build) int {
+	count, ok := strings.CutPrefix(b.GetHost(), requeuedHost)
+	if !ok {
+		return 0
+	}
+
+	requeues, err := strconv.Atoi(count)
+	if err != nil {
+		return 0
+	}
+
+	return requeues
+}
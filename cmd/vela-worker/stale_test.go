@@ -0,0 +1,329 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	api "github.com/go-vela/server/api/types"
+	"github.com/go-vela/server/constants"
+	"github.com/go-vela/server/queue"
+	"github.com/go-vela/server/queue/models"
+)
+
+// staleServer is a fake server recording the
+// updates to the build for a stale queue item.
+type staleServer struct {
+	mutex sync.Mutex
+
+	admin    bool
+	token    int
+	restarts int
+	statuses []string
+	errors   []string
+}
+
+func (s *staleServer) handler() http.Handler {
+	gin.SetMode(gin.TestMode)
+
+	e := gin.New()
+
+	e.GET("/api/v1/user", func(c *gin.Context) {
+		user := new(api.User)
+		user.SetName("vela-worker")
+		user.SetAdmin(s.admin)
+
+		c.JSON(http.StatusOK, user)
+	})
+
+	e.GET("/api/v1/repos/:org/:repo/builds/:build/token", func(c *gin.Context) {
+		if s.token != http.StatusOK {
+			c.AbortWithStatus(s.token)
+
+			return
+		}
+
+		token := new(api.Token)
+		token.SetToken("buildToken")
+
+		c.JSON(http.StatusOK, token)
+	})
+
+	e.PUT("/api/v1/repos/:org/:repo/builds/:build", func(c *gin.Context) {
+		build := new(api.Build)
+
+		err := c.Bind(build)
+		if err != nil {
+			c.AbortWithStatus(http.StatusBadRequest)
+
+			return
+		}
+
+		s.mutex.Lock()
+		s.statuses = append(s.statuses, build.GetStatus())
+		s.errors = append(s.errors, build.GetError())
+		s.mutex.Unlock()
+
+		c.JSON(http.StatusOK, build)
+	})
+
+	e.POST("/api/v1/repos/:org/:repo/builds/:build", func(c *gin.Context) {
+		s.mutex.Lock()
+		s.restarts++
+		s.mutex.Unlock()
+
+		// only admins are allowed to restart builds for any repo
+		if !s.admin {
+			c.AbortWithStatus(http.StatusForbidden)
+
+			return
+		}
+
+		build := new(api.Build)
+		build.SetNumber(2)
+		build.SetStatus(constants.StatusPending)
+
+		c.JSON(http.StatusCreated, build)
+	})
+
+	return e
+}
+
+// staleQueue is a fake queue recording
+// the items pushed back onto the queue.
+type staleQueue struct {
+	queue.Service
+
+	routes []string
+	items  []*models.Item
+}
+
+func (q *staleQueue) Push(_ context.Context, route string, data []byte) error {
+	item := new(models.Item)
+
+	err := json.Unmarshal(data, item)
+	if err != nil {
+		return err
+	}
+
+	q.routes = append(q.routes, route)
+	q.items = append(q.items, item)
+
+	return nil
+}
+
+func TestWorker_handleStale(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name     string
+		limit    int
+		key      string
+		route    string
+		host     string
+		token    int
+		version  uint64
+		requeued string
+		statuses []string
+		err      string
+	}{
+		{
+			name:     "requeued",
+			limit:    3,
+			key:      "queueKey",
+			route:    "vela",
+			token:    http.StatusOK,
+			version:  models.ItemVersion - 1,
+			requeued: "vela-requeued:1",
+		},
+		{
+			name:     "requeued again",
+			limit:    3,
+			key:      "queueKey",
+			route:    "vela",
+			host:     "vela-requeued:2",
+			token:    http.StatusOK,
+			version:  models.ItemVersion - 1,
+			requeued: "vela-requeued:3",
+		},
+		{
+			name:     "queued by a newer server",
+			limit:    3,
+			key:      "queueKey",
+			route:    "vela",
+			token:    http.StatusOK,
+			version:  models.ItemVersion + 1,
+			requeued: "vela-requeued:1",
+		},
+		{
+			name:     "requeue limit exceeded",
+			limit:    3,
+			key:      "queueKey",
+			route:    "vela",
+			host:     "vela-requeued:3",
+			token:    http.StatusOK,
+			version:  models.ItemVersion - 1,
+			statuses: []string{constants.StatusError},
+			err:      "Unable to process stale build",
+		},
+		{
+			name:     "requeue disabled",
+			limit:    0,
+			key:      "queueKey",
+			route:    "vela",
+			token:    http.StatusOK,
+			version:  models.ItemVersion - 1,
+			statuses: []string{constants.StatusError},
+			err:      "Unable to process stale build",
+		},
+		{
+			name:     "no queue signing key",
+			limit:    3,
+			route:    "vela",
+			token:    http.StatusOK,
+			version:  models.ItemVersion - 1,
+			statuses: []string{constants.StatusError},
+			err:      "Unable to process stale build",
+		},
+		{
+			name:     "no queue route",
+			limit:    3,
+			key:      "queueKey",
+			token:    http.StatusOK,
+			version:  models.ItemVersion - 1,
+			statuses: []string{constants.StatusError},
+			err:      "Unable to process stale build",
+		},
+		{
+			name:    "build canceled in queue",
+			limit:   0,
+			key:     "queueKey",
+			route:   "vela",
+			token:   http.StatusConflict,
+			version: models.ItemVersion - 1,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// the worker is not an admin so it is unable to restart builds
+			fake := &staleServer{admin: false, token: test.token}
+
+			s := httptest.NewServer(fake.handler())
+			defer s.Close()
+
+			client, err := setupClient(&Server{Address: s.URL}, "superSecretToken")
+			if err != nil {
+				t.Errorf("unable to create Vela API client: %v", err)
+			}
+
+			q := new(staleQueue)
+
+			w := &Worker{
+				Config: &Config{
+					API:    &API{Address: &url.URL{Host: "localhost"}},
+					Build:  &Build{RequeueLimit: test.limit},
+					Queue:  &queue.Setup{PrivateKey: test.key},
+					Server: &Server{Address: s.URL},
+				},
+				Queue:      q,
+				VelaClient: client,
+			}
+
+			repo := new(api.Repo)
+			repo.SetOrg("github")
+			repo.SetName("octocat")
+			repo.SetFullName("github/octocat")
+
+			build := new(api.Build)
+			build.SetNumber(1)
+			build.SetRepo(repo)
+			build.SetRoute(test.route)
+			build.SetHost(test.host)
+			build.SetStatus(constants.StatusPending)
+
+			item := &models.Item{
+				Build:       build,
+				ItemVersion: test.version,
+			}
+
+			err = w.handleStale(context.Background(), item)
+			if err != nil {
+				t.Errorf("handleStale returned err: %v", err)
+			}
+
+			if fake.restarts > 0 {
+				t.Errorf("handleStale restarted the build %d times, want 0", fake.restarts)
+			}
+
+			if !reflect.DeepEqual(fake.statuses, test.statuses) {
+				t.Errorf("handleStale statuses are %v, want %v", fake.statuses, test.statuses)
+			}
+
+			if len(test.err) > 0 && (len(fake.errors) == 0 || !strings.Contains(fake.errors[len(fake.errors)-1], test.err)) {
+				t.Errorf("handleStale errors are %v, want %s", fake.errors, test.err)
+			}
+
+			if len(test.requeued) == 0 {
+				if len(q.items) > 0 {
+					t.Errorf("handleStale requeued %d items, want 0", len(q.items))
+				}
+
+				return // continue to next test
+			}
+
+			if len(q.items) != 1 {
+				t.Errorf("handleStale requeued %d items, want 1", len(q.items))
+
+				return // continue to next test
+			}
+
+			if q.routes[0] != test.route {
+				t.Errorf("handleStale route is %s, want %s", q.routes[0], test.route)
+			}
+
+			if q.items[0].ItemVersion != test.version {
+				t.Errorf("handleStale item version is %d, want %d", q.items[0].ItemVersion, test.version)
+			}
+
+			if q.items[0].Build.GetHost() != test.requeued {
+				t.Errorf("handleStale host is %s, want %s", q.items[0].Build.GetHost(), test.requeued)
+			}
+		})
+	}
+}
+
+func TestWorker_requeueCount(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		host string
+		want int
+	}{
+		{host: "", want: 0},
+		{host: "localhost", want: 0},
+		{host: "vela-requeued:", want: 0},
+		{host: "vela-requeued:foo", want: 0},
+		{host: "vela-requeued:2", want: 2},
+	}
+
+	// run tests
+	for _, test := range tests {
+		build := new(api.Build)
+		build.SetHost(test.host)
+
+		got := requeueCount(build)
+		if got != test.want {
+			t.Errorf("requeueCount for %q is %d, want %d", test.host, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
+
+	api "github.com/go-vela/server/api/types"
+	workerAPI "github.com/go-vela/worker/api"
+	"github.com/go-vela/worker/internal/metrics"
+	"github.com/go-vela/worker/internal/tracing"
+	"github.com/go-vela/worker/version"
+)
+
+// standaloneSecret is a helper function to return the secret for
+// authenticating API requests when the worker runs without a server.
+func (w *Worker) standaloneSecret() string {
+	if !w.Config.Standalone.Enabled {
+		return ""
+	}
+
+	return w.Config.Server.Secret
+}
+
+// operateStandalone is a helper function to initiate the
+// subprocesses for the operator to execute the Vela
+// pipelines submitted to the worker API.
+func (w *Worker) operateStandalone(ctx context.Context) error {
+	// create the errgroup for managing operator subprocesses
+	//
+	// https://pkg.go.dev/golang.org/x/sync/errgroup#Group
+	executors, gctx := errgroup.WithContext(ctx)
+
+	// the worker is never registered with a server in standalone mode
+	// so this only tracks the running builds for the worker status
+	registryWorker := new(api.Worker)
+	registryWorker.SetHostname(w.Config.API.Address.Hostname())
+	registryWorker.SetAddress(w.Config.API.Address.String())
+	registryWorker.SetActive(true)
+	registryWorker.SetBuildLimit(w.buildLimit())
+
+	// run the standalone executor loop for each slot
+	run := func(id int) error {
+		return w.standaloneExecutor(gctx, id, registryWorker)
+	}
+
+	// spawn goroutine for resizing the executors when the build limit changes
+	executors.Go(func() error {
+		w.watchBuildLimit(gctx, executors, registryWorker, run)
+
+		return nil
+	})
+
+	// spawn an executor for every slot below the build limit
+	w.spawnExecutors(executors, run)
+
+	// wait for errors from operator subprocesses
+	//
+	// https://pkg.go.dev/golang.org/x/sync/errgroup#Group.Wait
+	return executors.Wait()
+}
+
+// standaloneExecutor is a helper function to execute the builds submitted
+// to the worker API until the worker stops or the slot is retired.
+func (w *Worker) standaloneExecutor(ctx context.Context, id int, registryWorker *api.Worker) error {
+	for {
+		// stop waiting for builds once the build limit drops below the slot
+		if w.retireExecutor(id) {
+			logrus.WithFields(logrus.Fields{
+				"id": id,
+			}).Info("build limit decreased, completed looping on worker executor")
+
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			logrus.WithFields(logrus.Fields{
+				"id": id,
+			}).Info("completed looping on worker executor")
+
+			return nil
+		case <-w.Draining:
+			logrus.WithFields(logrus.Fields{
+				"id": id,
+			}).Info("worker draining, completed looping on worker executor")
+
+			return nil
+		case <-time.After(5 * time.Second):
+			// check the build limit again
+			continue
+		case req := <-w.Builds:
+			// pass background context to avoid errors in one
+			// executor+build inadvertently canceling other builds
+			//
+			//nolint:contextcheck // see above
+			err := w.execStandalone(context.Background(), id, req, registryWorker)
+			if err != nil {
+				logrus.Errorf("unable to run standalone build: %v", err)
+			}
+		}
+	}
+}
+
+// execStandalone is a helper function to execute a Vela pipeline
+// submitted to the worker API and report the results locally.
+func (w *Worker) execStandalone(ctx context.Context, index int, req *workerAPI.BuildRequest, registryWorker *api.Worker) error {
+	build, p := req.Build, req.Pipeline
+
+	// trace the build from the time it was submitted
+	ctx, span := tracing.Start(ctx, "exec",
+		attribute.String("vela.repo", build.GetRepo().GetFullName()),
+		attribute.Int64("vela.build", build.GetNumber()),
+	)
+	defer span.End()
+
+	// prepare pipeline by hydrating container ID values based on build information
+	p.Prepare(build.GetRepo().GetOrg(), build.GetRepo().GetName(), build.GetNumber(), false)
+
+	// setup the client reporting the results of the build locally
+	//
+	// https://pkg.go.dev/github.com/go-vela/worker/internal/report#Reporter.Client
+	client, err := w.Reporter.Client()
+	if err != nil {
+		return err
+	}
+
+	// dereference configured outputs ctn config and set the outputs container ID for the executor
+	//
+	// need to dereference to avoid executors sharing the last set outputs container config
+	execOutputCtn := *w.Config.Executor.OutputCtn
+	execOutputCtn.ID = fmt.Sprintf("outputs_%s", p.ID)
+
+	// create logger with extra metadata
+	//
+	// https://pkg.go.dev/github.com/sirupsen/logrus#WithFields
+	logger := logrus.WithFields(logrus.Fields{
+		"build":    build.GetNumber(),
+		"executor": w.Config.Executor.Driver,
+		"host":     w.Config.API.Address.Hostname(),
+		"repo":     build.GetRepo().GetFullName(),
+		"runtime":  w.Config.Runtime.Driver,
+		"version":  version.New().Semantic(),
+	})
+
+	// create labels for the build metrics
+	labels := metrics.Labels{
+		Repo:     build.GetRepo().GetFullName(),
+		Runtime:  w.Config.Runtime.Driver,
+		Executor: w.Config.Executor.Driver,
+	}
+
+	// capture the settings that can be reloaded while the build runs
+	s := w.settings()
+
+	// setup the executor with the runtime for the build
+	_executor, err := w.newExecutor(logger, s, build, p, client, &execOutputCtn, false)
+	if err != nil {
+		return err
+	}
+	// add the executor to the worker
+	w.setExecutor(index, _executor)
+
+	// lock and append the build to the list
+	w.RunningBuildsMutex.Lock()
+
+	w.RunningBuilds = append(w.RunningBuilds, build)
+
+	registryWorker.SetRunningBuilds(w.RunningBuilds)
+
+	w.RunningBuildsMutex.Unlock()
+
+	// this gets deferred first so that it runs AFTER the build is destroyed
+	defer func() {
+		// capture the build to record the final status
+		if _build, err := _executor.GetBuild(); err == nil {
+			metrics.BuildFinished(labels, _build.GetStatus())
+		}
+
+		// lock and remove the build from the list
+		w.RunningBuildsMutex.Lock()
+
+		for i, v := range w.RunningBuilds {
+			if v == build {
+				w.RunningBuilds = append(w.RunningBuilds[:i], w.RunningBuilds[i+1:]...)
+
+				break
+			}
+		}
+
+		registryWorker.SetRunningBuilds(w.RunningBuilds)
+
+		w.RunningBuildsMutex.Unlock()
+	}()
+
+	metrics.BuildStarted(labels)
+
+	// run the build through the lifecycle of the executor
+	//
+	// builds submitted to the worker API are not queued so they aren't requeued
+	_ = w.runBuild(ctx, logger, _executor, s.buildTimeout(build))
+
+	return nil
+}
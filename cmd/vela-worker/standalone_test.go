@@ -0,0 +1,236 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	api "github.com/go-vela/server/api/types"
+	"github.com/go-vela/server/compiler/types/pipeline"
+	"github.com/go-vela/server/constants"
+	"github.com/go-vela/server/queue"
+	workerAPI "github.com/go-vela/worker/api"
+	"github.com/go-vela/worker/executor"
+	"github.com/go-vela/worker/internal/report"
+	"github.com/go-vela/worker/router/middleware"
+	"github.com/go-vela/worker/runtime"
+)
+
+// testStandaloneWorker is a helper function to create
+// a worker running builds without a server for tests.
+func testStandaloneWorker(t *testing.T, dir string) *Worker {
+	t.Helper()
+
+	reporter, err := report.New(dir)
+	if err != nil {
+		t.Fatalf("unable to create reporter: %v", err)
+	}
+
+	return &Worker{
+		Config: &Config{
+			Mock:    true,
+			API:     &API{Address: &url.URL{Scheme: "http", Host: "localhost"}},
+			Build:   &Build{Limit: 1, Timeout: time.Minute},
+			Logger:  &Logger{Level: "info"},
+			Queue:   &queue.Setup{},
+			Runtime: &runtime.Setup{Driver: constants.DriverDocker},
+			Executor: &executor.Setup{
+				Driver:    constants.DriverLinux,
+				OutputCtn: new(pipeline.Container),
+			},
+			Standalone: &Standalone{Enabled: true, ReportDir: dir},
+		},
+		Executors:     make(map[int]executor.Engine),
+		Runtimes:      runtime.NewPool(),
+		RunningBuilds: make([]*api.Build, 0),
+		BuildLimit:    make(chan int32, 1),
+		Builds:        make(chan *workerAPI.BuildRequest),
+		Reporter:      reporter,
+		Draining:      make(chan struct{}),
+	}
+}
+
+// testStandaloneBuild is a helper function to
+// create the request body for a build for tests.
+func testStandaloneBuild(t *testing.T) string {
+	t.Helper()
+
+	build := new(api.Build)
+	build.SetNumber(1)
+	build.SetStatus(constants.StatusPending)
+	build.SetRepo(new(api.Repo))
+	build.GetRepo().SetOrg("github")
+	build.GetRepo().SetName("octocat")
+	build.GetRepo().SetFullName("github/octocat")
+	build.GetRepo().SetTimeout(30)
+
+	p := &pipeline.Build{
+		Version: "1",
+		ID:      "github_octocat_1",
+		Steps: pipeline.ContainerSlice{
+			{
+				ID:        "step_github_octocat_1_init",
+				Directory: "/vela/src/github.com/github/octocat",
+				Image:     "#init",
+				Name:      constants.InitName,
+				Number:    1,
+				Pull:      "not_present",
+			},
+			{
+				ID:        "step_github_octocat_1_echo",
+				Commands:  []string{"echo hello"},
+				Directory: "/vela/src/github.com/github/octocat",
+				Image:     "alpine:latest",
+				Name:      "echo",
+				Number:    2,
+				Pull:      "not_present",
+			},
+		},
+	}
+
+	data, err := json.Marshal(&workerAPI.BuildRequest{Build: build, Pipeline: p})
+	if err != nil {
+		t.Fatalf("unable to marshal build request: %v", err)
+	}
+
+	return string(data)
+}
+
+// submitBuild is a helper function to submit the build
+// to the worker API and return the response code.
+func submitBuild(t *testing.T, w *Worker, body string) int {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+
+	e := gin.New()
+	e.Use(middleware.Builds(w.Builds))
+	e.POST("/api/v1/builds", workerAPI.RunBuild)
+
+	req, _ := http.NewRequestWithContext(t.Context(), http.MethodPost, "/api/v1/builds", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	return resp.Code
+}
+
+func TestWorker_operateStandalone(t *testing.T) {
+	// setup types
+	dir := t.TempDir()
+	w := testStandaloneWorker(t, dir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- w.operateStandalone(ctx)
+	}()
+
+	// submit the build once an executor is waiting for it
+	deadline := time.Now().Add(10 * time.Second)
+
+	for {
+		code := submitBuild(t, w, testStandaloneBuild(t))
+		if code == http.StatusCreated {
+			break
+		}
+
+		if code != http.StatusServiceUnavailable || time.Now().After(deadline) {
+			t.Fatalf("RunBuild returned %v, want %v", code, http.StatusCreated)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// wait for the build to be reported once it completes
+	path := filepath.Join(dir, "github", "octocat", "1", "build.json")
+	deadline = time.Now().Add(30 * time.Second)
+
+	got := new(api.Build)
+
+	for {
+		data, err := os.ReadFile(path)
+		if err == nil && json.Unmarshal(data, got) == nil && got.GetFinished() > 0 {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("operateStandalone did not report the build to %s", path)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if got.GetNumber() != 1 {
+		t.Errorf("operateStandalone reported build %d, want 1", got.GetNumber())
+	}
+
+	switch got.GetStatus() {
+	case constants.StatusSuccess, constants.StatusFailure, constants.StatusError:
+	default:
+		t.Errorf("operateStandalone reported status %s, want a completed status", got.GetStatus())
+	}
+
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("operateStandalone returned err: %v", err)
+		}
+	case <-time.After(30 * time.Second):
+		t.Errorf("operateStandalone did not stop after the context was canceled")
+	}
+}
+
+func TestWorker_standaloneExecutor_Draining(t *testing.T) {
+	// setup types
+	w := testStandaloneWorker(t, "")
+	w.slots = map[int]bool{0: true}
+
+	registryWorker := new(api.Worker)
+
+	// start draining the worker
+	close(w.Draining)
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- w.standaloneExecutor(context.Background(), 0, registryWorker)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("standaloneExecutor returned err: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("standaloneExecutor did not stop while draining")
+	}
+
+	// builds are rejected once the executors stopped
+	code := submitBuild(t, w, testStandaloneBuild(t))
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("RunBuild returned %v, want %v", code, http.StatusServiceUnavailable)
+	}
+
+	if len(w.RunningBuilds) > 0 {
+		t.Errorf("standaloneExecutor ran %d builds, want 0", len(w.RunningBuilds))
+	}
+}
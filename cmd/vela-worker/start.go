@@ -47,13 +47,8 @@ func (w *Worker) Start(ctx context.Context) error {
 		select {
 		case sig := <-signalChannel:
 			logrus.Infof("Received signal: %s", sig)
-
-			err := server.Shutdown(ctx)
-			if err != nil {
-				logrus.Error(err)
-			}
-
-			done()
+		case <-w.Shutdown:
+			logrus.Info("Received shutdown request")
 		case <-gctx.Done():
 			logrus.Info("Closing signal goroutine")
 
@@ -65,9 +60,39 @@ func (w *Worker) Start(ctx context.Context) error {
 			return gctx.Err()
 		}
 
+		// stop accepting new builds and wait for running builds to complete
+		w.drain(gctx, signalChannel)
+
+		err := server.Shutdown(ctx)
+		if err != nil {
+			logrus.Error(err)
+		}
+
+		done()
+
 		return nil
 	})
 
+	// goroutine to reload the configuration file and registry credentials on SIGHUP
+	g.Go(func() error {
+		reloadChannel := make(chan os.Signal, 1)
+		signal.Notify(reloadChannel, syscall.SIGHUP)
+
+		defer signal.Stop(reloadChannel)
+
+		for {
+			select {
+			case <-gctx.Done():
+				return nil
+			case <-reloadChannel:
+				err := w.reload()
+				if err != nil {
+					logrus.Errorf("unable to reload configuration: %v", err)
+				}
+			}
+		}
+	})
+
 	// spawn goroutine for starting the server
 	g.Go(func() error {
 		var err error
@@ -75,7 +100,8 @@ func (w *Worker) Start(ctx context.Context) error {
 		logrus.Info("starting worker server")
 
 		if tlsCfg != nil {
-			if err := server.ListenAndServeTLS(w.Config.Certificate.Cert, w.Config.Certificate.Key); !errors.Is(err, http.ErrServerClosed) {
+			// the certificate is provided by the TLS config so it can be reloaded
+			if err := server.ListenAndServeTLS("", ""); !errors.Is(err, http.ErrServerClosed) {
 				// log a message indicating the start of the server
 				//
 				// https://pkg.go.dev/github.com/sirupsen/logrus#Info
@@ -95,6 +121,9 @@ func (w *Worker) Start(ctx context.Context) error {
 
 	// spawn goroutine for starting the operator
 	g.Go(func() error {
+		// signal the operator stopped for the liveness of the worker
+		defer close(w.operatorDone)
+
 		logrus.Info("starting worker operator")
 		// start the operator for the worker
 		err := w.operate(gctx)
@@ -40,13 +40,39 @@ func (w *Worker) Validate() error {
 		return fmt.Errorf("no worker build timeout provided")
 	}
 
+	// verify the build drain timeout is not negative
+	if w.Config.Build.DrainTimeout < 0 {
+		return fmt.Errorf("invalid worker build drain timeout provided: %s", w.Config.Build.DrainTimeout)
+	}
+
+	// verify the build infrastructure restart limit is not negative
+	if w.Config.Build.InfraRestartLimit < 0 {
+		return fmt.Errorf("invalid worker build infrastructure restart limit provided: %d", w.Config.Build.InfraRestartLimit)
+	}
+
+	// verify the build requeue limit is not negative
+	if w.Config.Build.RequeueLimit < 0 {
+		return fmt.Errorf("invalid worker build requeue limit provided: %d", w.Config.Build.RequeueLimit)
+	}
+
 	// verify a worker address was provided
 	if *w.Config.API.Address == (url.URL{}) {
 		return fmt.Errorf("no worker address provided")
 	}
 
-	// verify a server address was provided
-	if len(w.Config.Server.Address) == 0 {
+	// verify client certificates are only required when serving over https
+	if len(w.Config.Certificate.ClientCA) > 0 && !strings.EqualFold(w.Config.API.Address.Scheme, "https") {
+		return fmt.Errorf("worker client CA provided without https worker address: %s", w.Config.API.Address.String())
+	}
+
+	// verify the standalone configuration
+	if w.Config.Standalone.Enabled {
+		// verify a secret was provided for authenticating requests
+		if len(w.Config.Server.Secret) == 0 {
+			return fmt.Errorf("no worker server secret provided for standalone mode")
+		}
+	} else if len(w.Config.Server.Address) == 0 {
+		// verify a server address was provided
 		return fmt.Errorf("no worker server address provided")
 	}
 
@@ -10,7 +10,11 @@ import (
 	"github.com/go-vela/sdk-go/vela"
 	api "github.com/go-vela/server/api/types"
 	"github.com/go-vela/server/queue"
+	workerAPI "github.com/go-vela/worker/api"
 	"github.com/go-vela/worker/executor"
+	"github.com/go-vela/worker/internal/journal"
+	"github.com/go-vela/worker/internal/report"
+	"github.com/go-vela/worker/internal/tracing"
 	"github.com/go-vela/worker/runtime"
 )
 
@@ -22,8 +26,12 @@ type (
 
 	// Build represents the worker configuration for build information.
 	Build struct {
-		Limit   int32
-		Timeout time.Duration
+		Limit             int32
+		Timeout           time.Duration
+		DrainTimeout      time.Duration
+		JournalDir        string
+		InfraRestartLimit int
+		RequeueLimit      int
 	}
 
 	// Logger represents the worker configuration for logger information.
@@ -34,14 +42,23 @@ type (
 
 	// Server represents the worker configuration for server information.
 	Server struct {
-		Address string
-		Secret  string
+		Address    string
+		Secret     string
+		SigningKey string
 	}
 
-	// Certificate represents the optional cert and key to enable TLS.
+	// Standalone represents the worker configuration for running builds without a server.
+	Standalone struct {
+		Enabled   bool
+		ReportDir string
+	}
+
+	// Certificate represents the optional cert and key to enable TLS
+	// and the optional CA bundle to verify client certificates.
 	Certificate struct {
-		Cert string
-		Key  string
+		Cert     string
+		Key      string
+		ClientCA string
 	}
 
 	// Config represents the worker configuration.
@@ -55,8 +72,10 @@ type (
 		Queue         *queue.Setup
 		Runtime       *runtime.Setup
 		Server        *Server
+		Standalone    *Standalone
 		Certificate   *Certificate
 		TLSMinVersion string
+		Tracing       *tracing.Config
 	}
 
 	// Worker represents all configuration and
@@ -64,13 +83,30 @@ type (
 	Worker struct {
 		Config             *Config
 		Executors          map[int]executor.Engine
+		ExecutorsMutex     sync.RWMutex
+		Journal            *journal.Journal
 		Queue              queue.Service
-		Runtime            runtime.Engine
+		Runtimes           *runtime.Pool
 		VelaClient         *vela.Client
 		RegisterToken      chan string
 		CheckedIn          bool
 		RunningBuilds      []*api.Build
 		QueueCheckedIn     bool
 		RunningBuildsMutex sync.Mutex
+		Shutdown           chan struct{}
+		BuildLimit         chan int32
+		Builds             chan *workerAPI.BuildRequest
+		Reporter           *report.Reporter
+		Draining           chan struct{}
+		drainOnce          sync.Once
+		slots              map[int]bool
+		executorLoops      int
+		operatorDone       chan struct{}
+		limitMutex         sync.Mutex
+		configFile         *configFile
+		configMutex        sync.RWMutex
+		reloaded           chan struct{}
+		registryMutex      sync.Mutex
+		limitPending       bool
 	}
 )
@@ -31,6 +31,9 @@ type Engine interface {
 	// CancelBuild defines a function for the API
 	// that Cancels the current build in execution.
 	CancelBuild() (*api.Build, error)
+	// TailLogs defines a function for the API that
+	// subscribes to the live output of a container.
+	TailLogs(context.Context, *pipeline.Container) (<-chan []byte, error)
 
 	// Build Engine interface functions
 
@@ -42,6 +42,62 @@ var Flags = []cli.Flag{
 		Sources: cli.EnvVars("WORKER_LOG_STREAMING_TIMEOUT", "VELA_LOG_STREAMING_TIMEOUT", "LOG_STREAMING_TIMEOUT"),
 		Value:   5 * time.Minute,
 	},
+	&cli.StringFlag{
+		Name:  "executor.log-spool-dir",
+		Usage: "directory for spilling logs to disk when they are unable to be uploaded to the server",
+		Sources: cli.NewValueSourceChain(
+			cli.EnvVar("VELA_EXECUTOR_LOG_SPOOL_DIR"),
+			cli.EnvVar("EXECUTOR_LOG_SPOOL_DIR"),
+			cli.File("/vela/executor/log_spool_dir"),
+		),
+	},
+	&cli.DurationFlag{
+		Name:  "executor.step-timeout",
+		Usage: "default maximum time a step may run when it doesn't set the VELA_STEP_TIMEOUT environment variable - set to 0 to disable",
+		Sources: cli.NewValueSourceChain(
+			cli.EnvVar("VELA_EXECUTOR_STEP_TIMEOUT"),
+			cli.EnvVar("EXECUTOR_STEP_TIMEOUT"),
+			cli.File("/vela/executor/step_timeout"),
+		),
+	},
+	&cli.DurationFlag{
+		Name:  "executor.stage-timeout",
+		Usage: "default maximum time the steps of a stage may run when it doesn't set the VELA_STAGE_TIMEOUT environment variable - set to 0 to disable",
+		Sources: cli.NewValueSourceChain(
+			cli.EnvVar("VELA_EXECUTOR_STAGE_TIMEOUT"),
+			cli.EnvVar("EXECUTOR_STAGE_TIMEOUT"),
+			cli.File("/vela/executor/stage_timeout"),
+		),
+	},
+	&cli.IntFlag{
+		Name:  "executor.retry-attempts",
+		Usage: "default maximum number of times a failed step runs when it doesn't set the VELA_STEP_RETRY_ATTEMPTS environment variable",
+		Sources: cli.NewValueSourceChain(
+			cli.EnvVar("VELA_EXECUTOR_RETRY_ATTEMPTS"),
+			cli.EnvVar("EXECUTOR_RETRY_ATTEMPTS"),
+			cli.File("/vela/executor/retry_attempts"),
+		),
+		Value: 1,
+	},
+	&cli.DurationFlag{
+		Name:  "executor.retry-backoff",
+		Usage: "default delay before retrying a failed step, doubled for every retry after it, when it doesn't set the VELA_STEP_RETRY_BACKOFF environment variable",
+		Sources: cli.NewValueSourceChain(
+			cli.EnvVar("VELA_EXECUTOR_RETRY_BACKOFF"),
+			cli.EnvVar("EXECUTOR_RETRY_BACKOFF"),
+			cli.File("/vela/executor/retry_backoff"),
+		),
+		Value: 10 * time.Second,
+	},
+	&cli.Int32SliceFlag{
+		Name:  "executor.retry-exit-codes",
+		Usage: "default exit codes a failed step is retried for when it doesn't set the VELA_STEP_RETRY_EXIT_CODES environment variable - any exit code is retried when empty",
+		Sources: cli.NewValueSourceChain(
+			cli.EnvVar("VELA_EXECUTOR_RETRY_EXIT_CODES"),
+			cli.EnvVar("EXECUTOR_RETRY_EXIT_CODES"),
+			cli.File("/vela/executor/retry_exit_codes"),
+		),
+	},
 	&cli.BoolFlag{
 		Name:  "executor.enforce-trusted-repos",
 		Usage: "enforce trusted repo restrictions for privileged images",
@@ -34,6 +34,16 @@ func (c *client) GetPipeline() (*pipeline.Build, error) {
 	return c.pipeline, nil
 }
 
+// TailLogs subscribes to the live output of a container in the current build.
+func (c *client) TailLogs(ctx context.Context, ctn *pipeline.Container) (<-chan []byte, error) {
+	// check if the container is provided
+	if ctn == nil {
+		return nil, fmt.Errorf("empty container provided")
+	}
+
+	return c.tail.Subscribe(ctx, ctn.ID), nil
+}
+
 // CancelBuild cancels the current build in execution.
 func (c *client) CancelBuild() (*api.Build, error) {
 	// get the current build from the client
@@ -3,9 +3,11 @@
 package linux
 
 import (
+	"context"
 	"reflect"
 	"testing"
 
+	"github.com/go-vela/server/compiler/types/pipeline"
 	"github.com/go-vela/server/constants"
 )
 
@@ -114,3 +116,64 @@ func TestLinux_GetPipeline(t *testing.T) {
 		})
 	}
 }
+
+func TestLinux_TailLogs(t *testing.T) {
+	// setup types
+	_steps := testSteps(constants.DriverDocker)
+
+	_engine, err := New(
+		WithPipeline(_steps),
+	)
+	if err != nil {
+		t.Errorf("unable to create executor engine: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		name      string
+		failure   bool
+		container *pipeline.Container
+	}{
+		{
+			name:      "with container",
+			failure:   false,
+			container: _steps.Steps[0],
+		},
+		{
+			name:      "missing container",
+			failure:   true,
+			container: nil,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := _engine.TailLogs(context.Background(), test.container)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("TailLogs should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("TailLogs returned err: %v", err)
+			}
+
+			_engine.tail.Publish(test.container.ID, []byte("hello"))
+			_engine.tail.Finish(test.container.ID)
+
+			line := <-got
+			if string(line) != "hello" {
+				t.Errorf("TailLogs received %s, want hello", line)
+			}
+
+			if _, ok := <-got; ok {
+				t.Errorf("TailLogs channel should be closed")
+			}
+		})
+	}
+}
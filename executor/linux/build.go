@@ -5,6 +5,7 @@ package linux
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -20,7 +21,10 @@ import (
 	"github.com/go-vela/worker/internal/build"
 	context2 "github.com/go-vela/worker/internal/context"
 	"github.com/go-vela/worker/internal/image"
+	"github.com/go-vela/worker/internal/infra"
+	"github.com/go-vela/worker/internal/metrics"
 	"github.com/go-vela/worker/internal/outputs"
+	"github.com/go-vela/worker/internal/spooler"
 	"github.com/go-vela/worker/internal/step"
 )
 
@@ -29,7 +33,13 @@ func (c *client) CreateBuild(ctx context.Context) error {
 	// defer taking a snapshot of the build
 	//
 	// https://pkg.go.dev/github.com/go-vela/worker/internal/build#Snapshot
-	defer func() { build.Snapshot(ctx, c.build, c.Vela, c.err, c.Logger) }()
+	defer func() {
+		if c.restarting(ctx) {
+			return
+		}
+
+		build.Snapshot(ctx, c.build, c.Vela, c.err, c.Logger)
+	}()
 
 	// update the build fields
 	c.build.SetStatus(constants.StatusRunning)
@@ -85,7 +95,13 @@ func (c *client) PlanBuild(ctx context.Context) error {
 	// https://pkg.go.dev/github.com/go-vela/worker/internal/build#Snapshot
 	//
 	//nolint:contextcheck // ctx can be canceled by build timing out. need to pass background context here
-	defer func() { build.Snapshot(context.Background(), c.build, c.Vela, c.err, c.Logger) }()
+	defer func() {
+		if c.restarting(ctx) {
+			return
+		}
+
+		build.Snapshot(context.Background(), c.build, c.Vela, c.err, c.Logger)
+	}()
 
 	// load the init step from the client
 	//
@@ -112,6 +128,10 @@ func (c *client) PlanBuild(ctx context.Context) error {
 	//
 	//nolint:contextcheck // ctx can be canceled by build timing out. need to pass background context here
 	defer func() {
+		if c.restarting(ctx) {
+			return
+		}
+
 		if c.err != nil {
 			_init.SetStatus(constants.StatusFailure)
 		}
@@ -219,7 +239,13 @@ func (c *client) AssembleBuild(ctx context.Context) error {
 	// https://pkg.go.dev/github.com/go-vela/worker/internal/build#Snapshot
 	//
 	//nolint:contextcheck // ctx can be canceled by build timing out. need to pass background context here
-	defer func() { build.Snapshot(context.Background(), c.build, c.Vela, c.err, c.Logger) }()
+	defer func() {
+		if c.restarting(ctx) {
+			return
+		}
+
+		build.Snapshot(context.Background(), c.build, c.Vela, c.err, c.Logger)
+	}()
 
 	// load the init step from the client
 	//
@@ -241,6 +267,10 @@ func (c *client) AssembleBuild(ctx context.Context) error {
 	//
 	// https://pkg.go.dev/github.com/go-vela/worker/internal/step#Upload
 	defer func() {
+		if c.restarting(ctx) {
+			return
+		}
+
 		if c.err != nil {
 			_init.SetStatus(constants.StatusFailure)
 		}
@@ -250,6 +280,10 @@ func (c *client) AssembleBuild(ctx context.Context) error {
 
 	//nolint:contextcheck // ctx can be canceled by build timing out. need to pass background context here
 	defer func() {
+		if c.restarting(ctx) {
+			return
+		}
+
 		c.Logger.Infof("uploading %s step logs", c.init.Name)
 		// send API call to update the logs for the step
 		//
@@ -257,7 +291,11 @@ func (c *client) AssembleBuild(ctx context.Context) error {
 		_, err = c.Vela.Log.UpdateStep(context.Background(), c.build.GetRepo().GetOrg(), c.build.GetRepo().GetName(), c.build.GetNumber(), c.init.Number, _log)
 		if err != nil {
 			c.Logger.Errorf("unable to upload %s logs: %v", c.init.Name, err)
+
+			return
 		}
+
+		metrics.AddLogBytes(c.metricLabels(), len(_log.GetData()))
 	}()
 
 	// update the init log with progress
@@ -868,6 +906,9 @@ func loadLazySecrets(ctx context.Context, c *client, _step *pipeline.Container)
 func (c *client) DestroyBuild(ctx context.Context) error {
 	var err error
 
+	// close the live output for any containers that never ran
+	defer c.tail.Close()
+
 	defer func() {
 		c.Logger.Info("deleting runtime build")
 		// remove the runtime build for the pipeline
@@ -877,6 +918,13 @@ func (c *client) DestroyBuild(ctx context.Context) error {
 		}
 	}()
 
+	c.Logger.Info("flushing logs")
+	// flush the logs pending upload for the pipeline
+	err = c.flushLogs(ctx)
+	if err != nil {
+		c.Logger.Errorf("unable to flush logs: %v", err)
+	}
+
 	// destroy the steps for the pipeline
 	for _, _step := range c.pipeline.Steps {
 		if _step.Name == constants.InitName {
@@ -953,6 +1001,47 @@ func (c *client) DestroyBuild(ctx context.Context) error {
 	return err
 }
 
+// flushLogs uploads the logs pending in the spooler and
+// keeps any logs the spooler was unable to upload on disk.
+func (c *client) flushLogs(ctx context.Context) error {
+	// capture the configured log streaming timeout
+	timeout := c.logStreamingTimeout
+	if timeout <= 0 {
+		timeout = spooler.DefaultMaxBackoff
+	}
+
+	// flush the logs even if the build was canceled
+	flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+
+	// https://pkg.go.dev/github.com/go-vela/worker/internal/spooler#Spooler.Flush
+	err := c.logs.Flush(flushCtx)
+
+	// https://pkg.go.dev/github.com/go-vela/worker/internal/spooler#Spooler.Close
+	return errors.Join(err, c.logs.Close())
+}
+
+// logKey is a helper function to create the key
+// for the logs of the container in the spooler.
+func (c *client) logKey(kind string, ctn *pipeline.Container) string {
+	return spooler.Key{
+		Org:    c.build.GetRepo().GetOrg(),
+		Repo:   c.build.GetRepo().GetName(),
+		Build:  c.build.GetNumber(),
+		Kind:   kind,
+		Number: ctn.Number,
+	}.String()
+}
+
+// restarting is a helper function to determine if the build failed due to
+// the infrastructure before any step ran and the worker restarts it. In that
+// case, the failure is left for the worker to report once it gives up.
+//
+// https://pkg.go.dev/github.com/go-vela/worker/internal/infra#Is
+func (c *client) restarting(ctx context.Context) bool {
+	return c.restartable && ctx.Err() == nil && infra.Is(c.err)
+}
+
 // UpdateSCMAuth updates the SCM authentication information for a container.
 func (c *client) UpdateSCMAuth(ctx context.Context, ctn *pipeline.Container) error {
 	// if the container is requesting a token, fetch a new one and add it to the environment
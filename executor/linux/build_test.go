@@ -4,6 +4,8 @@ package linux
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http/httptest"
 	"strings"
 	"testing"
@@ -21,6 +23,7 @@ import (
 	"github.com/go-vela/server/compiler/types/pipeline"
 	"github.com/go-vela/server/constants"
 	"github.com/go-vela/server/mock/server"
+	"github.com/go-vela/worker/internal/infra"
 	"github.com/go-vela/worker/internal/message"
 	"github.com/go-vela/worker/runtime"
 	"github.com/go-vela/worker/runtime/docker"
@@ -1956,6 +1959,76 @@ func TestLinux_DestroyBuild(t *testing.T) {
 	}
 }
 
+func TestLinux_restarting(t *testing.T) {
+	// setup types
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// setup tests
+	tests := []struct {
+		name        string
+		restartable bool
+		ctx         context.Context
+		err         error
+		want        bool
+	}{
+		{
+			name:        "infrastructure error",
+			restartable: true,
+			ctx:         context.Background(),
+			err:         fmt.Errorf("unable to setup build: %w", infra.Wrap(errors.New("connection refused"))),
+			want:        true,
+		},
+		{
+			name:        "infrastructure error when not restartable",
+			restartable: false,
+			ctx:         context.Background(),
+			err:         infra.Wrap(errors.New("connection refused")),
+			want:        false,
+		},
+		{
+			name:        "infrastructure error with canceled context",
+			restartable: true,
+			ctx:         canceled,
+			err:         infra.Wrap(errors.New("connection refused")),
+			want:        false,
+		},
+		{
+			name:        "pipeline error",
+			restartable: true,
+			ctx:         context.Background(),
+			err:         errors.New("attempting to use privileged image"),
+			want:        false,
+		},
+		{
+			name:        "no error",
+			restartable: true,
+			ctx:         context.Background(),
+			want:        false,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_engine, err := New(
+				WithRestartable(test.restartable),
+			)
+			if err != nil {
+				t.Errorf("unable to create executor engine: %v", err)
+			}
+
+			_engine.err = test.err
+
+			got := _engine.restarting(test.ctx)
+
+			if got != test.want {
+				t.Errorf("restarting is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
 func testOutputsCtn() *pipeline.Container {
 	return &pipeline.Container{
 		ID:          "outputs_test",
@@ -14,6 +14,9 @@ import (
 	api "github.com/go-vela/server/api/types"
 	"github.com/go-vela/server/compiler/types/pipeline"
 	"github.com/go-vela/worker/internal/message"
+	"github.com/go-vela/worker/internal/spooler"
+	"github.com/go-vela/worker/internal/step"
+	"github.com/go-vela/worker/internal/tail"
 	"github.com/go-vela/worker/runtime"
 )
 
@@ -34,6 +37,8 @@ type (
 		// clients for build actions
 		secret  *secretSvc
 		outputs *outputSvc
+		logs    *spooler.Spooler
+		tail    *tail.Hub
 
 		// private fields
 		init                *pipeline.Container
@@ -41,6 +46,11 @@ type (
 		fileSizeLimit       int64
 		buildFileSizeLimit  int64
 		logStreamingTimeout time.Duration
+		logSpoolDir         string
+		stepTimeout         time.Duration
+		stageTimeout        time.Duration
+		retryPolicy         step.RetryPolicy
+		restartable         bool
 		privilegedImages    []string
 		enforceTrustedRepos bool
 		build               *api.Build
@@ -79,6 +89,11 @@ func Equal(a, b *client) bool {
 		a.maxLogSize == b.maxLogSize &&
 		a.fileSizeLimit == b.fileSizeLimit &&
 		a.buildFileSizeLimit == b.buildFileSizeLimit &&
+		a.logSpoolDir == b.logSpoolDir &&
+		a.stepTimeout == b.stepTimeout &&
+		a.stageTimeout == b.stageTimeout &&
+		reflect.DeepEqual(a.retryPolicy, b.retryPolicy) &&
+		a.restartable == b.restartable &&
 		reflect.DeepEqual(a.privilegedImages, b.privilegedImages) &&
 		a.enforceTrustedRepos == b.enforceTrustedRepos &&
 		reflect.DeepEqual(a.build, b.build) &&
@@ -130,5 +145,23 @@ func New(opts ...Opt) (*client, error) {
 	c.secret = &secretSvc{client: c}
 	c.outputs = &outputSvc{client: c}
 
+	// create the spooler for uploading logs
+	//
+	// https://pkg.go.dev/github.com/go-vela/worker/internal/spooler#New
+	logs, err := spooler.New(
+		spooler.WithDirectory(c.logSpoolDir),
+		spooler.WithLogger(c.Logger),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logs = logs
+
+	// create the hub for streaming live logs
+	//
+	// https://pkg.go.dev/github.com/go-vela/worker/internal/tail#New
+	c.tail = tail.New()
+
 	return c, nil
 }
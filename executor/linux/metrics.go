@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package linux
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-vela/server/compiler/types/pipeline"
+	"github.com/go-vela/server/constants"
+	"github.com/go-vela/worker/internal/metrics"
+)
+
+// metricLabels returns the labels applied to
+// the metrics recorded for the build.
+func (c *client) metricLabels() metrics.Labels {
+	labels := metrics.Labels{
+		Repo:     c.build.GetRepo().GetFullName(),
+		Executor: c.Driver(),
+	}
+
+	if c.Runtime != nil {
+		labels.Runtime = c.Runtime.Driver()
+	}
+
+	return labels
+}
+
+// setupContainer prepares the runtime container and records
+// the time spent pulling the image for the container.
+func (c *client) setupContainer(ctx context.Context, ctn *pipeline.Container) error {
+	started := time.Now()
+
+	err := c.Runtime.SetupContainer(ctx, ctn)
+	if err != nil {
+		return err
+	}
+
+	// only the docker runtime pulls images during setup
+	if !strings.EqualFold(c.Runtime.Driver(), constants.DriverDocker) {
+		return nil
+	}
+
+	// images are pulled during setup for the always and not_present policies
+	if strings.EqualFold(ctn.Pull, constants.PullAlways) || strings.EqualFold(ctn.Pull, constants.PullNotPresent) {
+		metrics.ObserveImagePull(c.metricLabels(), time.Since(started))
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package linux
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-vela/sdk-go/vela"
+	"github.com/go-vela/server/constants"
+	"github.com/go-vela/server/mock/server"
+	"github.com/go-vela/worker/internal/metrics"
+	"github.com/go-vela/worker/runtime/docker"
+)
+
+func TestLinux_metricLabels(t *testing.T) {
+	// setup types
+	gin.SetMode(gin.TestMode)
+
+	s := httptest.NewServer(server.FakeHandler())
+
+	_client, err := vela.NewClient(s.URL, "", nil)
+	if err != nil {
+		t.Errorf("unable to create Vela API client: %v", err)
+	}
+
+	_runtime, err := docker.NewMock()
+	if err != nil {
+		t.Errorf("unable to create runtime engine: %v", err)
+	}
+
+	_build := testBuild()
+
+	want := metrics.Labels{
+		Repo:     _build.GetRepo().GetFullName(),
+		Runtime:  constants.DriverDocker,
+		Executor: constants.DriverLinux,
+	}
+
+	_engine, err := New(
+		WithBuild(_build),
+		WithHostname("localhost"),
+		WithPipeline(testSteps(constants.DriverDocker)),
+		WithRuntime(_runtime),
+		WithVelaClient(_client),
+	)
+	if err != nil {
+		t.Errorf("unable to create executor engine: %v", err)
+	}
+
+	// run test
+	got := _engine.metricLabels()
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("metricLabels is %v, want %v", got, want)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	api "github.com/go-vela/server/api/types"
 	"github.com/go-vela/server/compiler/types/pipeline"
 	"github.com/go-vela/worker/internal/message"
+	"github.com/go-vela/worker/internal/step"
 	"github.com/go-vela/worker/runtime"
 )
 
@@ -83,6 +84,93 @@ func WithLogStreamingTimeout(timeout time.Duration) Opt {
 	}
 }
 
+// WithLogSpoolDir sets the directory for spilling logs to disk in the executor client for Linux.
+func WithLogSpoolDir(dir string) Opt {
+	return func(c *client) error {
+		c.Logger.Trace("configuring log spool directory in linux executor client")
+
+		// set the log spool directory in the client
+		c.logSpoolDir = dir
+
+		return nil
+	}
+}
+
+// WithStepTimeout sets the default maximum time a step may run in the executor client for Linux.
+func WithStepTimeout(timeout time.Duration) Opt {
+	return func(c *client) error {
+		c.Logger.Trace("configuring step timeout in linux executor client")
+
+		// check if the step timeout provided is negative
+		if timeout < 0 {
+			return fmt.Errorf("invalid step timeout provided: %s", timeout)
+		}
+
+		// set the step timeout in the client
+		c.stepTimeout = timeout
+
+		return nil
+	}
+}
+
+// WithStageTimeout sets the default maximum time the steps of a stage may run in the executor client for Linux.
+func WithStageTimeout(timeout time.Duration) Opt {
+	return func(c *client) error {
+		c.Logger.Trace("configuring stage timeout in linux executor client")
+
+		// check if the stage timeout provided is negative
+		if timeout < 0 {
+			return fmt.Errorf("invalid stage timeout provided: %s", timeout)
+		}
+
+		// set the stage timeout in the client
+		c.stageTimeout = timeout
+
+		return nil
+	}
+}
+
+// WithRetryPolicy sets the default policy for retrying failed steps in the executor client for Linux.
+func WithRetryPolicy(attempts int, backoff time.Duration, exitCodes []int32) Opt {
+	return func(c *client) error {
+		c.Logger.Trace("configuring retry policy in linux executor client")
+
+		// check if the retry attempts provided are negative
+		if attempts < 0 {
+			return fmt.Errorf("invalid retry attempts provided: %d", attempts)
+		}
+
+		// check if the retry backoff provided is negative
+		if backoff < 0 {
+			return fmt.Errorf("invalid retry backoff provided: %s", backoff)
+		}
+
+		// set the retry policy in the client
+		c.retryPolicy = step.RetryPolicy{
+			Attempts:  attempts,
+			Backoff:   backoff,
+			ExitCodes: exitCodes,
+		}
+
+		return nil
+	}
+}
+
+// WithRestartable configures whether the worker restarts the build after an
+// infrastructure failure before any step runs in the executor client for Linux.
+//
+// When enabled, the executor leaves reporting those failures to the worker.
+func WithRestartable(restartable bool) Opt {
+	return func(c *client) error {
+		c.Logger.Trace("configuring restartable in linux executor client")
+
+		// set the restartable flag in the client
+		c.restartable = restartable
+
+		return nil
+	}
+}
+
 // WithPrivilegedImages sets the privileged images in the executor client for Linux.
 func WithPrivilegedImages(images []string) Opt {
 	return func(c *client) error {
@@ -16,6 +16,7 @@ import (
 	"github.com/go-vela/server/compiler/types/pipeline"
 	"github.com/go-vela/server/constants"
 	"github.com/go-vela/server/mock/server"
+	"github.com/go-vela/worker/internal/step"
 	"github.com/go-vela/worker/runtime"
 	"github.com/go-vela/worker/runtime/docker"
 	"github.com/go-vela/worker/runtime/kubernetes"
@@ -196,6 +197,246 @@ func TestLinux_Opt_WithLogStreamingTimeout(t *testing.T) {
 	}
 }
 
+func TestLinux_Opt_WithLogSpoolDir(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name        string
+		failure     bool
+		logSpoolDir string
+	}{
+		{
+			name:        "defined",
+			failure:     false,
+			logSpoolDir: "/tmp/vela/logs",
+		},
+		{
+			name:        "empty",
+			failure:     false,
+			logSpoolDir: "",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_engine, err := New(
+				WithLogSpoolDir(test.logSpoolDir),
+			)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("WithLogSpoolDir should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("WithLogSpoolDir returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(_engine.logSpoolDir, test.logSpoolDir) {
+				t.Errorf("WithLogSpoolDir is %v, want %v", _engine.logSpoolDir, test.logSpoolDir)
+			}
+		})
+	}
+}
+
+func TestLinux_Opt_WithStepTimeout(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name        string
+		failure     bool
+		stepTimeout time.Duration
+	}{
+		{
+			name:        "defined",
+			failure:     false,
+			stepTimeout: 30 * time.Minute,
+		},
+		{
+			name:        "empty",
+			failure:     false,
+			stepTimeout: 0,
+		},
+		{
+			name:        "negative",
+			failure:     true,
+			stepTimeout: -time.Minute,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_engine, err := New(
+				WithStepTimeout(test.stepTimeout),
+			)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("WithStepTimeout should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("WithStepTimeout returned err: %v", err)
+			}
+
+			if _engine.stepTimeout != test.stepTimeout {
+				t.Errorf("WithStepTimeout is %v, want %v", _engine.stepTimeout, test.stepTimeout)
+			}
+		})
+	}
+}
+
+func TestLinux_Opt_WithStageTimeout(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name         string
+		failure      bool
+		stageTimeout time.Duration
+	}{
+		{
+			name:         "defined",
+			failure:      false,
+			stageTimeout: 30 * time.Minute,
+		},
+		{
+			name:         "empty",
+			failure:      false,
+			stageTimeout: 0,
+		},
+		{
+			name:         "negative",
+			failure:      true,
+			stageTimeout: -time.Minute,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_engine, err := New(
+				WithStageTimeout(test.stageTimeout),
+			)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("WithStageTimeout should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("WithStageTimeout returned err: %v", err)
+			}
+
+			if _engine.stageTimeout != test.stageTimeout {
+				t.Errorf("WithStageTimeout is %v, want %v", _engine.stageTimeout, test.stageTimeout)
+			}
+		})
+	}
+}
+
+func TestLinux_Opt_WithRetryPolicy(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name      string
+		failure   bool
+		attempts  int
+		backoff   time.Duration
+		exitCodes []int32
+		want      step.RetryPolicy
+	}{
+		{
+			name:      "defined",
+			failure:   false,
+			attempts:  3,
+			backoff:   10 * time.Second,
+			exitCodes: []int32{137},
+			want:      step.RetryPolicy{Attempts: 3, Backoff: 10 * time.Second, ExitCodes: []int32{137}},
+		},
+		{
+			name:    "empty",
+			failure: false,
+			want:    step.RetryPolicy{},
+		},
+		{
+			name:     "negative attempts",
+			failure:  true,
+			attempts: -1,
+		},
+		{
+			name:     "negative backoff",
+			failure:  true,
+			attempts: 1,
+			backoff:  -time.Second,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_engine, err := New(
+				WithRetryPolicy(test.attempts, test.backoff, test.exitCodes),
+			)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("WithRetryPolicy should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("WithRetryPolicy returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(_engine.retryPolicy, test.want) {
+				t.Errorf("WithRetryPolicy is %v, want %v", _engine.retryPolicy, test.want)
+			}
+		})
+	}
+}
+
+func TestLinux_Opt_WithRestartable(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name        string
+		restartable bool
+	}{
+		{
+			name:        "enabled",
+			restartable: true,
+		},
+		{
+			name:        "disabled",
+			restartable: false,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_engine, err := New(
+				WithRestartable(test.restartable),
+			)
+			if err != nil {
+				t.Errorf("WithRestartable returned err: %v", err)
+			}
+
+			if _engine.restartable != test.restartable {
+				t.Errorf("WithRestartable is %v, want %v", _engine.restartable, test.restartable)
+			}
+		})
+	}
+}
+
 func TestLinux_Opt_WithPrivilegedImages(t *testing.T) {
 	// setup tests
 	tests := []struct {
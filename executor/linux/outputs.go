@@ -21,6 +21,7 @@ import (
 
 	api "github.com/go-vela/server/api/types"
 	"github.com/go-vela/server/compiler/types/pipeline"
+	"github.com/go-vela/worker/internal/metrics"
 )
 
 // outputSvc handles communication with the outputs container during the build.
@@ -54,7 +55,7 @@ func (o *outputSvc) create(ctx context.Context, ctn *pipeline.Container, timeout
 
 	logger.Debug("setting up outputs container")
 	// setup the runtime container
-	err := o.client.Runtime.SetupContainer(ctx, ctn)
+	err := o.client.setupContainer(ctx, ctn)
 	if err != nil {
 		return err
 	}
@@ -291,6 +292,8 @@ func (o *outputSvc) pollFiles(ctx context.Context, ctn *pipeline.Container, _ste
 		}
 
 		o.client.Uploaded += size
+
+		metrics.AddArtifactBytes(o.client.metricLabels(), size)
 	}
 
 	return nil
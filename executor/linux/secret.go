@@ -17,6 +17,7 @@ import (
 	"github.com/go-vela/server/compiler/types/pipeline"
 	"github.com/go-vela/server/constants"
 	"github.com/go-vela/worker/internal/message"
+	"github.com/go-vela/worker/internal/metrics"
 	"github.com/go-vela/worker/internal/outputs"
 	"github.com/go-vela/worker/internal/step"
 )
@@ -48,7 +49,7 @@ func (s *secretSvc) create(ctx context.Context, ctn *pipeline.Container, reqToke
 
 	logger.Debug("setting up container")
 	// setup the runtime container
-	err = s.client.Runtime.SetupContainer(ctx, ctn)
+	err = s.client.setupContainer(ctx, ctn)
 	if err != nil {
 		return err
 	}
@@ -149,6 +150,9 @@ func (s *secretSvc) exec(ctx context.Context, p *pipeline.SecretSlice) error {
 		// https://pkg.go.dev/github.com/sirupsen/logrus#Entry.WithField
 		logger := s.client.Logger.WithField("secret", _secret.Origin.Name)
 
+		// capture the time the secret plugin started for metrics
+		started := time.Now()
+
 		logger.Debug("running container")
 		// run the runtime container
 		err := s.client.Runtime.RunContainer(ctx, _secret.Origin, s.client.pipeline)
@@ -177,6 +181,8 @@ func (s *secretSvc) exec(ctx context.Context, p *pipeline.SecretSlice) error {
 			return err
 		}
 
+		metrics.ObserveSecretPlugin(s.client.metricLabels(), time.Since(started))
+
 		// check the step exit code
 		if _secret.Origin.ExitCode != 0 {
 			// check if we ignore step failures
@@ -299,7 +305,11 @@ func (s *secretSvc) stream(ctx context.Context, ctn *pipeline.Container) error {
 		_, err = s.client.Vela.Log.UpdateStep(ctx, s.client.build.GetRepo().GetOrg(), s.client.build.GetRepo().GetName(), s.client.build.GetNumber(), s.client.init.Number, _log)
 		if err != nil {
 			logger.Errorf("unable to upload container logs: %v", err)
+
+			return
 		}
+
+		metrics.AddLogBytes(s.client.metricLabels(), len(_log.GetData()))
 	}()
 
 	logger.Debug("tailing container")
@@ -334,6 +344,8 @@ func (s *secretSvc) stream(ctx context.Context, ctn *pipeline.Container) error {
 				return err
 			}
 
+			metrics.AddLogBytes(s.client.metricLabels(), len(_log.GetData()))
+
 			// flush the buffer of logs
 			logs.Reset()
 		}
@@ -10,12 +10,17 @@ import (
 	"io"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	api "github.com/go-vela/server/api/types"
 	"github.com/go-vela/server/compiler/types/pipeline"
 	"github.com/go-vela/server/constants"
 	"github.com/go-vela/worker/internal/image"
 	"github.com/go-vela/worker/internal/message"
+	"github.com/go-vela/worker/internal/metrics"
 	"github.com/go-vela/worker/internal/service"
+	"github.com/go-vela/worker/internal/spooler"
+	"github.com/go-vela/worker/internal/tracing"
 )
 
 // CreateService configures the service for execution.
@@ -27,7 +32,7 @@ func (c *client) CreateService(ctx context.Context, ctn *pipeline.Container) err
 
 	logger.Debug("setting up container")
 	// setup the runtime container
-	err := c.Runtime.SetupContainer(ctx, ctn)
+	err := c.setupContainer(ctx, ctn)
 	if err != nil {
 		return err
 	}
@@ -120,7 +125,10 @@ func (c *client) PlanService(ctx context.Context, ctn *pipeline.Container) error
 }
 
 // ExecService runs a service.
-func (c *client) ExecService(ctx context.Context, ctn *pipeline.Container) error {
+func (c *client) ExecService(ctx context.Context, ctn *pipeline.Container) (err error) {
+	ctx, span := tracing.Start(ctx, "executor.ExecService", attribute.String("vela.service", ctn.Name))
+	defer func() { tracing.End(span, err) }()
+
 	// update engine logger with service metadata
 	//
 	// https://pkg.go.dev/github.com/sirupsen/logrus#Entry.WithField
@@ -199,6 +207,9 @@ func (c *client) StreamService(ctx context.Context, ctn *pipeline.Container) err
 		return err
 	}
 
+	// close the live output for the service once streaming completes
+	defer c.tail.Finish(ctn.ID)
+
 	defer func() {
 		// tail the runtime container
 		rc, err := c.Runtime.TailContainer(ctx, ctn)
@@ -227,14 +238,11 @@ func (c *client) StreamService(ctx context.Context, ctn *pipeline.Container) err
 		// overwrite the existing log with all bytes
 		_log.SetData(data)
 
-		logger.Debug("uploading logs")
-		// send API call to update the logs for the service
+		logger.Debug("spooling logs")
+		// spool the logs for the service to be uploaded
 		//
-		// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#LogService.UpdateService
-		_, err = c.Vela.Log.UpdateService(ctx, c.build.GetRepo().GetOrg(), c.build.GetRepo().GetName(), c.build.GetNumber(), ctn.Number, _log)
-		if err != nil {
-			logger.Errorf("unable to upload container logs: %v", err)
-		}
+		// https://pkg.go.dev/github.com/go-vela/worker/internal/spooler#Spooler.Write
+		c.logs.Write(c.logKey(spooler.KindService, ctn), _log.GetData(), c.uploadServiceLogs(ctn))
 	}()
 
 	logger.Debug("tailing container")
@@ -285,13 +293,10 @@ func (c *client) StreamService(ctx context.Context, ctn *pipeline.Container) err
 					_log.AppendData(logs.Bytes())
 
 					logger.Debug("appending logs")
-					// send API call to append the logs for the service
+					// spool the logs for the service to be uploaded
 					//
-					// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#LogService.UpdateService
-					_, err = c.Vela.Log.UpdateService(ctx, c.build.GetRepo().GetOrg(), c.build.GetRepo().GetName(), c.build.GetNumber(), ctn.Number, _log)
-					if err != nil {
-						logger.Error(err)
-					}
+					// https://pkg.go.dev/github.com/go-vela/worker/internal/spooler#Spooler.Write
+					c.logs.Write(c.logKey(spooler.KindService, ctn), _log.GetData(), c.uploadServiceLogs(ctn))
 
 					// flush the buffer of logs
 					logs.Reset()
@@ -310,10 +315,16 @@ func (c *client) StreamService(ctx context.Context, ctn *pipeline.Container) err
 	// create new scanner from the container output
 	scanner := bufio.NewScanner(rc)
 
+	// capture the secret values for masking the live output
+	secretValues := getSecretValues(ctn)
+
 	// scan entire container output
 	for scanner.Scan() {
 		// write all the logs from the scanner
 		logs.Write(append(scanner.Bytes(), []byte("\n")...))
+
+		// publish the masked line to the clients watching the service
+		c.tail.Publish(ctn.ID, maskLine(scanner.Bytes(), secretValues))
 	}
 
 	logger.Info("finished streaming logs")
@@ -344,6 +355,10 @@ func (c *client) DestroyService(ctx context.Context, ctn *pipeline.Container) er
 	//
 	// https://pkg.go.dev/github.com/go-vela/worker/internal/service#Upload
 	defer func() {
+		if c.restarting(ctx) {
+			return
+		}
+
 		service.Upload(ctx, ctn, c.build, c.Vela, c.Logger, _service)
 	}()
 
@@ -363,3 +378,24 @@ func (c *client) DestroyService(ctx context.Context, ctn *pipeline.Container) er
 
 	return nil
 }
+
+// uploadServiceLogs returns the function used by the
+// spooler to upload the logs for the service.
+func (c *client) uploadServiceLogs(ctn *pipeline.Container) spooler.UploadFunc {
+	return func(ctx context.Context, data []byte) error {
+		_log := new(api.Log)
+		_log.SetData(data)
+
+		// send API call to update the logs for the service
+		//
+		// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#LogService.UpdateService
+		_, err := c.Vela.Log.UpdateService(ctx, c.build.GetRepo().GetOrg(), c.build.GetRepo().GetName(), c.build.GetNumber(), ctn.Number, _log)
+		if err != nil {
+			return err
+		}
+
+		metrics.AddLogBytes(c.metricLabels(), len(data))
+
+		return nil
+	}
+}
@@ -6,13 +6,28 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/go-vela/server/compiler/types/pipeline"
 	"github.com/go-vela/server/constants"
 	"github.com/go-vela/worker/internal/outputs"
 	"github.com/go-vela/worker/internal/step"
+	"github.com/go-vela/worker/internal/tracing"
 )
 
+// stageDeadlineKey is the key for the
+// deadline of the stage in the context of its steps.
+type stageDeadlineKey struct{}
+
+// stageDeadline represents the time
+// the steps of the stage must complete by.
+type stageDeadline struct {
+	timeout  time.Duration
+	deadline time.Time
+}
+
 // CreateStage prepares the stage for execution.
 func (c *client) CreateStage(ctx context.Context, s *pipeline.Stage) error {
 	// load the logs for the init step from the client
@@ -95,7 +110,10 @@ func (c *client) PlanStage(ctx context.Context, s *pipeline.Stage, m *sync.Map)
 }
 
 // ExecStage runs a stage.
-func (c *client) ExecStage(ctx context.Context, s *pipeline.Stage, m *sync.Map) error {
+func (c *client) ExecStage(ctx context.Context, s *pipeline.Stage, m *sync.Map) (err error) {
+	ctx, span := tracing.Start(ctx, "executor.ExecStage", attribute.String("vela.stage", s.Name))
+	defer func() { tracing.End(span, err) }()
+
 	// update engine logger with stage metadata
 	//
 	// https://pkg.go.dev/github.com/sirupsen/logrus#Entry.WithField
@@ -117,10 +135,37 @@ func (c *client) ExecStage(ctx context.Context, s *pipeline.Stage, m *sync.Map)
 
 	logger.Debug("starting execution of stage")
 
+	// capture the maximum time the steps of the stage may run
+	//
+	// https://pkg.go.dev/github.com/go-vela/worker/internal/step#StageTimeout
+	timeout, err := step.StageTimeout(s, c.stageTimeout)
+	if err != nil {
+		return err
+	}
+
+	var deadline *stageDeadline
+
+	if timeout > 0 {
+		deadline = &stageDeadline{
+			timeout:  timeout,
+			deadline: time.Now().Add(timeout),
+		}
+
+		// limit the steps of the stage to the deadline
+		ctx = context.WithValue(ctx, stageDeadlineKey{}, deadline)
+	}
+
 	stageStatus := constants.StatusRunning
 
 	// execute the steps for the stage
 	for _, _step := range s.Steps {
+		// check if the stage exceeded the timeout before the step started
+		if deadline != nil && time.Now().After(deadline.deadline) {
+			logger.Infof("stage exceeded timeout of %s, not running %s step", timeout, _step.Name)
+
+			continue
+		}
+
 		var useStatus string
 
 		if s.Independent {
@@ -8,15 +8,19 @@ import (
 	"net/http/httptest"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/urfave/cli/v3"
 
 	"github.com/go-vela/sdk-go/vela"
+	api "github.com/go-vela/server/api/types"
 	"github.com/go-vela/server/compiler/native"
 	"github.com/go-vela/server/compiler/types/pipeline"
+	"github.com/go-vela/server/constants"
 	"github.com/go-vela/server/mock/server"
 	"github.com/go-vela/worker/internal/message"
+	"github.com/go-vela/worker/internal/step"
 	"github.com/go-vela/worker/runtime"
 	"github.com/go-vela/worker/runtime/docker"
 	"github.com/go-vela/worker/runtime/kubernetes"
@@ -615,6 +619,111 @@ func TestLinux_ExecStage(t *testing.T) {
 	}
 }
 
+func TestLinux_ExecStage_StageTimeout(t *testing.T) {
+	// setup types
+	_build := testBuild()
+
+	gin.SetMode(gin.TestMode)
+
+	s := httptest.NewServer(server.FakeHandler())
+	defer s.Close()
+
+	_client, err := vela.NewClient(s.URL, "", nil)
+	if err != nil {
+		t.Fatalf("unable to create Vela API client: %v", err)
+	}
+
+	_docker, err := docker.NewMock()
+	if err != nil {
+		t.Fatalf("unable to create docker runtime engine: %v", err)
+	}
+
+	streamRequests, done := message.MockStreamRequestsWithCancel(context.Background())
+	defer done()
+
+	_engine, err := New(
+		WithBuild(_build),
+		WithPipeline(new(pipeline.Build)),
+		WithRuntime(_docker),
+		WithVelaClient(_client),
+		WithOutputCtn(testOutputsCtn()),
+		WithStageTimeout(time.Hour),
+		withStreamRequests(streamRequests),
+	)
+	if err != nil {
+		t.Fatalf("unable to create executor engine: %v", err)
+	}
+
+	_stage := &pipeline.Stage{
+		Name: "echo",
+		Steps: pipeline.ContainerSlice{
+			{
+				ID:          "github_octocat_1_echo_wait-hang",
+				Directory:   "/vela/src/github.com/github/octocat",
+				Environment: map[string]string{"FOO": "bar", "VELA_STAGE_TIMEOUT": "10ms"},
+				Image:       "alpine:latest",
+				Name:        "wait-hang",
+				Number:      1,
+				Pull:        "not_present",
+			},
+			{
+				ID:          "github_octocat_1_echo_echo",
+				Directory:   "/vela/src/github.com/github/octocat",
+				Environment: map[string]string{"FOO": "bar", "VELA_STAGE_TIMEOUT": "10ms"},
+				Image:       "alpine:latest",
+				Name:        "echo",
+				Number:      2,
+				Pull:        "not_present",
+				Ruleset: pipeline.Ruleset{
+					If: pipeline.Rules{Status: []string{constants.StatusFailure}},
+				},
+			},
+		},
+	}
+
+	for _, container := range _stage.Steps {
+		_engine.steps.Store(container.ID, api.StepFromBuildContainer(_build, container))
+		_engine.stepLogs.Store(container.ID, new(api.Log))
+	}
+
+	stageMap := new(sync.Map)
+	stageMap.Store("echo", make(chan error, 1))
+
+	// a timed out stage should not stop the build
+	err = _engine.ExecStage(context.Background(), _stage, stageMap)
+	if err != nil {
+		t.Fatalf("ExecStage returned err: %v", err)
+	}
+
+	stepEntries := []*api.Step{}
+
+	for _, container := range _stage.Steps {
+		stepEntry, err := step.Load(container, &_engine.steps)
+		if err != nil {
+			t.Fatalf("unable to load step %s: %v", container.Name, err)
+		}
+
+		stepEntries = append(stepEntries, stepEntry)
+	}
+
+	if got := stepEntries[0].GetExitCode(); got != step.ExitCodeTimeout {
+		t.Errorf("step exit code = %d, want %d", got, step.ExitCodeTimeout)
+	}
+
+	if got := stepEntries[0].GetError(); got != "stage exceeded timeout of 10ms" {
+		t.Errorf("step error = %s, want stage exceeded timeout of 10ms", got)
+	}
+
+	// the remaining steps of the stage do not run after the timeout
+	if got := stepEntries[1].GetStatus(); got != constants.StatusPending {
+		t.Errorf("step status = %s, want %s", got, constants.StatusPending)
+	}
+
+	if got := _build.GetStatus(); got != constants.StatusFailure {
+		t.Errorf("build status = %s, want %s", got, constants.StatusFailure)
+	}
+}
+
 func TestLinux_DestroyStage(t *testing.T) {
 	// setup types
 	_build := testBuild()
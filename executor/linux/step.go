@@ -9,16 +9,23 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/go-vela/sdk-go/vela"
 	api "github.com/go-vela/server/api/types"
 	"github.com/go-vela/server/compiler/types/pipeline"
 	"github.com/go-vela/server/constants"
+	"github.com/go-vela/worker/internal/exit"
 	"github.com/go-vela/worker/internal/image"
 	"github.com/go-vela/worker/internal/message"
+	"github.com/go-vela/worker/internal/metrics"
+	"github.com/go-vela/worker/internal/spooler"
 	"github.com/go-vela/worker/internal/step"
+	"github.com/go-vela/worker/internal/tracing"
 )
 
 // CreateStep configures the step for execution.
@@ -34,7 +41,7 @@ func (c *client) CreateStep(ctx context.Context, ctn *pipeline.Container) error
 
 	logger.Debug("setting up container")
 	// setup the runtime container
-	err := c.Runtime.SetupContainer(ctx, ctn)
+	err := c.setupContainer(ctx, ctn)
 	if err != nil {
 		return err
 	}
@@ -51,6 +58,9 @@ func (c *client) CreateStep(ctx context.Context, ctn *pipeline.Container) error
 		return err
 	}
 
+	// propagate the trace context for the build into the container
+	maps.Copy(ctn.Environment, tracing.Environment(ctx))
+
 	logger.Debug("injecting secrets")
 	// inject secrets for container
 	err = injectSecrets(ctn, c.Secrets)
@@ -153,11 +163,14 @@ func (c *client) PlanStep(ctx context.Context, ctn *pipeline.Container) error {
 }
 
 // ExecStep runs a step.
-func (c *client) ExecStep(ctx context.Context, ctn *pipeline.Container) error {
+func (c *client) ExecStep(ctx context.Context, ctn *pipeline.Container) (err error) {
 	if ctn.Name == constants.InitName {
 		return nil
 	}
 
+	ctx, span := tracing.Start(ctx, "executor.ExecStep", attribute.String("vela.step", ctn.Name))
+	defer func() { tracing.End(span, err) }()
+
 	// update engine logger with step metadata
 	//
 	// https://pkg.go.dev/github.com/sirupsen/logrus#Entry.WithField
@@ -171,6 +184,21 @@ func (c *client) ExecStep(ctx context.Context, ctn *pipeline.Container) error {
 		return err
 	}
 
+	// capture the time the step started for metrics
+	started := time.Now()
+
+	// defer recording the duration of the step
+	//
+	// this runs after the snapshot to capture the final status
+	defer func() {
+		// detached steps are not waited on
+		if ctn.Detach {
+			return
+		}
+
+		metrics.ObserveStep(c.metricLabels(), _step.GetStatus(), time.Since(started))
+	}()
+
 	// defer taking a snapshot of the step
 	//
 	// https://pkg.go.dev/github.com/go-vela/worker/internal/step#Snapshot
@@ -204,49 +232,352 @@ func (c *client) ExecStep(ctx context.Context, ctn *pipeline.Container) error {
 		}
 	}
 
-	logger.Debug("running container")
+	// capture the maximum time the step may run
+	//
+	// https://pkg.go.dev/github.com/go-vela/worker/internal/step#Timeout
+	timeout, err := step.Timeout(ctn, c.stepTimeout)
+	if err != nil {
+		_step.SetStatus(constants.StatusError)
+		_step.SetError(err.Error())
+
+		return err
+	}
 
-	// run the runtime container
-	err = c.Runtime.RunContainer(ctx, ctn, c.pipeline)
+	// capture the policy for retrying the step
+	//
+	// https://pkg.go.dev/github.com/go-vela/worker/internal/step#Retry
+	policy, err := step.Retry(ctn, c.retryPolicy)
 	if err != nil {
-		// set step status to error and step error
 		_step.SetStatus(constants.StatusError)
 		_step.SetError(err.Error())
 
 		return err
 	}
 
-	// trigger StreamStep goroutine with logging context
-	c.streamRequests <- message.StreamRequest{
-		Key:       "step",
-		Stream:    c.StreamStep,
-		Container: ctn,
+	// containers can't be recreated within the pod for the kubernetes runtime
+	if policy.Attempts > 1 && c.Runtime.Driver() == constants.DriverKubernetes {
+		logger.Warn("retrying steps is not supported by the kubernetes runtime")
+
+		policy.Attempts = 1
 	}
 
-	// do not wait for detached containers
-	if ctn.Detach {
-		return nil
+	// propagate the trace context for the step into the container
+	//
+	// the kubernetes runtime only delivers the changes to the
+	// environment after CreateStep to steps running commands
+	if ctn.Environment != nil {
+		maps.Copy(ctn.Environment, tracing.Environment(ctx))
+	}
+
+	for attempt := 1; ; attempt++ {
+		logger.Debug("running container")
+
+		// run the runtime container
+		err = c.Runtime.RunContainer(ctx, ctn, c.pipeline)
+		if err != nil {
+			// set step status to error and step error
+			_step.SetStatus(constants.StatusError)
+			_step.SetError(err.Error())
+			recordStepAttempts(_step, attempt, ctn.ExitCode)
+
+			return err
+		}
+
+		// capture when the output of the attempt is streamed
+		streamed := make(chan struct{})
+
+		// trigger StreamStep goroutine with logging context
+		c.streamRequests <- message.StreamRequest{
+			Key: "step",
+			Stream: func(ctx context.Context, ctn *pipeline.Container) error {
+				defer close(streamed)
+
+				return c.StreamStep(ctx, ctn)
+			},
+			Container: ctn,
+		}
+
+		// do not wait for detached containers
+		if ctn.Detach {
+			return nil
+		}
+
+		var timedOut bool
+
+		// limit the step to the time remaining for the stage
+		limit, msg := stageLimit(ctx, timeout)
+
+		timedOut, err = c.waitStep(ctx, ctn, limit)
+		if timedOut {
+			c.timeoutStep(ctx, ctn, _step, msg)
+			recordStepAttempts(_step, attempt, ctn.ExitCode)
+
+			return nil
+		}
+
+		if err != nil {
+			recordStepRuntimeError(ctn, _step, err)
+			recordStepAttempts(_step, attempt, ctn.ExitCode)
+
+			return err
+		}
+
+		// capture why the step exited if it was terminated by the infrastructure
+		reason := c.exitReason(ctx, ctn)
+		if reason != nil {
+			err = c.appendStepLog(ctx, ctn, streamed, fmt.Appendf(nil, "\n--- %s ---\n", reason))
+			if err != nil {
+				logger.Errorf("unable to append exit reason to logs: %v", err)
+			}
+		}
+
+		// check if the step should run again
+		if ctx.Err() != nil || !policy.Retryable(attempt, ctn.ExitCode) {
+			// record the attempts for steps that failed after retrying
+			recordStepAttempts(_step, attempt, ctn.ExitCode)
+
+			// record the attempts in the logs for steps that passed after retrying
+			if attempt > 1 && ctn.ExitCode == 0 {
+				logger.Infof("step succeeded after %d attempts", attempt)
+
+				err = c.appendStepLog(ctx, ctn, streamed, fmt.Appendf(nil, "\n--- step succeeded after %d attempts ---\n", attempt))
+				if err != nil {
+					logger.Errorf("unable to append attempts to logs: %v", err)
+				}
+			}
+
+			// record the reason for steps terminated by the infrastructure
+			if reason != nil {
+				msg := reason.String()
+
+				if len(_step.GetError()) > 0 {
+					msg = fmt.Sprintf("%s: %s", _step.GetError(), msg)
+				}
+
+				_step.SetError(msg)
+			}
+
+			return nil
+		}
+
+		err = c.retryStep(ctx, ctn, policy, attempt, streamed)
+		if err != nil {
+			recordStepRuntimeError(ctn, _step, err)
+			recordStepAttempts(_step, attempt, ctn.ExitCode)
+
+			return err
+		}
+	}
+}
+
+// waitStep is a helper function to wait for the step to complete
+// and capture the exit code, returning true if the step exceeded
+// the timeout while the build is still running.
+func (c *client) waitStep(ctx context.Context, ctn *pipeline.Container, timeout time.Duration) (bool, error) {
+	logger := c.Logger.WithField("step", ctn.Name)
+
+	// create a context limited to the timeout for the step
+	waitCtx := ctx
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+
+		waitCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
 
 	logger.Debug("waiting for container")
 	// wait for the runtime container
-	err = c.Runtime.WaitContainer(ctx, ctn)
+	err := c.Runtime.WaitContainer(waitCtx, ctn)
 	if err != nil {
-		recordStepRuntimeError(ctn, _step, err)
-		return err
+		// check if the step timed out while the build is still running
+		if ctx.Err() == nil && errors.Is(waitCtx.Err(), context.DeadlineExceeded) {
+			return true, nil
+		}
+
+		return false, err
 	}
 
 	logger.Debug("inspecting container")
 	// inspect the runtime container
-	err = c.Runtime.InspectContainer(ctx, ctn)
+	return false, c.Runtime.InspectContainer(ctx, ctn)
+}
+
+// retryStep is a helper function to prepare the step for running
+// again after the attempt failed, once the output of the attempt
+// is captured and the delay for the retry policy passed.
+func (c *client) retryStep(ctx context.Context, ctn *pipeline.Container, policy step.RetryPolicy, attempt int, streamed <-chan struct{}) error {
+	logger := c.Logger.WithField("step", ctn.Name)
+
+	delay := policy.Delay(attempt)
+
+	logger.Infof("step exited with code %d, retrying in %s (attempt %d of %d)", ctn.ExitCode, delay, attempt+1, policy.Attempts)
+
+	// separate the output of the attempts in the logs for the step
+	err := c.appendStepLog(ctx, ctn, streamed, fmt.Appendf(nil,
+		"\n--- attempt %d of %d failed with exit code %d, retrying in %s ---\n\n",
+		attempt, policy.Attempts, ctn.ExitCode, delay,
+	))
 	if err != nil {
-		recordStepRuntimeError(ctn, _step, err)
 		return err
 	}
 
+	logger.Debug("removing container")
+	// remove the runtime container so it can be created again
+	err = c.Runtime.RemoveContainer(ctx, ctn)
+	if err != nil {
+		return err
+	}
+
+	// allow following the live output of the next attempt
+	c.tail.Reopen(ctn.ID)
+
+	select {
+	case <-time.After(delay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	// reset the exit code for the next attempt
+	ctn.ExitCode = 0
+
+	return nil
+}
+
+// exitReason is a helper function to capture why the step exited
+// when it was terminated by the infrastructure, returning nil
+// if the step was successful or exited on its own.
+func (c *client) exitReason(ctx context.Context, ctn *pipeline.Container) *exit.Reason {
+	if ctn.ExitCode == 0 || ctx.Err() != nil {
+		return nil
+	}
+
+	logger := c.Logger.WithField("step", ctn.Name)
+
+	logger.Debug("capturing exit reason for container")
+	// capture why the runtime container exited
+	reason, err := c.Runtime.ExitReasonContainer(ctx, ctn)
+	if err != nil {
+		logger.Errorf("unable to capture exit reason for container: %v", err)
+
+		return nil
+	}
+
+	if reason == nil {
+		return nil
+	}
+
+	logger.Warnf("step exited with code %d: %s", ctn.ExitCode, reason)
+
+	metrics.StepExitReason(c.metricLabels(), reason.Reason)
+
+	return reason
+}
+
+// appendStepLog is a helper function to append the data to
+// the logs for the step once the output of the container
+// is captured by StreamStep.
+func (c *client) appendStepLog(ctx context.Context, ctn *pipeline.Container, streamed <-chan struct{}, data []byte) error {
+	// wait for the output of the container to be captured
+	select {
+	case <-streamed:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	// load the logs for the step from the client
+	//
+	// https://pkg.go.dev/github.com/go-vela/worker/internal/step#LoadLogs
+	_log, err := step.LoadLogs(ctn, &c.stepLogs)
+	if err != nil {
+		return err
+	}
+
+	_log.AppendData(data)
+
+	// spool the logs for the step to be uploaded
+	//
+	// https://pkg.go.dev/github.com/go-vela/worker/internal/spooler#Spooler.Write
+	c.logs.Write(c.logKey(spooler.KindStep, ctn), _log.GetData(), c.uploadStepLogs(ctn))
+
 	return nil
 }
 
+// stageLimit is a helper function to limit the timeout for the step to
+// the time remaining for its stage, returning the timeout along with the
+// error for the step when it is exceeded.
+func stageLimit(ctx context.Context, timeout time.Duration) (time.Duration, string) {
+	msg := fmt.Sprintf("step exceeded timeout of %s", timeout)
+
+	deadline, ok := ctx.Value(stageDeadlineKey{}).(*stageDeadline)
+	if !ok {
+		return timeout, msg
+	}
+
+	// a timeout of 0 disables the timeout so wait for at least a nanosecond
+	remaining := max(time.Until(deadline.deadline), time.Nanosecond)
+
+	if timeout > 0 && timeout <= remaining {
+		return timeout, msg
+	}
+
+	return remaining, fmt.Sprintf("stage exceeded timeout of %s", deadline.timeout)
+}
+
+// timeoutStep is a helper function to stop the step after it exceeded
+// the timeout and mark it with an error without ending the build so the
+// steps with rulesets for failed builds still run.
+func (c *client) timeoutStep(ctx context.Context, ctn *pipeline.Container, s *api.Step, msg string) {
+	logger := c.Logger.WithField("step", ctn.Name)
+
+	logger.Infof("%s, stopping container", msg)
+
+	// stop and remove the runtime container
+	err := c.Runtime.RemoveContainer(ctx, ctn)
+	if err != nil {
+		logger.Errorf("unable to stop container: %v", err)
+	}
+
+	ctn.ExitCode = step.ExitCodeTimeout
+
+	s.SetExitCode(step.ExitCodeTimeout)
+	s.SetStatus(constants.StatusError)
+	s.SetError(msg)
+	s.SetFinished(time.Now().UTC().Unix())
+
+	// check if container failures should be ignored
+	if !ctn.Ruleset.Continue {
+		// set build status to failure
+		c.build.SetStatus(constants.StatusFailure)
+	}
+}
+
+// timedOut is a helper function to return true
+// if the step was stopped after exceeding its timeout.
+func timedOut(s *api.Step) bool {
+	return s.GetExitCode() == step.ExitCodeTimeout &&
+		s.GetStatus() == constants.StatusError
+}
+
+// recordStepAttempts is a helper function to record the number of
+// attempts in the error for a step that failed after retrying.
+//
+// The error is left empty for a step that passed after retrying
+// since a step with an error is reported as failed.
+func recordStepAttempts(s *api.Step, attempt int, exitCode int32) {
+	if attempt < 2 {
+		return
+	}
+
+	switch {
+	case len(s.GetError()) > 0:
+		// the step timed out or errored while retrying
+		s.SetError(fmt.Sprintf("%s after %d attempts", s.GetError(), attempt))
+	case exitCode != 0:
+		s.SetError(fmt.Sprintf("step failed after %d attempts", attempt))
+	}
+}
+
 // recordStepRuntimeError updates the in-memory step so Snapshot/Upload won't report success.
 func recordStepRuntimeError(ctn *pipeline.Container, s *api.Step, err error) {
 	if s == nil || err == nil {
@@ -309,6 +640,9 @@ func (c *client) StreamStep(ctx context.Context, ctn *pipeline.Container) error
 
 	secretValues := getSecretValues(ctn)
 
+	// close the live output for the step once streaming completes
+	defer c.tail.Finish(ctn.ID)
+
 	defer func() {
 		// tail the runtime container
 		rc, err := c.Runtime.TailContainer(ctx, ctn)
@@ -340,16 +674,11 @@ func (c *client) StreamStep(ctx context.Context, ctn *pipeline.Container) error
 		// mask secrets in the log data
 		_log.MaskData(secretValues)
 
-		logger.Debug("uploading logs")
-		// send API call to update the logs for the step
+		logger.Debug("spooling logs")
+		// spool the logs for the step to be uploaded
 		//
-		// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#LogService.UpdateStep
-		//
-		//nolint:contextcheck // ctx can be canceled by build timing out. need to pass background context here
-		_, err = c.Vela.Log.UpdateStep(context.Background(), c.build.GetRepo().GetOrg(), c.build.GetRepo().GetName(), c.build.GetNumber(), ctn.Number, _log)
-		if err != nil {
-			logger.Errorf("unable to upload container logs: %v", err)
-		}
+		// https://pkg.go.dev/github.com/go-vela/worker/internal/spooler#Spooler.Write
+		c.logs.Write(c.logKey(spooler.KindStep, ctn), _log.GetData(), c.uploadStepLogs(ctn))
 	}()
 
 	logger.Debug("tailing container")
@@ -403,13 +732,10 @@ func (c *client) StreamStep(ctx context.Context, ctn *pipeline.Container) error
 					_log.MaskData(secretValues)
 
 					logger.Debug("appending logs")
-					// send API call to append the logs for the step
+					// spool the logs for the step to be uploaded
 					//
-					// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#LogStep.UpdateStep
-					_, err := c.Vela.Log.UpdateStep(ctx, c.build.GetRepo().GetOrg(), c.build.GetRepo().GetName(), c.build.GetNumber(), ctn.Number, _log)
-					if err != nil {
-						logger.Error(err)
-					}
+					// https://pkg.go.dev/github.com/go-vela/worker/internal/spooler#Spooler.Write
+					c.logs.Write(c.logKey(spooler.KindStep, ctn), _log.GetData(), c.uploadStepLogs(ctn))
 
 					// flush the buffer of logs
 					logs.Reset()
@@ -432,6 +758,9 @@ func (c *client) StreamStep(ctx context.Context, ctn *pipeline.Container) error
 	for scanner.Scan() {
 		// write all the logs from the scanner
 		logs.Write(append(scanner.Bytes(), []byte("\n")...))
+
+		// publish the masked line to the clients watching the step
+		c.tail.Publish(ctn.ID, maskLine(scanner.Bytes(), secretValues))
 	}
 
 	logger.Info("finished streaming logs")
@@ -467,8 +796,17 @@ func (c *client) DestroyStep(ctx context.Context, ctn *pipeline.Container) error
 		//
 		// https://pkg.go.dev/github.com/go-vela/worker/internal/step#Upload
 		defer func() {
+			if c.restarting(ctx) {
+				return
+			}
+
 			step.Upload(ctx, ctn, c.build, c.Vela, c.Logger, _step)
 		}()
+
+		// the container was already removed once the step timed out
+		if timedOut(_step) {
+			return nil
+		}
 	}
 
 	logger.Debug("inspecting container")
@@ -508,3 +846,37 @@ func getSecretValues(ctn *pipeline.Container) []string {
 
 	return secretValues
 }
+
+// maskLine is a helper function that masks the secrets
+// within a line of output before it is streamed live.
+func maskLine(line []byte, secrets []string) []byte {
+	_log := new(api.Log)
+	// copy the line since the scanner reuses the buffer
+	_log.SetData(bytes.Clone(line))
+
+	// mask secrets within the line before sending it
+	_log.MaskData(secrets)
+
+	return _log.GetData()
+}
+
+// uploadStepLogs returns the function used by the
+// spooler to upload the logs for the step.
+func (c *client) uploadStepLogs(ctn *pipeline.Container) spooler.UploadFunc {
+	return func(ctx context.Context, data []byte) error {
+		_log := new(api.Log)
+		_log.SetData(data)
+
+		// send API call to update the logs for the step
+		//
+		// https://pkg.go.dev/github.com/go-vela/sdk-go/vela#LogService.UpdateStep
+		_, err := c.Vela.Log.UpdateStep(ctx, c.build.GetRepo().GetOrg(), c.build.GetRepo().GetName(), c.build.GetNumber(), ctn.Number, _log)
+		if err != nil {
+			return err
+		}
+
+		metrics.AddLogBytes(c.metricLabels(), len(data))
+
+		return nil
+	}
+}
@@ -3,11 +3,14 @@
 package linux
 
 import (
+	"bytes"
 	"context"
 	"net/http/httptest"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -16,7 +19,9 @@ import (
 	"github.com/go-vela/server/compiler/types/pipeline"
 	"github.com/go-vela/server/constants"
 	"github.com/go-vela/server/mock/server"
+	"github.com/go-vela/worker/internal/exit"
 	"github.com/go-vela/worker/internal/message"
+	"github.com/go-vela/worker/internal/step"
 	"github.com/go-vela/worker/runtime"
 	"github.com/go-vela/worker/runtime/docker"
 	"github.com/go-vela/worker/runtime/kubernetes"
@@ -593,6 +598,458 @@ func TestLinux_ExecStep_WaitTimeout(t *testing.T) {
 	}
 }
 
+func TestLinux_ExecStep_StepTimeout(t *testing.T) {
+	_build := testBuild()
+
+	gin.SetMode(gin.TestMode)
+
+	s := httptest.NewServer(server.FakeHandler())
+	defer s.Close()
+
+	_client, err := vela.NewClient(s.URL, "", nil)
+	if err != nil {
+		t.Fatalf("unable to create Vela API client: %v", err)
+	}
+
+	_docker, err := docker.NewMock()
+	if err != nil {
+		t.Fatalf("unable to create docker runtime engine: %v", err)
+	}
+
+	streamRequests, done := message.MockStreamRequestsWithCancel(context.Background())
+	defer done()
+
+	_engine, err := New(
+		WithBuild(_build),
+		WithPipeline(new(pipeline.Build)),
+		WithRuntime(_docker),
+		WithVelaClient(_client),
+		WithStepTimeout(time.Hour),
+		withStreamRequests(streamRequests),
+	)
+	if err != nil {
+		t.Fatalf("unable to create executor engine: %v", err)
+	}
+
+	container := &pipeline.Container{
+		ID:          "step_github_octocat_1_wait-hang",
+		Directory:   "/vela/src/github.com/github/octocat",
+		Environment: map[string]string{"FOO": "bar", "VELA_STEP_TIMEOUT": "10ms"},
+		Image:       "alpine:latest",
+		Name:        "echo",
+		Number:      1,
+		Pull:        "not_present",
+	}
+
+	stepEntry := api.StepFromBuildContainer(_build, container)
+	_engine.steps.Store(container.ID, stepEntry)
+	_engine.stepLogs.Store(container.ID, new(api.Log))
+
+	// a timed out step should not stop the build
+	err = _engine.ExecStep(context.Background(), container)
+	if err != nil {
+		t.Fatalf("ExecStep returned err: %v", err)
+	}
+
+	if got := stepEntry.GetStatus(); got != constants.StatusError {
+		t.Errorf("step status = %s, want %s", got, constants.StatusError)
+	}
+
+	if got := stepEntry.GetExitCode(); got != step.ExitCodeTimeout {
+		t.Errorf("step exit code = %d, want %d", got, step.ExitCodeTimeout)
+	}
+
+	if container.ExitCode != step.ExitCodeTimeout {
+		t.Errorf("container exit code = %d, want %d", container.ExitCode, step.ExitCodeTimeout)
+	}
+
+	if got := _build.GetStatus(); got != constants.StatusFailure {
+		t.Errorf("build status = %s, want %s", got, constants.StatusFailure)
+	}
+
+	// the container was already removed once the step timed out
+	err = _engine.DestroyStep(context.Background(), container)
+	if err != nil {
+		t.Errorf("DestroyStep returned err: %v", err)
+	}
+}
+
+func TestLinux_stageLimit(t *testing.T) {
+	// setup types
+	_deadline := &stageDeadline{
+		timeout:  time.Hour,
+		deadline: time.Now().Add(time.Hour),
+	}
+
+	_expired := &stageDeadline{
+		timeout:  time.Minute,
+		deadline: time.Now().Add(-time.Minute),
+	}
+
+	// setup tests
+	tests := []struct {
+		name     string
+		deadline *stageDeadline
+		timeout  time.Duration
+		stage    bool
+	}{
+		{
+			name:    "no stage",
+			timeout: time.Minute,
+			stage:   false,
+		},
+		{
+			name:     "step timeout before stage",
+			deadline: _deadline,
+			timeout:  time.Minute,
+			stage:    false,
+		},
+		{
+			name:     "stage timeout before step",
+			deadline: _deadline,
+			timeout:  2 * time.Hour,
+			stage:    true,
+		},
+		{
+			name:     "step timeout disabled",
+			deadline: _deadline,
+			timeout:  0,
+			stage:    true,
+		},
+		{
+			name:     "stage timeout exceeded",
+			deadline: _expired,
+			timeout:  0,
+			stage:    true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.deadline != nil {
+				ctx = context.WithValue(ctx, stageDeadlineKey{}, test.deadline)
+			}
+
+			got, msg := stageLimit(ctx, test.timeout)
+
+			if !test.stage {
+				if got != test.timeout {
+					t.Errorf("stageLimit is %v, want %v", got, test.timeout)
+				}
+
+				if !strings.HasPrefix(msg, "step exceeded timeout") {
+					t.Errorf("stageLimit message is %s, want step exceeded timeout", msg)
+				}
+
+				return // continue to next test
+			}
+
+			if got <= 0 || got > test.deadline.timeout {
+				t.Errorf("stageLimit is %v, want time remaining for stage", got)
+			}
+
+			if !strings.HasPrefix(msg, "stage exceeded timeout") {
+				t.Errorf("stageLimit message is %s, want stage exceeded timeout", msg)
+			}
+		})
+	}
+}
+
+func TestLinux_ExecStep_Retry(t *testing.T) {
+	_build := testBuild()
+
+	gin.SetMode(gin.TestMode)
+
+	s := httptest.NewServer(server.FakeHandler())
+	defer s.Close()
+
+	_client, err := vela.NewClient(s.URL, "", nil)
+	if err != nil {
+		t.Fatalf("unable to create Vela API client: %v", err)
+	}
+
+	_docker, err := docker.NewMock()
+	if err != nil {
+		t.Fatalf("unable to create docker runtime engine: %v", err)
+	}
+
+	ctx, done := context.WithCancel(context.Background())
+	defer done()
+
+	// stream the output of every attempt so the step can be retried
+	streamRequests := testStreamRequests(ctx)
+
+	_engine, err := New(
+		WithBuild(_build),
+		WithPipeline(new(pipeline.Build)),
+		WithRuntime(_docker),
+		WithVelaClient(_client),
+		withStreamRequests(streamRequests),
+	)
+	if err != nil {
+		t.Fatalf("unable to create executor engine: %v", err)
+	}
+
+	container := &pipeline.Container{
+		ID:        "step_github_octocat_1_exit-failure",
+		Directory: "/vela/src/github.com/github/octocat",
+		Environment: map[string]string{
+			"FOO":                  "bar",
+			step.RetryAttemptsKey:  "2",
+			step.RetryBackoffKey:   "0s",
+			step.RetryExitCodesKey: "1",
+		},
+		Image:  "alpine:latest",
+		Name:   "echo",
+		Number: 1,
+		Pull:   "not_present",
+	}
+
+	stepEntry := api.StepFromBuildContainer(_build, container)
+	_engine.steps.Store(container.ID, stepEntry)
+	_engine.stepLogs.Store(container.ID, new(api.Log))
+
+	err = _engine.ExecStep(ctx, container)
+	if err != nil {
+		t.Fatalf("ExecStep returned err: %v", err)
+	}
+
+	if container.ExitCode != 1 {
+		t.Errorf("container exit code = %d, want %d", container.ExitCode, 1)
+	}
+
+	want := "step failed after 2 attempts"
+	if got := stepEntry.GetError(); got != want {
+		t.Errorf("step error = %s, want %s", got, want)
+	}
+}
+
+// flakyRuntime is a runtime where the containers
+// exit unsuccessfully for the first inspections.
+type flakyRuntime struct {
+	runtime.Engine
+
+	failures int
+}
+
+func (r *flakyRuntime) InspectContainer(ctx context.Context, ctn *pipeline.Container) error {
+	err := r.Engine.InspectContainer(ctx, ctn)
+	if err != nil {
+		return err
+	}
+
+	if r.failures > 0 {
+		r.failures--
+
+		ctn.ExitCode = 1
+	}
+
+	return nil
+}
+
+func TestLinux_ExecStep_RetrySuccess(t *testing.T) {
+	_build := testBuild()
+
+	gin.SetMode(gin.TestMode)
+
+	s := httptest.NewServer(server.FakeHandler())
+	defer s.Close()
+
+	_client, err := vela.NewClient(s.URL, "", nil)
+	if err != nil {
+		t.Fatalf("unable to create Vela API client: %v", err)
+	}
+
+	_docker, err := docker.NewMock()
+	if err != nil {
+		t.Fatalf("unable to create docker runtime engine: %v", err)
+	}
+
+	ctx, done := context.WithCancel(context.Background())
+	defer done()
+
+	// stream the output of every attempt so the step can be retried
+	streamRequests := testStreamRequests(ctx)
+
+	_engine, err := New(
+		WithBuild(_build),
+		WithPipeline(new(pipeline.Build)),
+		WithRuntime(&flakyRuntime{Engine: _docker, failures: 1}),
+		WithVelaClient(_client),
+		withStreamRequests(streamRequests),
+	)
+	if err != nil {
+		t.Fatalf("unable to create executor engine: %v", err)
+	}
+
+	container := &pipeline.Container{
+		ID:        "step_github_octocat_1_echo",
+		Directory: "/vela/src/github.com/github/octocat",
+		Environment: map[string]string{
+			"FOO":                  "bar",
+			step.RetryAttemptsKey:  "3",
+			step.RetryBackoffKey:   "0s",
+			step.RetryExitCodesKey: "1",
+		},
+		Image:  "alpine:latest",
+		Name:   "echo",
+		Number: 1,
+		Pull:   "not_present",
+	}
+
+	stepEntry := api.StepFromBuildContainer(_build, container)
+	_engine.steps.Store(container.ID, stepEntry)
+	_engine.stepLogs.Store(container.ID, new(api.Log))
+
+	err = _engine.ExecStep(ctx, container)
+	if err != nil {
+		t.Fatalf("ExecStep returned err: %v", err)
+	}
+
+	if container.ExitCode != 0 {
+		t.Errorf("container exit code = %d, want %d", container.ExitCode, 0)
+	}
+
+	// a step that passed after retrying is not reported with an error
+	if got := stepEntry.GetError(); len(got) > 0 {
+		t.Errorf("step error = %s, want empty", got)
+	}
+
+	_log, err := step.LoadLogs(container, &_engine.stepLogs)
+	if err != nil {
+		t.Fatalf("unable to load logs for step: %v", err)
+	}
+
+	want := "--- step succeeded after 2 attempts ---"
+	if got := string(_log.GetData()); !strings.Contains(got, want) {
+		t.Errorf("step logs = %s, want %s", got, want)
+	}
+}
+
+func TestLinux_recordStepAttempts(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name     string
+		attempt  int
+		exitCode int32
+		err      string
+		want     string
+	}{
+		{
+			name:     "first attempt",
+			attempt:  1,
+			exitCode: 1,
+			want:     "",
+		},
+		{
+			name:     "succeeded after retrying",
+			attempt:  2,
+			exitCode: 0,
+			want:     "",
+		},
+		{
+			name:     "failed after retrying",
+			attempt:  3,
+			exitCode: 1,
+			want:     "step failed after 3 attempts",
+		},
+		{
+			name:     "timed out while retrying",
+			attempt:  2,
+			exitCode: step.ExitCodeTimeout,
+			err:      "step exceeded timeout of 1m0s",
+			want:     "step exceeded timeout of 1m0s after 2 attempts",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := new(api.Step)
+
+			if len(test.err) > 0 {
+				s.SetError(test.err)
+			}
+
+			recordStepAttempts(s, test.attempt, test.exitCode)
+
+			if got := s.GetError(); got != test.want {
+				t.Errorf("recordStepAttempts is %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLinux_ExecStep_ExitReason(t *testing.T) {
+	_build := testBuild()
+
+	gin.SetMode(gin.TestMode)
+
+	s := httptest.NewServer(server.FakeHandler())
+	defer s.Close()
+
+	_client, err := vela.NewClient(s.URL, "", nil)
+	if err != nil {
+		t.Fatalf("unable to create Vela API client: %v", err)
+	}
+
+	_docker, err := docker.NewMock()
+	if err != nil {
+		t.Fatalf("unable to create docker runtime engine: %v", err)
+	}
+
+	ctx, done := context.WithCancel(context.Background())
+	defer done()
+
+	// stream the output of the step so the exit reason can be logged
+	streamRequests := testStreamRequests(ctx)
+
+	_engine, err := New(
+		WithBuild(_build),
+		WithPipeline(new(pipeline.Build)),
+		WithRuntime(_docker),
+		WithVelaClient(_client),
+		withStreamRequests(streamRequests),
+	)
+	if err != nil {
+		t.Fatalf("unable to create executor engine: %v", err)
+	}
+
+	container := &pipeline.Container{
+		ID:          "step_github_octocat_1_oom-killed",
+		Directory:   "/vela/src/github.com/github/octocat",
+		Environment: map[string]string{"FOO": "bar"},
+		Image:       "alpine:latest",
+		Name:        "echo",
+		Number:      1,
+		Pull:        "not_present",
+	}
+
+	stepEntry := api.StepFromBuildContainer(_build, container)
+	_engine.steps.Store(container.ID, stepEntry)
+	_engine.stepLogs.Store(container.ID, new(api.Log))
+
+	err = _engine.ExecStep(ctx, container)
+	if err != nil {
+		t.Fatalf("ExecStep returned err: %v", err)
+	}
+
+	if container.ExitCode != 137 {
+		t.Errorf("container exit code = %d, want %d", container.ExitCode, 137)
+	}
+
+	if got := stepEntry.GetError(); !strings.Contains(got, exit.ReasonOOMKilled) {
+		t.Errorf("step error = %s, want it to contain %s", got, exit.ReasonOOMKilled)
+	}
+
+	_log, _ := _engine.stepLogs.Load(container.ID)
+	if got := string(_log.(*api.Log).GetData()); !strings.Contains(got, exit.ReasonOOMKilled) {
+		t.Errorf("step logs = %q, want them to contain %s", got, exit.ReasonOOMKilled)
+	}
+}
+
 func TestLinux_ExecStep_WaitError(t *testing.T) {
 	_build := testBuild()
 
@@ -1148,3 +1605,39 @@ func TestLinux_getSecretValues(t *testing.T) {
 		})
 	}
 }
+
+func TestLinux_maskLine(t *testing.T) {
+	// setup types
+	line := []byte("logging in with secretPass")
+
+	got := maskLine(line, []string{"secretPass"})
+
+	if bytes.Contains(got, []byte("secretPass")) {
+		t.Errorf("maskLine is %s, want secret masked", got)
+	}
+
+	// the original line should not be modified
+	if string(line) != "logging in with secretPass" {
+		t.Errorf("maskLine modified line to %s", line)
+	}
+}
+
+// testStreamRequests is a helper function to return the channel
+// for stream requests that streams the output of every container
+// until the context is canceled.
+func testStreamRequests(ctx context.Context) chan message.StreamRequest {
+	streamRequests := make(chan message.StreamRequest)
+
+	go func() {
+		for {
+			select {
+			case req := <-streamRequests:
+				go func() { _ = req.Stream(ctx, req.Container) }()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return streamRequests
+}
@@ -34,6 +34,14 @@ func (c *client) GetPipeline() (*pipeline.Build, error) {
 	return c.pipeline, nil
 }
 
+// TailLogs subscribes to the live output of a container in the current build.
+//
+// The local executor already writes the output to stdout
+// so streaming the live output is not supported.
+func (c *client) TailLogs(_ context.Context, _ *pipeline.Container) (<-chan []byte, error) {
+	return nil, fmt.Errorf("live logs are not supported by the local executor")
+}
+
 // CancelBuild cancels the current build in execution.
 func (c *client) CancelBuild() (*api.Build, error) {
 	// get the current build from the client
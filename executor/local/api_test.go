@@ -3,6 +3,7 @@
 package local
 
 import (
+	"context"
 	"reflect"
 	"testing"
 )
@@ -112,3 +113,20 @@ func TestLocal_GetPipeline(t *testing.T) {
 		})
 	}
 }
+
+func TestLocal_TailLogs(t *testing.T) {
+	// setup types
+	_steps := testSteps()
+
+	_engine, err := New(
+		WithPipeline(_steps),
+	)
+	if err != nil {
+		t.Errorf("unable to create executor engine: %v", err)
+	}
+
+	_, err = _engine.TailLogs(context.Background(), _steps.Steps[0])
+	if err == nil {
+		t.Errorf("TailLogs should have returned err")
+	}
+}
@@ -41,6 +41,24 @@ type Setup struct {
 	// specifies how long to wait after the build finishes
 	// for log streaming to complete
 	LogStreamingTimeout time.Duration
+	// specifies the directory for spilling logs to disk
+	// when they are unable to be uploaded to the server
+	LogSpoolDir string
+	// specifies the default maximum time a step may run
+	// when it doesn't provide a timeout of its own
+	StepTimeout time.Duration
+	// specifies the default maximum time the steps of a
+	// stage may run when it doesn't provide a timeout of its own
+	StageTimeout time.Duration
+	// specifies the default maximum number of times a failed step runs
+	RetryAttempts int
+	// specifies the default delay before retrying a failed step
+	RetryBackoff time.Duration
+	// specifies the default exit codes a failed step is retried for
+	RetryExitCodes []int32
+	// specifies whether the worker restarts the build after an
+	// infrastructure failure before any step runs
+	Restartable bool
 	// specifies a list of privileged images to use
 	PrivilegedImages []string
 	// configuration for enforcing that only trusted repos may run privileged images
@@ -86,6 +104,11 @@ func (s *Setup) Linux() (Engine, error) {
 		linux.WithFileSizeLimit(s.FileSizeLimit),
 		linux.WithBuildFileSizeLimit(s.BuildFileSizeLimit),
 		linux.WithLogStreamingTimeout(s.LogStreamingTimeout),
+		linux.WithLogSpoolDir(s.LogSpoolDir),
+		linux.WithStepTimeout(s.StepTimeout),
+		linux.WithStageTimeout(s.StageTimeout),
+		linux.WithRetryPolicy(s.RetryAttempts, s.RetryBackoff, s.RetryExitCodes),
+		linux.WithRestartable(s.Restartable),
 		linux.WithPrivilegedImages(s.PrivilegedImages),
 		linux.WithEnforceTrustedRepos(s.EnforceTrustedRepos),
 		linux.WithHostname(s.Hostname),
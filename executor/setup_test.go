@@ -75,6 +75,7 @@ func TestExecutor_Setup_Linux(t *testing.T) {
 		linux.WithFileSizeLimit(10),
 		linux.WithBuildFileSizeLimit(100),
 		linux.WithLogStreamingTimeout(1*time.Second),
+		linux.WithLogSpoolDir("/tmp/vela/logs"),
 		linux.WithHostname("localhost"),
 		linux.WithPipeline(_pipeline),
 		linux.WithRuntime(_runtime),
@@ -92,6 +93,7 @@ func TestExecutor_Setup_Linux(t *testing.T) {
 		MaxLogSize:         2097152,
 		FileSizeLimit:      10,
 		BuildFileSizeLimit: 100,
+		LogSpoolDir:        "/tmp/vela/logs",
 		Hostname:           "localhost",
 		Pipeline:           _pipeline,
 		Runtime:            _runtime,
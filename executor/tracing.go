@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package executor
+
+import (
+	"context"
+
+	"github.com/go-vela/worker/internal/tracing"
+)
+
+// traced represents a Vela engine that creates a
+// span for every phase of the build lifecycle.
+//
+// The spans for the steps, stages and services are
+// created by the engines as the build is executed.
+type traced struct {
+	Engine
+}
+
+// WithTracing returns a Vela engine that creates a span for
+// every phase of the build lifecycle for the provided engine.
+func WithTracing(e Engine) Engine {
+	return &traced{Engine: e}
+}
+
+// CreateBuild creates a span for configuring the build for execution.
+func (t *traced) CreateBuild(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "executor.CreateBuild")
+	defer func() { tracing.End(span, err) }()
+
+	return t.Engine.CreateBuild(ctx)
+}
+
+// PlanBuild creates a span for preparing the build for execution.
+func (t *traced) PlanBuild(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "executor.PlanBuild")
+	defer func() { tracing.End(span, err) }()
+
+	return t.Engine.PlanBuild(ctx)
+}
+
+// AssembleBuild creates a span for preparing the containers for execution.
+func (t *traced) AssembleBuild(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "executor.AssembleBuild")
+	defer func() { tracing.End(span, err) }()
+
+	return t.Engine.AssembleBuild(ctx)
+}
+
+// ExecBuild creates a span for running the build.
+func (t *traced) ExecBuild(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "executor.ExecBuild")
+	defer func() { tracing.End(span, err) }()
+
+	return t.Engine.ExecBuild(ctx)
+}
+
+// StreamBuild creates a span for streaming the logs of the build.
+func (t *traced) StreamBuild(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "executor.StreamBuild")
+	defer func() { tracing.End(span, err) }()
+
+	return t.Engine.StreamBuild(ctx)
+}
+
+// DestroyBuild creates a span for cleaning up the build after execution.
+func (t *traced) DestroyBuild(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "executor.DestroyBuild")
+	defer func() { tracing.End(span, err) }()
+
+	return t.Engine.DestroyBuild(ctx)
+}
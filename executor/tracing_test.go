@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// failingEngine represents an engine that fails to create the build.
+type failingEngine struct {
+	Engine
+}
+
+func (failingEngine) CreateBuild(context.Context) error {
+	return errors.New("test")
+}
+
+func TestExecutor_WithTracing(t *testing.T) {
+	// setup types
+	recorder := tracetest.NewSpanRecorder()
+
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(sdktrace.NewTracerProvider()) })
+
+	_engine := WithTracing(failingEngine{})
+
+	// run test
+	err := _engine.CreateBuild(context.Background())
+	if err == nil {
+		t.Errorf("CreateBuild should have returned err")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("WithTracing ended %d spans, want 1", len(spans))
+	}
+
+	if spans[0].Name() != "executor.CreateBuild" {
+		t.Errorf("WithTracing span is %s, want %s", spans[0].Name(), "executor.CreateBuild")
+	}
+
+	if spans[0].Status().Code != codes.Error {
+		t.Errorf("WithTracing status is %v, want %v", spans[0].Status().Code, codes.Error)
+	}
+}
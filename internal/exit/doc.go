@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package exit provides the ability for Vela to describe
+// why a container exited when it was terminated by the
+// infrastructure rather than by the commands it executed,
+// such as when the container ran out of memory.
+//
+// Usage:
+//
+//	import "github.com/go-vela/worker/internal/exit"
+package exit
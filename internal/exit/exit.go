@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package exit
+
+import "fmt"
+
+const (
+	// ReasonOOMKilled represents the reason for a container
+	// killed after exceeding the memory available to it.
+	ReasonOOMKilled = "OOMKilled"
+
+	// ReasonRuntimeError represents the reason for a container
+	// the runtime failed to run, i.e. the entrypoint was not found.
+	ReasonRuntimeError = "RuntimeError"
+
+	// ReasonEvicted represents the reason for a container killed
+	// after the node ran low on resources, i.e. memory or disk.
+	ReasonEvicted = "Evicted"
+)
+
+// Reason represents why a container exited when it
+// was terminated by the infrastructure.
+type Reason struct {
+	// Reason is the short reason reported by the runtime (i.e. OOMKilled)
+	Reason string
+	// Message is the detail reported by the runtime, if any
+	Message string
+}
+
+// String returns the description of the reason
+// for the step error and the logs of the container.
+func (r *Reason) String() string {
+	var msg string
+
+	switch r.Reason {
+	case ReasonOOMKilled:
+		msg = "container was killed after running out of memory (OOMKilled), consider raising the memory limit for the step"
+	default:
+		msg = fmt.Sprintf("container was terminated by the runtime (%s)", r.Reason)
+	}
+
+	if len(r.Message) > 0 {
+		msg = fmt.Sprintf("%s: %s", msg, r.Message)
+	}
+
+	return msg
+}
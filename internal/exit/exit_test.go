@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package exit
+
+import (
+	"testing"
+)
+
+func TestExit_Reason_String(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name   string
+		reason *Reason
+		want   string
+	}{
+		{
+			name:   "oom killed",
+			reason: &Reason{Reason: ReasonOOMKilled},
+			want:   "container was killed after running out of memory (OOMKilled), consider raising the memory limit for the step",
+		},
+		{
+			name:   "runtime error",
+			reason: &Reason{Reason: ReasonRuntimeError, Message: "exec: \"foo\": executable file not found in $PATH"},
+			want:   "container was terminated by the runtime (RuntimeError): exec: \"foo\": executable file not found in $PATH",
+		},
+		{
+			name:   "other reason",
+			reason: &Reason{Reason: "DeadlineExceeded"},
+			want:   "container was terminated by the runtime (DeadlineExceeded)",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.reason.String()
+
+			if got != test.want {
+				t.Errorf("String is %s, want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package health provides the ability for Vela to report
+// the liveness and readiness of the worker.
+//
+// The readiness of the worker is determined by checking
+// the dependencies required to execute builds.
+//
+// Usage:
+//
+//	import "github.com/go-vela/worker/internal/health"
+package health
@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// StatusOK represents the status for a healthy check or report.
+	StatusOK = "ok"
+	// StatusUnavailable represents the status for an unhealthy check or report.
+	StatusUnavailable = "unavailable"
+
+	// DefaultTimeout represents the default time to wait for each check.
+	DefaultTimeout = 5 * time.Second
+)
+
+// CheckFunc represents a function to verify a dependency of the worker.
+type CheckFunc func(context.Context) error
+
+// InfoFunc represents a function to capture details about the worker.
+type InfoFunc func() any
+
+// Check represents the result of verifying a dependency of the worker.
+type Check struct {
+	// Status is the status of the dependency (i.e. ok)
+	Status string `json:"status"`
+	// Error is the reason the dependency is unavailable
+	Error string `json:"error,omitempty"`
+}
+
+// Report represents the results of verifying the dependencies of the worker.
+type Report struct {
+	// Status is ok if all dependencies are ok and unavailable otherwise
+	Status string `json:"status"`
+	// Checks are the results for each dependency by name
+	Checks map[string]Check `json:"checks,omitempty"`
+	// Info are the details about the worker by name (i.e. executors)
+	Info map[string]any `json:"info,omitempty"`
+}
+
+// OK returns true if all dependencies in the report are ok.
+func (r *Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+// Checker represents the checks for the liveness and readiness of the worker.
+type Checker struct {
+	timeout time.Duration
+	live    map[string]CheckFunc
+	ready   map[string]CheckFunc
+	info    map[string]InfoFunc
+}
+
+// New returns a Checker that waits up to the timeout for each check.
+//
+// If no timeout is provided, the DefaultTimeout is used.
+func New(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &Checker{
+		timeout: timeout,
+		live:    make(map[string]CheckFunc),
+		ready:   make(map[string]CheckFunc),
+		info:    make(map[string]InfoFunc),
+	}
+}
+
+// AddLive adds a check to verify the worker is alive.
+//
+// Liveness checks should only fail if restarting
+// the worker is required for it to recover.
+func (c *Checker) AddLive(name string, fn CheckFunc) {
+	c.live[name] = fn
+}
+
+// AddReady adds a check to verify the worker is ready to execute builds.
+func (c *Checker) AddReady(name string, fn CheckFunc) {
+	c.ready[name] = fn
+}
+
+// AddInfo adds details about the worker to the readiness
+// report without affecting the status of the report.
+func (c *Checker) AddInfo(name string, fn InfoFunc) {
+	c.info[name] = fn
+}
+
+// Live runs the liveness checks and returns the report.
+func (c *Checker) Live(ctx context.Context) *Report {
+	return c.run(ctx, c.live)
+}
+
+// Ready runs the readiness checks and returns the report
+// with the details about the worker.
+func (c *Checker) Ready(ctx context.Context) *Report {
+	r := c.run(ctx, c.ready)
+
+	if len(c.info) == 0 {
+		return r
+	}
+
+	r.Info = make(map[string]any, len(c.info))
+
+	for name, fn := range c.info {
+		r.Info[name] = fn()
+	}
+
+	return r
+}
+
+// run is a helper function to run the checks
+// concurrently and capture the results in a report.
+func (c *Checker) run(ctx context.Context, checks map[string]CheckFunc) *Report {
+	r := &Report{
+		Status: StatusOK,
+		Checks: make(map[string]Check, len(checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for name, fn := range checks {
+		wg.Go(func() {
+			ctx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+
+			check := Check{Status: StatusOK}
+
+			err := fn(ctx)
+			if err != nil {
+				check.Status = StatusUnavailable
+				check.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			r.Checks[name] = check
+
+			if err != nil {
+				r.Status = StatusUnavailable
+			}
+		})
+	}
+
+	wg.Wait()
+
+	return r
+}
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package health
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestHealth_New(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		want    time.Duration
+	}{
+		{
+			name:    "timeout",
+			timeout: time.Second,
+			want:    time.Second,
+		},
+		{
+			name:    "zero timeout",
+			timeout: 0,
+			want:    DefaultTimeout,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := New(test.timeout)
+
+			if got.timeout != test.want {
+				t.Errorf("New timeout is %v, want %v", got.timeout, test.want)
+			}
+		})
+	}
+}
+
+func TestHealth_Checker_Ready(t *testing.T) {
+	// setup types
+	ok := func(context.Context) error { return nil }
+	fail := func(context.Context) error { return errors.New("connection refused") }
+	slow := func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return ctx.Err()
+	}
+
+	// setup tests
+	tests := []struct {
+		name   string
+		checks map[string]CheckFunc
+		want   *Report
+	}{
+		{
+			name:   "ok",
+			checks: map[string]CheckFunc{"runtime": ok, "queue": ok},
+			want: &Report{
+				Status: StatusOK,
+				Checks: map[string]Check{
+					"runtime": {Status: StatusOK},
+					"queue":   {Status: StatusOK},
+				},
+			},
+		},
+		{
+			name:   "unavailable",
+			checks: map[string]CheckFunc{"runtime": ok, "queue": fail},
+			want: &Report{
+				Status: StatusUnavailable,
+				Checks: map[string]Check{
+					"runtime": {Status: StatusOK},
+					"queue":   {Status: StatusUnavailable, Error: "connection refused"},
+				},
+			},
+		},
+		{
+			name:   "timeout",
+			checks: map[string]CheckFunc{"runtime": slow},
+			want: &Report{
+				Status: StatusUnavailable,
+				Checks: map[string]Check{
+					"runtime": {Status: StatusUnavailable, Error: context.DeadlineExceeded.Error()},
+				},
+			},
+		},
+		{
+			name:   "no checks",
+			checks: nil,
+			want: &Report{
+				Status: StatusOK,
+				Checks: map[string]Check{},
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := New(10 * time.Millisecond)
+
+			for name, fn := range test.checks {
+				c.AddReady(name, fn)
+			}
+
+			got := c.Ready(context.Background())
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Ready is %v, want %v", got, test.want)
+			}
+
+			if got.OK() != (test.want.Status == StatusOK) {
+				t.Errorf("OK is %v, want %v", got.OK(), test.want.Status == StatusOK)
+			}
+		})
+	}
+}
+
+func TestHealth_Checker_Live(t *testing.T) {
+	// setup types
+	c := New(time.Second)
+
+	c.AddLive("api", func(context.Context) error { return nil })
+	c.AddReady("queue", func(context.Context) error { return errors.New("not checked in") })
+
+	want := &Report{
+		Status: StatusOK,
+		Checks: map[string]Check{
+			"api": {Status: StatusOK},
+		},
+	}
+
+	got := c.Live(context.Background())
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Live is %v, want %v", got, want)
+	}
+}
+
+func TestHealth_Checker_Info(t *testing.T) {
+	// setup types
+	c := New(time.Second)
+
+	c.AddReady("queue", func(context.Context) error { return nil })
+	c.AddInfo("executors", func() any { return map[string]int{"running": 1, "limit": 2} })
+
+	want := &Report{
+		Status: StatusOK,
+		Checks: map[string]Check{
+			"queue": {Status: StatusOK},
+		},
+		Info: map[string]any{
+			"executors": map[string]int{"running": 1, "limit": 2},
+		},
+	}
+
+	got := c.Ready(context.Background())
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Ready is %v, want %v", got, want)
+	}
+
+	// the details are only reported for readiness
+	if got := c.Live(context.Background()); got.Info != nil {
+		t.Errorf("Live info is %v, want nil", got.Info)
+	}
+}
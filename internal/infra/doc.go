@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package infra provides the ability for Vela to tell
+// failures of the infrastructure running a build, such
+// as an unavailable Docker daemon or Kubernetes API,
+// apart from failures caused by the pipeline itself.
+//
+// Usage:
+//
+//	import "github.com/go-vela/worker/internal/infra"
+package infra
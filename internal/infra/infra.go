@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package infra
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	cerrdefs "github.com/containerd/errdefs"
+	mobyClient "github.com/moby/moby/client"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Error represents a failure of the infrastructure running a build.
+type Error struct {
+	Err error
+}
+
+// Error returns the message of the underlying error.
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap marks the error as a failure of the infrastructure.
+func Wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &Error{Err: err}
+}
+
+// Is returns true if the error was caused by the infrastructure
+// running the build rather than the pipeline for the build.
+//
+// Errors marked with Wrap or Runtime are caused by the infrastructure
+// unless the build timed out or was canceled. Otherwise, the error is
+// checked for the failures of the Docker daemon or Kubernetes API that
+// are unrelated to the pipeline, i.e. a registry timing out on a pull.
+func Is(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// the build timing out or being canceled is not an infrastructure failure
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var infraErr *Error
+	if errors.As(err, &infraErr) {
+		return true
+	}
+
+	return isDocker(err) || isKubernetes(err)
+}
+
+// Runtime marks the failures to reach the runtime over the network
+// as failures of the infrastructure. It is only used for the errors
+// returned by the runtime since the network failures of other services,
+// i.e. uploading logs to the server, are not related to the runtime.
+func Runtime(err error) error {
+	var infraErr *Error
+	if err == nil || errors.As(err, &infraErr) {
+		return err
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return Wrap(err)
+	}
+
+	return err
+}
+
+// isDocker is a helper function to check if the error
+// is a failure of the Docker daemon or a registry.
+func isDocker(err error) bool {
+	// https://pkg.go.dev/github.com/moby/moby/client#IsErrConnectionFailed
+	if mobyClient.IsErrConnectionFailed(err) {
+		return true
+	}
+
+	// registry failures, i.e. timeouts, are reported by the daemon
+	// as internal errors while a missing image is not found
+	//
+	// https://pkg.go.dev/github.com/containerd/errdefs
+	return cerrdefs.IsInternal(err) ||
+		cerrdefs.IsUnavailable(err) ||
+		cerrdefs.IsResourceExhausted(err) ||
+		cerrdefs.IsDataLoss(err)
+}
+
+// isKubernetes is a helper function to check if the error
+// is a failure of the Kubernetes API or the cluster.
+func isKubernetes(err error) bool {
+	// https://pkg.go.dev/k8s.io/apimachinery/pkg/api/errors
+	if apierrors.IsServerTimeout(err) ||
+		apierrors.IsTimeout(err) ||
+		apierrors.IsTooManyRequests(err) ||
+		apierrors.IsInternalError(err) ||
+		apierrors.IsServiceUnavailable(err) ||
+		apierrors.IsUnexpectedServerError(err) {
+		return true
+	}
+
+	// pods are forbidden when the namespace has no quota left, but
+	// also when the pipeline violates a policy for the namespace
+	return apierrors.IsForbidden(err) && strings.Contains(err.Error(), "exceeded quota")
+}
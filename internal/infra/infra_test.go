@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package infra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	cerrdefs "github.com/containerd/errdefs"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestInfra_Is(t *testing.T) {
+	// setup types
+	pods := schema.GroupResource{Resource: "pods"}
+
+	// setup tests
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "wrapped",
+			err:  fmt.Errorf("unable to create network: %w", Wrap(errors.New("test"))),
+			want: true,
+		},
+		{
+			name: "wrapped build timeout",
+			err:  Wrap(fmt.Errorf("unable to pull image: %w", context.DeadlineExceeded)),
+			want: false,
+		},
+		{
+			name: "pipeline",
+			err:  errors.New("attempting to use privileged image as untrusted repo"),
+			want: false,
+		},
+		{
+			name: "build timeout",
+			err:  fmt.Errorf("unable to create step: %w", context.DeadlineExceeded),
+			want: false,
+		},
+		{
+			name: "docker internal",
+			err:  fmt.Errorf("unable to create step: %w", cerrdefs.ErrInternal),
+			want: true,
+		},
+		{
+			name: "docker unavailable",
+			err:  cerrdefs.ErrUnavailable,
+			want: true,
+		},
+		{
+			name: "docker image not found",
+			err:  fmt.Errorf("unable to create step: %w", cerrdefs.ErrNotFound),
+			want: false,
+		},
+		{
+			name: "kubernetes timeout",
+			err:  apierrors.NewServerTimeout(pods, "create", 1),
+			want: true,
+		},
+		{
+			name: "kubernetes quota",
+			err:  apierrors.NewForbidden(pods, "github-octocat-1", errors.New("exceeded quota: compute-resources")),
+			want: true,
+		},
+		{
+			name: "kubernetes policy",
+			err:  apierrors.NewForbidden(pods, "github-octocat-1", errors.New("violates PodSecurity")),
+			want: false,
+		},
+		{
+			name: "kubernetes invalid",
+			err:  apierrors.NewBadRequest("invalid pod"),
+			want: false,
+		},
+		{
+			name: "network",
+			err:  &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")},
+			want: false,
+		},
+		{
+			name: "runtime network",
+			err:  Runtime(&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}),
+			want: true,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := Is(test.err)
+
+			if got != test.want {
+				t.Errorf("Is is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestInfra_Wrap(t *testing.T) {
+	// setup types
+	err := errors.New("test")
+
+	// run test
+	got := Wrap(err)
+
+	if !errors.Is(got, err) {
+		t.Errorf("Wrap is %v, want it to wrap %v", got, err)
+	}
+
+	if got.Error() != err.Error() {
+		t.Errorf("Wrap message is %s, want %s", got.Error(), err.Error())
+	}
+
+	if Wrap(nil) != nil {
+		t.Errorf("Wrap is %v, want nil", Wrap(nil))
+	}
+}
+
+func TestInfra_Runtime(t *testing.T) {
+	// setup types
+	dial := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	wrapped := Wrap(errors.New("test"))
+
+	// setup tests
+	tests := []struct {
+		name  string
+		err   error
+		infra bool
+	}{
+		{
+			name:  "nil",
+			err:   nil,
+			infra: false,
+		},
+		{
+			name:  "dial",
+			err:   fmt.Errorf("unable to create pod: %w", dial),
+			infra: true,
+		},
+		{
+			name:  "already wrapped",
+			err:   wrapped,
+			infra: true,
+		},
+		{
+			name:  "pipeline",
+			err:   errors.New("attempting to use privileged image as untrusted repo"),
+			infra: false,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := Runtime(test.err)
+
+			if !errors.Is(got, test.err) {
+				t.Errorf("Runtime is %v, want it to wrap %v", got, test.err)
+			}
+
+			var infraErr *Error
+			if errors.As(got, &infraErr) != test.infra {
+				t.Errorf("Runtime infrastructure is %v, want %v", !test.infra, test.infra)
+			}
+		})
+	}
+}
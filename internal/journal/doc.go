@@ -0,0 +1,13 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package journal provides the ability for Vela to record
+// the builds in flight on the worker to local disk.
+//
+// The journal is reconciled when the worker starts to clean
+// up the runtime resources and report the builds that were
+// interrupted by a worker crash or restart.
+//
+// Usage:
+//
+//	import "github.com/go-vela/worker/internal/journal"
+package journal
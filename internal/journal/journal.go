@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ext represents the file extension for the journal entries.
+const ext = ".json"
+
+// Entry represents the record for a build in flight on the worker.
+type Entry struct {
+	// ID is the unique identifier for the build
+	BuildID int64 `json:"build_id"`
+	// Org is the org for the repo of the build
+	Org string `json:"org"`
+	// Repo is the name of the repo for the build
+	Repo string `json:"repo"`
+	// Number is the number of the build for the repo
+	Number int64 `json:"number"`
+	// PipelineID is the identifier for the runtime resources of the pipeline
+	PipelineID string `json:"pipeline_id"`
+	// Runtime is the driver for the runtime executing the build
+	Runtime string `json:"runtime"`
+	// Containers are the identifiers for the runtime containers of the pipeline
+	Containers []string `json:"containers,omitempty"`
+	// Started is the unix timestamp the build started on the worker
+	Started int64 `json:"started"`
+}
+
+// Journal represents the on-disk record of the builds in flight on the worker.
+type Journal struct {
+	dir string
+}
+
+// New returns a Journal that records the builds in the provided directory.
+//
+// If no directory is provided, a nil Journal is returned which
+// silently skips recording builds.
+func New(dir string) (*Journal, error) {
+	if len(dir) == 0 {
+		return nil, nil
+	}
+
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create journal directory %s: %w", dir, err)
+	}
+
+	// the entries describe the builds running on the worker, so
+	// restrict access to a directory that already existed to the worker
+	err = os.Chmod(dir, 0o700)
+	if err != nil {
+		return nil, fmt.Errorf("unable to restrict journal directory %s: %w", dir, err)
+	}
+
+	return &Journal{dir: dir}, nil
+}
+
+// Record writes the entry for the build to the journal,
+// replacing any existing entry for the build.
+func (j *Journal) Record(e *Entry) error {
+	if j == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("unable to encode journal entry for build %d: %w", e.BuildID, err)
+	}
+
+	// write to a temporary file and rename it so a crash
+	// while recording never leaves a partial entry behind
+	tmp, err := os.CreateTemp(j.dir, ".entry-*")
+	if err != nil {
+		return fmt.Errorf("unable to create journal entry for build %d: %w", e.BuildID, err)
+	}
+
+	// only allow the worker to read the entry
+	// regardless of the default mode
+	err = tmp.Chmod(0o600)
+	if err == nil {
+		_, err = tmp.Write(data)
+	}
+
+	if err == nil {
+		err = tmp.Sync()
+	}
+
+	err = errors.Join(err, tmp.Close())
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return fmt.Errorf("unable to write journal entry for build %d: %w", e.BuildID, err)
+	}
+
+	err = os.Rename(tmp.Name(), j.path(e.BuildID))
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return fmt.Errorf("unable to write journal entry for build %d: %w", e.BuildID, err)
+	}
+
+	return nil
+}
+
+// Remove deletes the entry for the build from the journal.
+func (j *Journal) Remove(buildID int64) error {
+	if j == nil {
+		return nil
+	}
+
+	err := os.Remove(j.path(buildID))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("unable to remove journal entry for build %d: %w", buildID, err)
+	}
+
+	return nil
+}
+
+// List returns the entries for all builds recorded in the journal
+// ordered by the time the build started. Entries that are unable
+// to be read are skipped and reported in the returned error.
+func (j *Journal) List() ([]*Entry, error) {
+	if j == nil {
+		return nil, nil
+	}
+
+	files, err := os.ReadDir(j.dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read journal directory %s: %w", j.dir, err)
+	}
+
+	var (
+		entries []*Entry
+		errs    []error
+	)
+
+	for _, file := range files {
+		// skip directories and temporary files
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ext) {
+			continue
+		}
+
+		path := filepath.Join(j.dir, file.Name())
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to read journal entry %s: %w", path, err))
+
+			continue
+		}
+
+		e := new(Entry)
+
+		err = json.Unmarshal(data, e)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to parse journal entry %s: %w", path, err))
+
+			continue
+		}
+
+		entries = append(entries, e)
+	}
+
+	sort.SliceStable(entries, func(i, k int) bool {
+		return entries[i].Started < entries[k].Started
+	})
+
+	return entries, errors.Join(errs...)
+}
+
+// path returns the location of the entry for the build.
+func (j *Journal) path(buildID int64) string {
+	return filepath.Join(j.dir, fmt.Sprintf("%d%s", buildID, ext))
+}
@@ -0,0 +1,248 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestJournal_New(t *testing.T) {
+	// setup types
+	dir := filepath.Join(t.TempDir(), "journal")
+
+	// setup tests
+	tests := []struct {
+		name string
+		dir  string
+		want *Journal
+	}{
+		{
+			name: "directory",
+			dir:  dir,
+			want: &Journal{dir: dir},
+		},
+		{
+			name: "empty directory",
+			dir:  "",
+			want: nil,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := New(test.dir)
+			if err != nil {
+				t.Errorf("New returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("New is %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	_, err := os.Stat(dir)
+	if err != nil {
+		t.Errorf("New did not create directory %s: %v", dir, err)
+	}
+}
+
+func TestJournal_Record(t *testing.T) {
+	// setup types
+	j, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New returned err: %v", err)
+	}
+
+	first := &Entry{
+		BuildID:    1,
+		Org:        "github",
+		Repo:       "octocat",
+		Number:     1,
+		PipelineID: "github-octocat-1",
+		Runtime:    "docker",
+		Containers: []string{"step_github_octocat_1_init", "step_github_octocat_1_clone"},
+		Started:    2,
+	}
+
+	second := &Entry{
+		BuildID:    2,
+		Org:        "github",
+		Repo:       "octocat",
+		Number:     2,
+		PipelineID: "github-octocat-2",
+		Runtime:    "docker",
+		Started:    1,
+	}
+
+	for _, e := range []*Entry{first, second} {
+		err = j.Record(e)
+		if err != nil {
+			t.Errorf("Record returned err: %v", err)
+		}
+	}
+
+	// record the first entry again to ensure it is replaced
+	first.Containers = append(first.Containers, "step_github_octocat_1_echo")
+
+	err = j.Record(first)
+	if err != nil {
+		t.Errorf("Record returned err: %v", err)
+	}
+
+	got, err := j.List()
+	if err != nil {
+		t.Errorf("List returned err: %v", err)
+	}
+
+	want := []*Entry{second, first}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("List is %v, want %v", got, want)
+	}
+}
+
+func TestJournal_Permissions(t *testing.T) {
+	// setup types
+	dir := filepath.Join(t.TempDir(), "journal")
+
+	// create the directory with broader permissions than the journal allows
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		t.Fatalf("MkdirAll returned err: %v", err)
+	}
+
+	j, err := New(dir)
+	if err != nil {
+		t.Fatalf("New returned err: %v", err)
+	}
+
+	err = j.Record(&Entry{BuildID: 1})
+	if err != nil {
+		t.Errorf("Record returned err: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		name string
+		path string
+		want os.FileMode
+	}{
+		{
+			name: "directory",
+			path: dir,
+			want: 0o700,
+		},
+		{
+			name: "entry",
+			path: filepath.Join(dir, "1.json"),
+			want: 0o600,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			info, err := os.Stat(test.path)
+			if err != nil {
+				t.Errorf("Stat returned err: %v", err)
+
+				return // continue to next test
+			}
+
+			if got := info.Mode().Perm(); got != test.want {
+				t.Errorf("%s mode is %v, want %v", test.path, got, test.want)
+			}
+		})
+	}
+}
+
+func TestJournal_Remove(t *testing.T) {
+	// setup types
+	j, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New returned err: %v", err)
+	}
+
+	err = j.Record(&Entry{BuildID: 1})
+	if err != nil {
+		t.Errorf("Record returned err: %v", err)
+	}
+
+	err = j.Remove(1)
+	if err != nil {
+		t.Errorf("Remove returned err: %v", err)
+	}
+
+	// ensure removing a missing entry is ignored
+	err = j.Remove(1)
+	if err != nil {
+		t.Errorf("Remove returned err: %v", err)
+	}
+
+	got, err := j.List()
+	if err != nil {
+		t.Errorf("List returned err: %v", err)
+	}
+
+	if len(got) > 0 {
+		t.Errorf("List is %v, want empty", got)
+	}
+}
+
+func TestJournal_List_Invalid(t *testing.T) {
+	// setup types
+	dir := t.TempDir()
+
+	j, err := New(dir)
+	if err != nil {
+		t.Fatalf("New returned err: %v", err)
+	}
+
+	err = j.Record(&Entry{BuildID: 1})
+	if err != nil {
+		t.Errorf("Record returned err: %v", err)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, "2.json"), []byte("!@#$%^&*()"), 0o600)
+	if err != nil {
+		t.Errorf("WriteFile returned err: %v", err)
+	}
+
+	got, err := j.List()
+	if err == nil {
+		t.Errorf("List should have returned err")
+	}
+
+	if len(got) != 1 || got[0].BuildID != 1 {
+		t.Errorf("List is %v, want build 1", got)
+	}
+}
+
+func TestJournal_Nil(t *testing.T) {
+	// setup types
+	var j *Journal
+
+	err := j.Record(&Entry{BuildID: 1})
+	if err != nil {
+		t.Errorf("Record returned err: %v", err)
+	}
+
+	err = j.Remove(1)
+	if err != nil {
+		t.Errorf("Remove returned err: %v", err)
+	}
+
+	got, err := j.List()
+	if err != nil {
+		t.Errorf("List returned err: %v", err)
+	}
+
+	if got != nil {
+		t.Errorf("List is %v, want nil", got)
+	}
+}
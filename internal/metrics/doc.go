@@ -0,0 +1,12 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package metrics provides the ability for Vela to publish
+// Prometheus metrics for the builds executed by the worker.
+//
+// The metrics are registered with the default Prometheus
+// registry and served by the worker from the /metrics endpoint.
+//
+// Usage:
+//
+//	import "github.com/go-vela/worker/internal/metrics"
+package metrics
@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// namespace represents the prefix for every metric published by the worker.
+const namespace = "vela_worker"
+
+// labels represents the labels applied to every build metric.
+var labels = []string{"repo", "runtime", "executor"}
+
+// Labels represents the values for the labels applied to every build metric.
+type Labels struct {
+	// Repo is the full name of the repo for the build (i.e. octocat/hello-world)
+	Repo string
+	// Runtime is the driver for the runtime executing the build (i.e. docker)
+	Runtime string
+	// Executor is the driver for the executor executing the build (i.e. linux)
+	Executor string
+}
+
+// values returns the label values in the order of the label names.
+func (l Labels) values(extra ...string) []string {
+	return append([]string{l.Repo, l.Runtime, l.Executor}, extra...)
+}
+
+var (
+	// https://pkg.go.dev/github.com/prometheus/client_golang/prometheus/promauto#NewCounterVec
+	buildsStarted = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "builds_started_total",
+		Help:      "Total number of builds started by the worker.",
+	}, labels)
+
+	buildsFinished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "builds_finished_total",
+		Help:      "Total number of builds finished by the worker by status.",
+	}, append(labels, "status"))
+
+	// https://pkg.go.dev/github.com/prometheus/client_golang/prometheus/promauto#NewHistogramVec
+	stepDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "step_duration_seconds",
+		Help:      "Duration of steps executed by the worker by status.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 14),
+	}, append(labels, "status"))
+
+	stepExitReasons = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "step_exit_reasons_total",
+		Help:      "Total number of steps terminated by the infrastructure by reason.",
+	}, append(labels, "reason"))
+
+	imagePullDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "image_pull_duration_seconds",
+		Help:      "Duration of pulling images for containers executed by the worker.",
+		Buckets:   prometheus.ExponentialBuckets(0.25, 2, 12),
+	}, labels)
+
+	queuePopDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "queue_pop_duration_seconds",
+		Help:      "Duration of waiting on the queue for a build to execute.",
+		Buckets:   prometheus.ExponentialBuckets(0.05, 2, 12),
+	}, labels)
+
+	logBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "log_uploaded_bytes_total",
+		Help:      "Total number of log bytes uploaded to the server.",
+	}, labels)
+
+	artifactBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "artifact_uploaded_bytes_total",
+		Help:      "Total number of artifact bytes uploaded to storage.",
+	}, labels)
+
+	secretPluginDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "secret_plugin_duration_seconds",
+		Help:      "Duration of secret plugins executed by the worker.",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+	}, labels)
+
+	janitorRemoved = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "janitor_removed_total",
+		Help:      "Total number of orphaned runtime resources removed by the janitor by resource.",
+	}, []string{"runtime", "resource"})
+
+	janitorErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "janitor_errors_total",
+		Help:      "Total number of orphaned runtime resources the janitor was unable to remove by resource.",
+	}, []string{"runtime", "resource"})
+
+	tokenCache = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "token_cache_requests_total",
+		Help:      "Total number of tokens validated for the worker API by cache result.",
+	}, []string{"result"})
+)
+
+// BuildStarted records a build started by the worker.
+func BuildStarted(l Labels) {
+	buildsStarted.WithLabelValues(l.values()...).Inc()
+}
+
+// BuildFinished records a build finished by the worker with the provided status.
+func BuildFinished(l Labels, status string) {
+	buildsFinished.WithLabelValues(l.values(status)...).Inc()
+}
+
+// ObserveStep records the duration of a step finished with the provided status.
+func ObserveStep(l Labels, status string, d time.Duration) {
+	stepDuration.WithLabelValues(l.values(status)...).Observe(d.Seconds())
+}
+
+// StepExitReason records a step terminated by the infrastructure
+// with the provided reason (i.e. OOMKilled).
+func StepExitReason(l Labels, reason string) {
+	stepExitReasons.WithLabelValues(l.values(reason)...).Inc()
+}
+
+// ObserveImagePull records the duration of pulling an image for a container.
+func ObserveImagePull(l Labels, d time.Duration) {
+	imagePullDuration.WithLabelValues(l.values()...).Observe(d.Seconds())
+}
+
+// ObserveQueuePop records the duration of waiting on the queue for a build.
+func ObserveQueuePop(l Labels, d time.Duration) {
+	queuePopDuration.WithLabelValues(l.values()...).Observe(d.Seconds())
+}
+
+// AddLogBytes records the number of log bytes uploaded to the server.
+func AddLogBytes(l Labels, n int) {
+	if n <= 0 {
+		return
+	}
+
+	logBytes.WithLabelValues(l.values()...).Add(float64(n))
+}
+
+// AddArtifactBytes records the number of artifact bytes uploaded to storage.
+func AddArtifactBytes(l Labels, n int64) {
+	if n <= 0 {
+		return
+	}
+
+	artifactBytes.WithLabelValues(l.values()...).Add(float64(n))
+}
+
+// ObserveSecretPlugin records the duration of executing a secret plugin.
+func ObserveSecretPlugin(l Labels, d time.Duration) {
+	secretPluginDuration.WithLabelValues(l.values()...).Observe(d.Seconds())
+}
+
+// JanitorRemoved records an orphaned runtime resource removed by the janitor.
+func JanitorRemoved(runtime, resource string) {
+	janitorRemoved.WithLabelValues(runtime, resource).Inc()
+}
+
+// JanitorError records an orphaned runtime resource the janitor was unable to remove.
+func JanitorError(runtime, resource string) {
+	janitorErrors.WithLabelValues(runtime, resource).Inc()
+}
+
+// TokenCacheHit records a token for the worker API found in the validation cache.
+func TokenCacheHit() {
+	tokenCache.WithLabelValues("hit").Inc()
+}
+
+// TokenCacheMiss records a token for the worker API missing from the validation cache.
+func TokenCacheMiss() {
+	tokenCache.WithLabelValues("miss").Inc()
+}
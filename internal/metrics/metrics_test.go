@@ -0,0 +1,208 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// setup global variables used for testing.
+var _labels = Labels{
+	Repo:     "github/octocat",
+	Runtime:  "docker",
+	Executor: "linux",
+}
+
+func TestMetrics_BuildStarted(t *testing.T) {
+	// setup types
+	want := counterValue(t, buildsStarted.WithLabelValues(_labels.values()...)) + 2
+
+	BuildStarted(_labels)
+	BuildStarted(_labels)
+
+	got := counterValue(t, buildsStarted.WithLabelValues(_labels.values()...))
+	if got != want {
+		t.Errorf("BuildStarted is %v, want %v", got, want)
+	}
+}
+
+func TestMetrics_BuildFinished(t *testing.T) {
+	// setup types
+	success := counterValue(t, buildsFinished.WithLabelValues(_labels.values("success")...)) + 1
+	failure := counterValue(t, buildsFinished.WithLabelValues(_labels.values("failure")...)) + 2
+
+	BuildFinished(_labels, "success")
+	BuildFinished(_labels, "failure")
+	BuildFinished(_labels, "failure")
+
+	got := counterValue(t, buildsFinished.WithLabelValues(_labels.values("success")...))
+	if got != success {
+		t.Errorf("BuildFinished success is %v, want %v", got, success)
+	}
+
+	got = counterValue(t, buildsFinished.WithLabelValues(_labels.values("failure")...))
+	if got != failure {
+		t.Errorf("BuildFinished failure is %v, want %v", got, failure)
+	}
+}
+
+func TestMetrics_StepExitReason(t *testing.T) {
+	// setup types
+	want := counterValue(t, stepExitReasons.WithLabelValues(_labels.values("OOMKilled")...)) + 1
+
+	StepExitReason(_labels, "OOMKilled")
+
+	got := counterValue(t, stepExitReasons.WithLabelValues(_labels.values("OOMKilled")...))
+	if got != want {
+		t.Errorf("StepExitReason is %v, want %v", got, want)
+	}
+}
+
+func TestMetrics_Histograms(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name      string
+		observe   func()
+		histogram prometheus.Observer
+		want      float64
+	}{
+		{
+			name:      "step duration",
+			observe:   func() { ObserveStep(_labels, "success", 3*time.Second) },
+			histogram: stepDuration.WithLabelValues(_labels.values("success")...),
+			want:      3,
+		},
+		{
+			name:      "image pull duration",
+			observe:   func() { ObserveImagePull(_labels, 2*time.Second) },
+			histogram: imagePullDuration.WithLabelValues(_labels.values()...),
+			want:      2,
+		},
+		{
+			name:      "queue pop duration",
+			observe:   func() { ObserveQueuePop(_labels, 100*time.Millisecond) },
+			histogram: queuePopDuration.WithLabelValues(_labels.values()...),
+			want:      0.1,
+		},
+		{
+			name:      "secret plugin duration",
+			observe:   func() { ObserveSecretPlugin(_labels, 500*time.Millisecond) },
+			histogram: secretPluginDuration.WithLabelValues(_labels.values()...),
+			want:      0.5,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			count, sum := histogramValue(t, test.histogram)
+
+			test.observe()
+
+			gotCount, gotSum := histogramValue(t, test.histogram)
+
+			if gotCount != count+1 {
+				t.Errorf("%s count is %v, want %v", test.name, gotCount, count+1)
+			}
+
+			if gotSum-sum != test.want {
+				t.Errorf("%s sum is %v, want %v", test.name, gotSum-sum, test.want)
+			}
+		})
+	}
+}
+
+func TestMetrics_Bytes(t *testing.T) {
+	// setup types
+	logs := counterValue(t, logBytes.WithLabelValues(_labels.values()...)) + 1024
+	artifacts := counterValue(t, artifactBytes.WithLabelValues(_labels.values()...)) + 2048
+
+	AddLogBytes(_labels, 1024)
+	AddLogBytes(_labels, 0)
+	AddArtifactBytes(_labels, 2048)
+	AddArtifactBytes(_labels, -1)
+
+	got := counterValue(t, logBytes.WithLabelValues(_labels.values()...))
+	if got != logs {
+		t.Errorf("AddLogBytes is %v, want %v", got, logs)
+	}
+
+	got = counterValue(t, artifactBytes.WithLabelValues(_labels.values()...))
+	if got != artifacts {
+		t.Errorf("AddArtifactBytes is %v, want %v", got, artifacts)
+	}
+}
+
+func TestMetrics_Janitor(t *testing.T) {
+	// setup types
+	removed := counterValue(t, janitorRemoved.WithLabelValues("docker", "container")) + 2
+	errored := counterValue(t, janitorErrors.WithLabelValues("docker", "network")) + 1
+
+	JanitorRemoved("docker", "container")
+	JanitorRemoved("docker", "container")
+	JanitorError("docker", "network")
+
+	got := counterValue(t, janitorRemoved.WithLabelValues("docker", "container"))
+	if got != removed {
+		t.Errorf("JanitorRemoved is %v, want %v", got, removed)
+	}
+
+	got = counterValue(t, janitorErrors.WithLabelValues("docker", "network"))
+	if got != errored {
+		t.Errorf("JanitorError is %v, want %v", got, errored)
+	}
+}
+
+func TestMetrics_TokenCache(t *testing.T) {
+	// setup types
+	hits := counterValue(t, tokenCache.WithLabelValues("hit")) + 2
+	misses := counterValue(t, tokenCache.WithLabelValues("miss")) + 1
+
+	TokenCacheHit()
+	TokenCacheHit()
+	TokenCacheMiss()
+
+	got := counterValue(t, tokenCache.WithLabelValues("hit"))
+	if got != hits {
+		t.Errorf("TokenCacheHit is %v, want %v", got, hits)
+	}
+
+	got = counterValue(t, tokenCache.WithLabelValues("miss"))
+	if got != misses {
+		t.Errorf("TokenCacheMiss is %v, want %v", got, misses)
+	}
+}
+
+// counterValue is a helper function to capture
+// the current value of a counter for tests.
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	t.Helper()
+
+	m := new(dto.Metric)
+
+	err := c.Write(m)
+	if err != nil {
+		t.Fatalf("Write returned err: %v", err)
+	}
+
+	return m.GetCounter().GetValue()
+}
+
+// histogramValue is a helper function to capture the
+// current sample count and sum of a histogram for tests.
+func histogramValue(t *testing.T, o prometheus.Observer) (uint64, float64) {
+	t.Helper()
+
+	m := new(dto.Metric)
+
+	err := o.(prometheus.Metric).Write(m)
+	if err != nil {
+		t.Fatalf("Write returned err: %v", err)
+	}
+
+	return m.GetHistogram().GetSampleCount(), m.GetHistogram().GetSampleSum()
+}
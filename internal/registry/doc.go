@@ -0,0 +1,10 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package registry provides the ability for Vela to resolve
+// credentials used to authenticate with container image
+// registries when pulling images for a container.
+//
+// Usage:
+//
+//	import "github.com/go-vela/worker/internal/registry"
+package registry
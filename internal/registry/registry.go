@@ -0,0 +1,373 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/distribution/reference"
+	mobyRegistry "github.com/moby/moby/api/types/registry"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// DockerHub represents the normalized domain for Docker Hub.
+	DockerHub = "docker.io"
+
+	// dockerHubServer represents the legacy server address Docker
+	// uses for storing and looking up Docker Hub credentials.
+	dockerHubServer = "https://index.docker.io/v1/"
+
+	// helperPrefix represents the prefix for the binary
+	// name of every Docker credential helper.
+	helperPrefix = "docker-credential-"
+
+	// tokenUsername represents the username returned from
+	// a credential helper when the secret is an identity token.
+	tokenUsername = "<token>"
+)
+
+// dockerConfig represents the subset of fields
+// used from a Docker client config.json file.
+//
+// https://docs.docker.com/reference/cli/docker/#docker-cli-configuration-file-configjson-properties
+type dockerConfig struct {
+	Auths       map[string]mobyRegistry.AuthConfig `json:"auths"`
+	CredsStore  string                             `json:"credsStore"`
+	CredHelpers map[string]string                  `json:"credHelpers"`
+}
+
+// helperCredentials represents the output from
+// invoking the get command for a credential helper.
+//
+// https://github.com/docker/docker-credential-helpers
+type helperCredentials struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+// execHelper invokes the get command for the provided credential
+// helper with the server address provided on standard input.
+//
+// This is a variable to enable overriding it for tests.
+var execHelper = func(ctx context.Context, helper, server string) ([]byte, error) {
+	// create the command to invoke the credential helper
+	//
+	// https://pkg.go.dev/os/exec#CommandContext
+	cmd := exec.CommandContext(ctx, helperPrefix+helper, "get")
+
+	cmd.Stdin = strings.NewReader(server)
+
+	return cmd.Output()
+}
+
+// Credentials represents the credentials available for
+// authenticating with container image registries.
+type Credentials struct {
+	// credentials from the per-registry secrets file
+	secrets map[string]mobyRegistry.AuthConfig
+	// credentials from the auths section of the Docker config
+	auths map[string]mobyRegistry.AuthConfig
+	// credential helpers from the credHelpers section of the Docker config
+	helpers map[string]string
+	// default credential helper from the credsStore section of the Docker config
+	store string
+}
+
+// Load reads the registry credentials from the provided Docker
+// config.json file and per-registry secrets file.
+//
+// The files are read when Load is called, so the credentials should
+// be loaded once and loaded again to pick up changes to the files.
+//
+// If neither file is provided, the default Docker config file
+// is used when it exists ($DOCKER_CONFIG/config.json or
+// $HOME/.docker/config.json).
+func Load(configFile, secretsFile string) (*Credentials, error) {
+	c := &Credentials{
+		secrets: make(map[string]mobyRegistry.AuthConfig),
+		auths:   make(map[string]mobyRegistry.AuthConfig),
+		helpers: make(map[string]string),
+	}
+
+	// check if a Docker config file was provided
+	if len(configFile) > 0 {
+		err := c.loadDockerConfig(configFile)
+		if err != nil {
+			return nil, err
+		}
+	} else if path := defaultConfigFile(); len(path) > 0 && len(secretsFile) == 0 {
+		// only load the default Docker config file when it exists
+		if _, err := os.Stat(path); err == nil {
+			err = c.loadDockerConfig(path)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// check if a per-registry secrets file was provided
+	if len(secretsFile) > 0 {
+		err := c.loadSecrets(secretsFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// Lookup returns the credentials for the registry the provided
+// image is pulled from. If no credentials are configured for the
+// registry, nil is returned.
+//
+// Credentials are resolved in the following order:
+//
+//   - per-registry secrets file
+//   - credential helper for the registry (credHelpers)
+//   - static credentials for the registry (auths)
+//   - default credential helper (credsStore)
+func (c *Credentials) Lookup(ctx context.Context, image string) (*mobyRegistry.AuthConfig, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	// capture the registry domain from the image
+	domain, err := Domain(image)
+	if err != nil {
+		return nil, err
+	}
+
+	// check if credentials exist in the secrets file for the registry
+	if auth, ok := c.secrets[domain]; ok {
+		return &auth, nil
+	}
+
+	// check if a credential helper exists for the registry
+	if helper, ok := c.helpers[domain]; ok {
+		return c.fromHelper(ctx, helper, domain)
+	}
+
+	// check if static credentials exist for the registry
+	if auth, ok := c.auths[domain]; ok {
+		return &auth, nil
+	}
+
+	// check if a default credential helper exists
+	if len(c.store) > 0 {
+		return c.fromHelper(ctx, c.store, domain)
+	}
+
+	return nil, nil
+}
+
+// Encode returns the provided credentials in the base64url
+// encoded JSON form expected by the Docker daemon.
+func Encode(auth *mobyRegistry.AuthConfig) (string, error) {
+	if auth == nil {
+		return "", nil
+	}
+
+	data, err := json.Marshal(auth)
+	if err != nil {
+		return "", fmt.Errorf("unable to encode registry credentials: %w", err)
+	}
+
+	return base64.URLEncoding.EncodeToString(data), nil
+}
+
+// Domain returns the normalized registry domain
+// for the provided container image.
+func Domain(image string) (string, error) {
+	// parse the image provided into a named, normalized reference
+	//
+	// https://pkg.go.dev/github.com/distribution/reference#ParseNormalizedNamed
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse image %s: %w", image, err)
+	}
+
+	// https://pkg.go.dev/github.com/distribution/reference#Domain
+	return normalize(reference.Domain(named)), nil
+}
+
+// fromHelper invokes the provided credential helper to
+// capture the credentials for the registry domain.
+func (c *Credentials) fromHelper(ctx context.Context, helper, domain string) (*mobyRegistry.AuthConfig, error) {
+	server := domain
+
+	// Docker Hub credentials are stored under the legacy server address
+	if domain == DockerHub {
+		server = dockerHubServer
+	}
+
+	out, err := execHelper(ctx, helper, server)
+	if err != nil {
+		// credential helpers report missing credentials on standard output
+		if bytes.Contains(out, []byte("credentials not found")) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("unable to get credentials for %s from helper %s: %w", domain, helper, err)
+	}
+
+	creds := new(helperCredentials)
+
+	err = json.Unmarshal(out, creds)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse credentials for %s from helper %s: %w", domain, helper, err)
+	}
+
+	auth := &mobyRegistry.AuthConfig{
+		ServerAddress: server,
+	}
+
+	// check if the helper returned an identity token
+	if creds.Username == tokenUsername {
+		auth.IdentityToken = creds.Secret
+	} else {
+		auth.Username = creds.Username
+		auth.Password = creds.Secret
+	}
+
+	return auth, nil
+}
+
+// loadDockerConfig reads the credentials from the provided Docker config.json file.
+func (c *Credentials) loadDockerConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read docker config %s: %w", path, err)
+	}
+
+	cfg := new(dockerConfig)
+
+	err = json.Unmarshal(data, cfg)
+	if err != nil {
+		return fmt.Errorf("unable to parse docker config %s: %w", path, err)
+	}
+
+	for server, auth := range cfg.Auths {
+		// decode the username and password from the auth field
+		if len(auth.Auth) > 0 {
+			auth.Username, auth.Password, err = decodeAuth(auth.Auth)
+			if err != nil {
+				return fmt.Errorf("unable to decode credentials for %s in docker config %s: %w", server, path, err)
+			}
+
+			auth.Auth = ""
+		}
+
+		auth.ServerAddress = server
+
+		c.auths[normalize(server)] = auth
+	}
+
+	for server, helper := range cfg.CredHelpers {
+		c.helpers[normalize(server)] = helper
+	}
+
+	c.store = cfg.CredsStore
+
+	return nil
+}
+
+// loadSecrets reads the credentials from the provided per-registry
+// secrets file. The file may be YAML or JSON and maps each registry
+// to the credentials used for it:
+//
+//	registry.example.com:
+//	  username: octocat
+//	  password: superSecretPassword
+func (c *Credentials) loadSecrets(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read registry credentials file %s: %w", path, err)
+	}
+
+	secrets := make(map[string]mobyRegistry.AuthConfig)
+
+	// https://pkg.go.dev/sigs.k8s.io/yaml#Unmarshal
+	err = yaml.Unmarshal(data, &secrets)
+	if err != nil {
+		return fmt.Errorf("unable to parse registry credentials file %s: %w", path, err)
+	}
+
+	for server, auth := range secrets {
+		// decode the username and password from the auth field
+		if len(auth.Auth) > 0 && len(auth.Username) == 0 {
+			auth.Username, auth.Password, err = decodeAuth(auth.Auth)
+			if err != nil {
+				return fmt.Errorf("unable to decode credentials for %s in registry credentials file %s: %w", server, path, err)
+			}
+
+			auth.Auth = ""
+		}
+
+		if len(auth.ServerAddress) == 0 {
+			auth.ServerAddress = server
+		}
+
+		c.secrets[normalize(server)] = auth
+	}
+
+	return nil
+}
+
+// decodeAuth decodes the base64 encoded "username:password"
+// form of credentials stored in a Docker config.
+func decodeAuth(auth string) (string, string, error) {
+	data, err := base64.StdEncoding.DecodeString(auth)
+	if err != nil {
+		return "", "", err
+	}
+
+	username, password, ok := strings.Cut(string(data), ":")
+	if !ok {
+		return "", "", errors.New("invalid auth format")
+	}
+
+	return username, strings.Trim(password, "\x00"), nil
+}
+
+// defaultConfigFile returns the default
+// location of the Docker config.json file.
+func defaultConfigFile() string {
+	// check if the Docker config directory is overridden
+	if dir := os.Getenv("DOCKER_CONFIG"); len(dir) > 0 {
+		return filepath.Join(dir, "config.json")
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(home, ".docker", "config.json")
+}
+
+// normalize strips the scheme and path from the provided
+// server address and collapses the Docker Hub aliases.
+func normalize(server string) string {
+	server = strings.TrimPrefix(server, "https://")
+	server = strings.TrimPrefix(server, "http://")
+
+	server, _, _ = strings.Cut(server, "/")
+
+	switch server {
+	case "index.docker.io", "registry-1.docker.io":
+		return DockerHub
+	}
+
+	return server
+}
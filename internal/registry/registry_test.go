@@ -0,0 +1,318 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	mobyRegistry "github.com/moby/moby/api/types/registry"
+)
+
+func TestRegistry_Load(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		failure bool
+		config  string
+		secrets string
+		want    *Credentials
+	}{
+		{
+			name:    "docker config and secrets",
+			failure: false,
+			config:  "testdata/config.json",
+			secrets: "testdata/secrets.yml",
+			want: &Credentials{
+				secrets: map[string]mobyRegistry.AuthConfig{
+					"registry.example.com": {Username: "vela", Password: "secret", ServerAddress: "registry.example.com"},
+					"ghcr.io":              {Username: "vela", Password: "ghcr", ServerAddress: "https://ghcr.io"},
+				},
+				auths: map[string]mobyRegistry.AuthConfig{
+					DockerHub:              {Username: "octocat", Password: "hub", ServerAddress: "https://index.docker.io/v1/"},
+					"registry.example.com": {Username: "octocat", Password: "example", ServerAddress: "registry.example.com"},
+				},
+				helpers: map[string]string{"gcr.io": "gcloud"},
+				store:   "desktop",
+			},
+		},
+		{
+			name:    "missing docker config",
+			failure: true,
+			config:  "testdata/missing.json",
+		},
+		{
+			name:    "missing secrets file",
+			failure: true,
+			config:  "testdata/config.json",
+			secrets: "testdata/missing.yml",
+		},
+		{
+			name:    "invalid docker config auth",
+			failure: true,
+			config:  "testdata/invalid.json",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Load(test.config, test.secrets)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("Load should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("Load returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Load is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRegistry_Load_Default(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name      string
+		dockerDir string
+		secrets   string
+		want      string
+	}{
+		{
+			name:      "default docker config",
+			dockerDir: "testdata/default",
+			want:      "desktop",
+		},
+		{
+			name:      "secrets file without docker config",
+			dockerDir: "testdata/default",
+			secrets:   "testdata/secrets.yml",
+			want:      "",
+		},
+		{
+			name:      "missing default docker config",
+			dockerDir: "testdata/missing",
+			want:      "",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("DOCKER_CONFIG", test.dockerDir)
+
+			got, err := Load("", test.secrets)
+			if err != nil {
+				t.Errorf("Load returned err: %v", err)
+			}
+
+			if got.store != test.want {
+				t.Errorf("Load store is %s, want %s", got.store, test.want)
+			}
+		})
+	}
+}
+
+func TestRegistry_Credentials_Lookup(t *testing.T) {
+	// setup types
+	c := &Credentials{
+		secrets: map[string]mobyRegistry.AuthConfig{
+			"registry.example.com": {Username: "vela", Password: "secret"},
+		},
+		auths: map[string]mobyRegistry.AuthConfig{
+			DockerHub:              {Username: "octocat", Password: "hub"},
+			"registry.example.com": {Username: "octocat", Password: "example"},
+		},
+		helpers: map[string]string{
+			"gcr.io":     "gcloud",
+			"quay.io":    "missing",
+			"failure.io": "failure",
+		},
+	}
+
+	store := &Credentials{store: "desktop"}
+
+	// override the credential helper for the tests
+	execHelper = func(_ context.Context, helper, server string) ([]byte, error) {
+		switch helper {
+		case "gcloud":
+			return json.Marshal(helperCredentials{ServerURL: server, Username: "oauth2accesstoken", Secret: "token"})
+		case "desktop":
+			return json.Marshal(helperCredentials{ServerURL: server, Username: tokenUsername, Secret: "identity"})
+		case "missing":
+			return []byte("credentials not found in native keychain"), errors.New("exit status 1")
+		default:
+			return nil, errors.New("exit status 1")
+		}
+	}
+
+	// setup tests
+	tests := []struct {
+		name    string
+		failure bool
+		creds   *Credentials
+		image   string
+		want    *mobyRegistry.AuthConfig
+	}{
+		{
+			name:    "secrets file takes precedence",
+			failure: false,
+			creds:   c,
+			image:   "registry.example.com/octocat/hello-world:latest",
+			want:    &mobyRegistry.AuthConfig{Username: "vela", Password: "secret"},
+		},
+		{
+			name:    "docker hub auths",
+			failure: false,
+			creds:   c,
+			image:   "alpine:latest",
+			want:    &mobyRegistry.AuthConfig{Username: "octocat", Password: "hub"},
+		},
+		{
+			name:    "credential helper",
+			failure: false,
+			creds:   c,
+			image:   "gcr.io/octocat/hello-world",
+			want:    &mobyRegistry.AuthConfig{Username: "oauth2accesstoken", Password: "token", ServerAddress: "gcr.io"},
+		},
+		{
+			name:    "credential helper without credentials",
+			failure: false,
+			creds:   c,
+			image:   "quay.io/octocat/hello-world",
+			want:    nil,
+		},
+		{
+			name:    "credential helper failure",
+			failure: true,
+			creds:   c,
+			image:   "failure.io/octocat/hello-world",
+		},
+		{
+			name:    "credential store with identity token",
+			failure: false,
+			creds:   store,
+			image:   "alpine",
+			want:    &mobyRegistry.AuthConfig{IdentityToken: "identity", ServerAddress: dockerHubServer},
+		},
+		{
+			name:    "no credentials",
+			failure: false,
+			creds:   c,
+			image:   "ghcr.io/octocat/hello-world",
+			want:    nil,
+		},
+		{
+			name:    "nil credentials",
+			failure: false,
+			creds:   nil,
+			image:   "alpine",
+			want:    nil,
+		},
+		{
+			name:    "invalid image",
+			failure: true,
+			creds:   c,
+			image:   "!@#$%^&*()",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.creds.Lookup(context.Background(), test.image)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("Lookup should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("Lookup returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Lookup is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRegistry_Encode(t *testing.T) {
+	// setup types
+	auth := &mobyRegistry.AuthConfig{Username: "octocat", Password: "superSecretPassword"}
+
+	got, err := Encode(auth)
+	if err != nil {
+		t.Errorf("Encode returned err: %v", err)
+	}
+
+	data, err := base64.URLEncoding.DecodeString(got)
+	if err != nil {
+		t.Errorf("DecodeString returned err: %v", err)
+	}
+
+	want := new(mobyRegistry.AuthConfig)
+
+	err = json.Unmarshal(data, want)
+	if err != nil {
+		t.Errorf("Unmarshal returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(auth, want) {
+		t.Errorf("Encode is %v, want %v", want, auth)
+	}
+
+	// ensure nil credentials are encoded as empty
+	got, err = Encode(nil)
+	if err != nil {
+		t.Errorf("Encode returned err: %v", err)
+	}
+
+	if len(got) > 0 {
+		t.Errorf("Encode is %s, want empty", got)
+	}
+}
+
+func TestRegistry_Domain(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{image: "alpine", want: DockerHub},
+		{image: "target/vela-git:v0.4.0", want: DockerHub},
+		{image: "index.docker.io/library/alpine", want: DockerHub},
+		{image: "registry.example.com/octocat/hello-world:latest", want: "registry.example.com"},
+		{image: "localhost:5000/octocat/hello-world", want: "localhost:5000"},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.image, func(t *testing.T) {
+			got, err := Domain(test.image)
+			if err != nil {
+				t.Errorf("Domain returned err: %v", err)
+			}
+
+			if got != test.want {
+				t.Errorf("Domain is %s, want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package report provides the ability for Vela to capture
+// the results of builds run without a server.
+//
+// The reporter stands in for the server API called by the
+// executor so builds run through the normal lifecycle while
+// the results are written to local disk or discarded.
+//
+// Usage:
+//
+//	import "github.com/go-vela/worker/internal/report"
+package report
@@ -0,0 +1,246 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-vela/sdk-go/vela"
+	api "github.com/go-vela/server/api/types"
+)
+
+// address represents the placeholder address for the
+// server used by the clients created by the reporter.
+const address = "http://standalone.vela"
+
+// Reporter represents the stand in for the server API that
+// captures the results of builds run without a server.
+type Reporter struct {
+	dir     string
+	handler http.Handler
+}
+
+// New returns a Reporter that writes the results of builds
+// to the provided directory.
+//
+// If no directory is provided, the results are discarded.
+func New(dir string) (*Reporter, error) {
+	if len(dir) > 0 {
+		err := os.MkdirAll(dir, 0o700)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create report directory %s: %w", dir, err)
+		}
+	}
+
+	r := &Reporter{dir: dir}
+
+	// create an empty gin engine with no middleware
+	//
+	// https://pkg.go.dev/github.com/gin-gonic/gin#New
+	e := gin.New()
+
+	build := e.Group("/api/v1/repos/:org/:repo/builds/:build")
+	{
+		build.PUT("", r.updateBuild)
+		build.PUT("/steps/:number", update[api.Step](r, "steps"))
+		build.PUT("/services/:number", update[api.Service](r, "services"))
+		build.GET("/steps/:number/logs", r.getLog("steps"))
+		build.PUT("/steps/:number/logs", r.updateLog("steps"))
+		build.GET("/services/:number/logs", r.getLog("services"))
+		build.PUT("/services/:number/logs", r.updateLog("services"))
+	}
+
+	// reject the requests that need a server, such as
+	// requesting tokens, with an error for the client
+	e.NoRoute(func(c *gin.Context) {
+		msg := fmt.Sprintf("%s %s is not supported without a server", c.Request.Method, c.Request.URL.Path)
+
+		c.AbortWithStatusJSON(http.StatusNotImplemented, api.Error{Message: &msg})
+	})
+
+	r.handler = e
+
+	return r, nil
+}
+
+// Client returns a Vela client that sends
+// the requests for a build to the reporter.
+func (r *Reporter) Client() (*vela.Client, error) {
+	return vela.NewClient(address, "vela-worker", &http.Client{Transport: r})
+}
+
+// RoundTrip serves the request with the reporter
+// without sending it over the network.
+func (r *Reporter) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+
+	r.handler.ServeHTTP(rec, req)
+
+	resp := rec.Result()
+	resp.Request = req
+
+	return resp, nil
+}
+
+// updateBuild captures the build from the request.
+func (r *Reporter) updateBuild(c *gin.Context) {
+	b := new(api.Build)
+
+	err := c.ShouldBindJSON(b)
+	if err != nil {
+		respond(c, http.StatusBadRequest, fmt.Errorf("unable to decode build: %w", err))
+
+		return
+	}
+
+	err = r.write(c, "build.json", b)
+	if err != nil {
+		respond(c, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	c.JSON(http.StatusOK, b)
+}
+
+// update returns a handler that captures the step
+// or service from the request in the provided directory.
+func update[T any](r *Reporter, kind string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		obj := new(T)
+
+		err := c.ShouldBindJSON(obj)
+		if err != nil {
+			respond(c, http.StatusBadRequest, fmt.Errorf("unable to decode %s: %w", kind, err))
+
+			return
+		}
+
+		err = r.write(c, filepath.Join(kind, c.Param("number")+".json"), obj)
+		if err != nil {
+			respond(c, http.StatusInternalServerError, err)
+
+			return
+		}
+
+		c.JSON(http.StatusOK, obj)
+	}
+}
+
+// getLog returns a handler that responds with the
+// captured log for the step or service.
+func (r *Reporter) getLog(kind string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		l := new(api.Log)
+
+		if len(r.dir) > 0 {
+			path, err := r.path(c, filepath.Join(kind, c.Param("number")+".log"))
+			if err != nil {
+				respond(c, http.StatusBadRequest, err)
+
+				return
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				respond(c, http.StatusInternalServerError, err)
+
+				return
+			}
+
+			l.SetData(data)
+		}
+
+		c.JSON(http.StatusOK, l)
+	}
+}
+
+// updateLog returns a handler that captures
+// the log for the step or service.
+func (r *Reporter) updateLog(kind string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		l := new(api.Log)
+
+		err := c.ShouldBindJSON(l)
+		if err != nil {
+			respond(c, http.StatusBadRequest, fmt.Errorf("unable to decode log: %w", err))
+
+			return
+		}
+
+		if len(r.dir) > 0 {
+			path, err := r.path(c, filepath.Join(kind, c.Param("number")+".log"))
+			if err != nil {
+				respond(c, http.StatusBadRequest, err)
+
+				return
+			}
+
+			// the executor uploads the full contents of the log
+			err = os.WriteFile(path, l.GetData(), 0o600)
+			if err != nil {
+				respond(c, http.StatusInternalServerError, err)
+
+				return
+			}
+		}
+
+		c.Status(http.StatusOK)
+	}
+}
+
+// write is a helper function to encode the object
+// to the file for the build in the request.
+func (r *Reporter) write(c *gin.Context, name string, v any) error {
+	if len(r.dir) == 0 {
+		return nil
+	}
+
+	path, err := r.path(c, name)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+// path is a helper function to return the path to the file
+// for the build in the request, creating the directories.
+func (r *Reporter) path(c *gin.Context, name string) (string, error) {
+	rel := filepath.Join(c.Param("org"), c.Param("repo"), c.Param("build"), name)
+
+	// prevent writing outside of the report directory
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("invalid report path %s", rel)
+	}
+
+	path := filepath.Join(r.dir, rel)
+
+	err := os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return "", fmt.Errorf("unable to create report directory: %w", err)
+	}
+
+	return path, nil
+}
+
+// respond is a helper function to abort the
+// request with the error for the client.
+func respond(c *gin.Context, code int, err error) {
+	msg := err.Error()
+
+	c.AbortWithStatusJSON(code, api.Error{Message: &msg})
+}
@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	api "github.com/go-vela/server/api/types"
+)
+
+func TestReport_New(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		failure bool
+		dir     string
+	}{
+		{
+			name:    "directory",
+			failure: false,
+			dir:     filepath.Join(t.TempDir(), "report"),
+		},
+		{
+			name:    "no directory",
+			failure: false,
+			dir:     "",
+		},
+		{
+			name:    "invalid directory",
+			failure: true,
+			dir:     filepath.Join(writeFile(t), "report"),
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := New(test.dir)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("New should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("New returned err: %v", err)
+			}
+
+			if got == nil {
+				t.Errorf("New returned nil reporter")
+			}
+		})
+	}
+}
+
+func TestReport_Reporter_Client(t *testing.T) {
+	// setup types
+	dir := t.TempDir()
+
+	_build := testBuild()
+
+	_step := new(api.Step)
+	_step.SetNumber(1)
+	_step.SetName("clone")
+	_step.SetStatus("success")
+
+	_log := new(api.Log)
+	_log.SetData([]byte("hello from vela"))
+
+	// setup tests
+	tests := []struct {
+		name string
+		dir  string
+	}{
+		{
+			name: "directory",
+			dir:  dir,
+		},
+		{
+			name: "no directory",
+			dir:  "",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r, err := New(test.dir)
+			if err != nil {
+				t.Errorf("New returned err: %v", err)
+			}
+
+			client, err := r.Client()
+			if err != nil {
+				t.Errorf("Client returned err: %v", err)
+			}
+
+			ctx := context.Background()
+
+			gotBuild, _, err := client.Build.Update(ctx, _build)
+			if err != nil {
+				t.Errorf("Build.Update returned err: %v", err)
+			}
+
+			if gotBuild.GetStatus() != _build.GetStatus() {
+				t.Errorf("Build.Update returned status %s, want %s", gotBuild.GetStatus(), _build.GetStatus())
+			}
+
+			_, _, err = client.Step.Update(ctx, "github", "octocat", 1, _step)
+			if err != nil {
+				t.Errorf("Step.Update returned err: %v", err)
+			}
+
+			_, err = client.Log.UpdateStep(ctx, "github", "octocat", 1, 1, _log)
+			if err != nil {
+				t.Errorf("Log.UpdateStep returned err: %v", err)
+			}
+
+			gotLog, _, err := client.Log.GetStep(ctx, "github", "octocat", 1, 1)
+			if err != nil {
+				t.Errorf("Log.GetStep returned err: %v", err)
+			}
+
+			// logs are only kept when writing to a directory
+			if len(test.dir) > 0 && string(gotLog.GetData()) != string(_log.GetData()) {
+				t.Errorf("Log.GetStep returned %s, want %s", gotLog.GetData(), _log.GetData())
+			}
+
+			// requests that need a server are rejected
+			_, _, err = client.Build.PostInstallToken(ctx, "github", "octocat", 1, new(api.TokenRequest))
+			if err == nil {
+				t.Errorf("Build.PostInstallToken should have returned err")
+			}
+
+			if len(test.dir) == 0 {
+				return
+			}
+
+			for _, name := range []string{"build.json", filepath.Join("steps", "1.json"), filepath.Join("steps", "1.log")} {
+				_, err = os.Stat(filepath.Join(test.dir, "github", "octocat", "1", name))
+				if err != nil {
+					t.Errorf("unable to find %s in report: %v", name, err)
+				}
+			}
+		})
+	}
+}
+
+// testBuild is a test helper function to create a Build
+// type with all fields set to a fake value.
+func testBuild() *api.Build {
+	r := new(api.Repo)
+	r.SetOrg("github")
+	r.SetName("octocat")
+	r.SetFullName("github/octocat")
+
+	b := new(api.Build)
+	b.SetID(1)
+	b.SetRepo(r)
+	b.SetNumber(1)
+	b.SetStatus("running")
+
+	return b
+}
+
+// writeFile is a test helper function to create
+// a file that blocks creating a directory.
+func writeFile(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "file")
+
+	err := os.WriteFile(path, []byte("vela"), 0o600)
+	if err != nil {
+		t.Fatalf("unable to write file: %v", err)
+	}
+
+	return path
+}
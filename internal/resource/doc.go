@@ -0,0 +1,9 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package resource provides the ability for Vela to manage
+// the CPU and memory limits applied to a container.
+//
+// Usage:
+//
+//	import "github.com/go-vela/worker/internal/resource"
+package resource
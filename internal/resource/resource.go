@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"fmt"
+	"strings"
+
+	units "github.com/docker/go-units"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// EnvCPU represents the container environment key
+	// used to override the CPU limit for a container.
+	EnvCPU = "VELA_CPU_LIMIT"
+
+	// EnvMemory represents the container environment key
+	// used to override the memory limit for a container.
+	EnvMemory = "VELA_MEMORY_LIMIT"
+)
+
+// Limits represents the CPU and memory limits for a container.
+//
+// A zero value for either field means no limit is applied.
+type Limits struct {
+	// CPU is the limit in millicores (1000 = 1 CPU)
+	CPU int64 `json:"cpu,omitempty"`
+	// Memory is the limit in bytes
+	Memory int64 `json:"memory,omitempty"`
+}
+
+// IsZero returns true if no limits are set.
+func (l Limits) IsZero() bool {
+	return l.CPU == 0 && l.Memory == 0
+}
+
+// String implements the Stringer interface for the Limits type.
+func (l Limits) String() string {
+	cpu, memory := "unlimited", "unlimited"
+
+	if l.CPU > 0 {
+		cpu = resource.NewMilliQuantity(l.CPU, resource.DecimalSI).String()
+	}
+
+	if l.Memory > 0 {
+		memory = units.BytesSize(float64(l.Memory))
+	}
+
+	return fmt.Sprintf("cpu=%s memory=%s", cpu, memory)
+}
+
+// Parse digests the provided CPU and memory strings into limits.
+//
+// The CPU accepts a number of CPUs (i.e. "1.5") or millicores
+// (i.e. "1500m") and the memory accepts a size with an optional
+// unit (i.e. "512m", "2g" or "1Gi"). An empty string means no limit.
+func Parse(cpu, memory string) (Limits, error) {
+	l := Limits{}
+
+	// check if a CPU limit was provided
+	if len(strings.TrimSpace(cpu)) > 0 {
+		// https://pkg.go.dev/k8s.io/apimachinery/pkg/api/resource#ParseQuantity
+		q, err := resource.ParseQuantity(strings.TrimSpace(cpu))
+		if err != nil {
+			return l, fmt.Errorf("invalid cpu limit %s: %w", cpu, err)
+		}
+
+		if q.Sign() < 0 {
+			return l, fmt.Errorf("invalid cpu limit %s: must not be negative", cpu)
+		}
+
+		l.CPU = q.MilliValue()
+	}
+
+	// check if a memory limit was provided
+	if len(strings.TrimSpace(memory)) > 0 {
+		// parse the memory using the Docker format (i.e. "512m")
+		//
+		// https://pkg.go.dev/github.com/docker/go-units#RAMInBytes
+		m, err := units.RAMInBytes(strings.TrimSpace(memory))
+		if err != nil {
+			// fallback to the Kubernetes format (i.e. "512Mi")
+			//
+			// https://pkg.go.dev/k8s.io/apimachinery/pkg/api/resource#ParseQuantity
+			q, qErr := resource.ParseQuantity(strings.TrimSpace(memory))
+			if qErr != nil {
+				return l, fmt.Errorf("invalid memory limit %s: %w", memory, err)
+			}
+
+			m = q.Value()
+		}
+
+		if m < 0 {
+			return l, fmt.Errorf("invalid memory limit %s: must not be negative", memory)
+		}
+
+		l.Memory = m
+	}
+
+	return l, nil
+}
+
+// Resolve returns the limits to apply to a container.
+//
+// The limits start from the provided defaults and are overridden
+// by the container environment (EnvCPU and EnvMemory). The result
+// is capped at the provided maximum when set.
+func Resolve(env map[string]string, defaults, limits Limits) (Limits, error) {
+	l := defaults
+
+	// parse the overrides from the container environment
+	override, err := Parse(env[EnvCPU], env[EnvMemory])
+	if err != nil {
+		return l, err
+	}
+
+	if override.CPU > 0 {
+		l.CPU = override.CPU
+	}
+
+	if override.Memory > 0 {
+		l.Memory = override.Memory
+	}
+
+	// cap the CPU limit to the maximum
+	if limits.CPU > 0 && (l.CPU == 0 || l.CPU > limits.CPU) {
+		l.CPU = limits.CPU
+	}
+
+	// cap the memory limit to the maximum
+	if limits.Memory > 0 && (l.Memory == 0 || l.Memory > limits.Memory) {
+		l.Memory = limits.Memory
+	}
+
+	return l, nil
+}
@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package resource
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResource_Parse(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		failure bool
+		cpu     string
+		memory  string
+		want    Limits
+	}{
+		{
+			name:    "empty",
+			failure: false,
+			want:    Limits{},
+		},
+		{
+			name:    "cpus and docker memory",
+			failure: false,
+			cpu:     "1.5",
+			memory:  "512m",
+			want:    Limits{CPU: 1500, Memory: 512 * 1024 * 1024},
+		},
+		{
+			name:    "millicores and kubernetes memory",
+			failure: false,
+			cpu:     "250m",
+			memory:  "1Gi",
+			want:    Limits{CPU: 250, Memory: 1024 * 1024 * 1024},
+		},
+		{
+			name:    "bytes",
+			failure: false,
+			cpu:     "2",
+			memory:  "1048576",
+			want:    Limits{CPU: 2000, Memory: 1024 * 1024},
+		},
+		{
+			name:    "invalid cpu",
+			failure: true,
+			cpu:     "two",
+		},
+		{
+			name:    "negative cpu",
+			failure: true,
+			cpu:     "-1",
+		},
+		{
+			name:    "invalid memory",
+			failure: true,
+			memory:  "lots",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Parse(test.cpu, test.memory)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("Parse should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("Parse returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Parse is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestResource_Resolve(t *testing.T) {
+	// setup types
+	defaults := Limits{CPU: 1000, Memory: 1024 * 1024 * 1024}
+	limits := Limits{CPU: 4000, Memory: 8 * 1024 * 1024 * 1024}
+
+	// setup tests
+	tests := []struct {
+		name     string
+		failure  bool
+		env      map[string]string
+		defaults Limits
+		limits   Limits
+		want     Limits
+	}{
+		{
+			name:     "defaults",
+			failure:  false,
+			env:      map[string]string{"FOO": "bar"},
+			defaults: defaults,
+			limits:   limits,
+			want:     defaults,
+		},
+		{
+			name:     "environment overrides",
+			failure:  false,
+			env:      map[string]string{EnvCPU: "2", EnvMemory: "2g"},
+			defaults: defaults,
+			limits:   limits,
+			want:     Limits{CPU: 2000, Memory: 2 * 1024 * 1024 * 1024},
+		},
+		{
+			name:     "environment memory override",
+			failure:  false,
+			env:      map[string]string{EnvMemory: "512m"},
+			defaults: defaults,
+			limits:   limits,
+			want:     Limits{CPU: 1000, Memory: 512 * 1024 * 1024},
+		},
+		{
+			name:     "overrides capped at maximum",
+			failure:  false,
+			env:      map[string]string{EnvCPU: "16", EnvMemory: "64g"},
+			defaults: defaults,
+			limits:   limits,
+			want:     limits,
+		},
+		{
+			name:    "maximum applied without defaults",
+			failure: false,
+			limits:  limits,
+			want:    limits,
+		},
+		{
+			name:    "unlimited",
+			failure: false,
+			want:    Limits{},
+		},
+		{
+			name:     "invalid environment override",
+			failure:  true,
+			env:      map[string]string{EnvCPU: "two"},
+			defaults: defaults,
+			limits:   limits,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Resolve(test.env, test.defaults, test.limits)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("Resolve should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("Resolve returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Resolve is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestResource_Limits_String(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		limits Limits
+		want   string
+	}{
+		{limits: Limits{}, want: "cpu=unlimited memory=unlimited"},
+		{limits: Limits{CPU: 1500, Memory: 512 * 1024 * 1024}, want: "cpu=1500m memory=512MiB"},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			if got := test.limits.String(); got != test.want {
+				t.Errorf("String is %s, want %s", got, test.want)
+			}
+		})
+	}
+}
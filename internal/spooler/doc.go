@@ -0,0 +1,16 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package spooler provides the ability for Vela to batch
+// and retry uploading container logs to the server.
+//
+// The latest log for each container is uploaded at most
+// once per interval, failed uploads are retried with an
+// exponential backoff and pending logs are spilled to
+// local disk until the server is reachable again.
+// Logs still on disk when the worker stops are uploaded with
+// Recover the next time it starts.
+//
+// Usage:
+//
+//	import "github.com/go-vela/worker/internal/spooler"
+package spooler
@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package spooler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// KindService represents the kind of key for the log of a service.
+	KindService = "service"
+
+	// KindStep represents the kind of key for the log of a step.
+	KindStep = "step"
+)
+
+// Key represents the location of the log
+// for a container of a build on the server.
+//
+// The key of a log is recorded in the name of the file it
+// is spilled to, so it is available to Recover the log after
+// the worker restarts.
+type Key struct {
+	Org    string
+	Repo   string
+	Build  int64
+	Kind   string
+	Number int32
+}
+
+// String returns the key in the format accepted by ParseKey.
+func (k Key) String() string {
+	return fmt.Sprintf("%s/%s/%d/%s/%d", k.Org, k.Repo, k.Build, k.Kind, k.Number)
+}
+
+// ParseKey returns the Key for the log from the provided string.
+func ParseKey(key string) (Key, error) {
+	parts := strings.Split(key, "/")
+	if len(parts) != 5 {
+		return Key{}, fmt.Errorf("invalid log key provided: %s", key)
+	}
+
+	build, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return Key{}, fmt.Errorf("invalid build number in log key %s: %w", key, err)
+	}
+
+	number, err := strconv.ParseInt(parts[4], 10, 32)
+	if err != nil {
+		return Key{}, fmt.Errorf("invalid container number in log key %s: %w", key, err)
+	}
+
+	switch parts[3] {
+	case KindService, KindStep:
+	default:
+		return Key{}, fmt.Errorf("invalid kind in log key %s: %s", key, parts[3])
+	}
+
+	return Key{
+		Org:    parts[0],
+		Repo:   parts[1],
+		Build:  build,
+		Kind:   parts[3],
+		Number: int32(number),
+	}, nil
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package spooler
+
+import (
+	"testing"
+)
+
+func TestSpooler_ParseKey(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		failure bool
+		key     string
+		want    Key
+	}{
+		{
+			name:    "step",
+			failure: false,
+			key:     "octocat/hello-world/1/step/2",
+			want:    Key{Org: "octocat", Repo: "hello-world", Build: 1, Kind: KindStep, Number: 2},
+		},
+		{
+			name:    "service",
+			failure: false,
+			key:     "octocat/hello-world/1/service/1",
+			want:    Key{Org: "octocat", Repo: "hello-world", Build: 1, Kind: KindService, Number: 1},
+		},
+		{
+			name:    "missing parts",
+			failure: true,
+			key:     "octocat/hello-world/1",
+		},
+		{
+			name:    "invalid build",
+			failure: true,
+			key:     "octocat/hello-world/foo/step/2",
+		},
+		{
+			name:    "invalid number",
+			failure: true,
+			key:     "octocat/hello-world/1/step/foo",
+		},
+		{
+			name:    "invalid kind",
+			failure: true,
+			key:     "octocat/hello-world/1/stage/2",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseKey(test.key)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("ParseKey should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("ParseKey returned err: %v", err)
+			}
+
+			if got != test.want {
+				t.Errorf("ParseKey is %v, want %v", got, test.want)
+			}
+
+			if got.String() != test.key {
+				t.Errorf("String is %s, want %s", got.String(), test.key)
+			}
+		})
+	}
+}
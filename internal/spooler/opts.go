@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package spooler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Opt represents a configuration option to initialize the spooler.
+type Opt func(*Spooler) error
+
+// WithDirectory sets the directory for spilling logs to disk in the spooler.
+func WithDirectory(dir string) Opt {
+	return func(s *Spooler) error {
+		s.Logger.Trace("configuring directory in log spooler")
+
+		// set the directory in the spooler
+		s.dir = dir
+
+		return nil
+	}
+}
+
+// WithInterval sets the minimum time between uploads of a log in the spooler.
+func WithInterval(interval time.Duration) Opt {
+	return func(s *Spooler) error {
+		s.Logger.Trace("configuring interval in log spooler")
+
+		// check if the interval provided is valid
+		if interval <= 0 {
+			return fmt.Errorf("invalid interval provided: %s", interval)
+		}
+
+		// set the interval in the spooler
+		s.interval = interval
+
+		return nil
+	}
+}
+
+// WithMaxBackoff sets the maximum time between retries of a failed upload in the spooler.
+func WithMaxBackoff(backoff time.Duration) Opt {
+	return func(s *Spooler) error {
+		s.Logger.Trace("configuring maximum backoff in log spooler")
+
+		// check if the backoff provided is valid
+		if backoff <= 0 {
+			return fmt.Errorf("invalid maximum backoff provided: %s", backoff)
+		}
+
+		// set the maximum backoff in the spooler
+		s.maxBackoff = backoff
+
+		return nil
+	}
+}
+
+// WithLogger sets the logger in the spooler.
+func WithLogger(logger *logrus.Entry) Opt {
+	return func(s *Spooler) error {
+		s.Logger.Trace("configuring logger in log spooler")
+
+		// check if the logger provided is empty
+		if logger != nil {
+			// set the logger in the spooler
+			s.Logger = logger
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package spooler
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestSpooler_Opt_WithDirectory(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name string
+		dir  string
+	}{
+		{
+			name: "directory",
+			dir:  "/tmp/vela",
+		},
+		{
+			name: "empty directory",
+			dir:  "",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_spooler, err := New(WithDirectory(test.dir))
+			if err != nil {
+				t.Errorf("WithDirectory returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(_spooler.dir, test.dir) {
+				t.Errorf("WithDirectory is %v, want %v", _spooler.dir, test.dir)
+			}
+		})
+	}
+}
+
+func TestSpooler_Opt_WithInterval(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name     string
+		failure  bool
+		interval time.Duration
+	}{
+		{
+			name:     "interval",
+			failure:  false,
+			interval: 5 * time.Second,
+		},
+		{
+			name:     "zero interval",
+			failure:  true,
+			interval: 0,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_spooler, err := New(WithInterval(test.interval))
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("WithInterval should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("WithInterval returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(_spooler.interval, test.interval) {
+				t.Errorf("WithInterval is %v, want %v", _spooler.interval, test.interval)
+			}
+		})
+	}
+}
+
+func TestSpooler_Opt_WithMaxBackoff(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		failure bool
+		backoff time.Duration
+	}{
+		{
+			name:    "backoff",
+			failure: false,
+			backoff: 5 * time.Minute,
+		},
+		{
+			name:    "negative backoff",
+			failure: true,
+			backoff: -1,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_spooler, err := New(WithMaxBackoff(test.backoff))
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("WithMaxBackoff should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("WithMaxBackoff returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(_spooler.maxBackoff, test.backoff) {
+				t.Errorf("WithMaxBackoff is %v, want %v", _spooler.maxBackoff, test.backoff)
+			}
+		})
+	}
+}
+
+func TestSpooler_Opt_WithLogger(t *testing.T) {
+	// setup types
+	logger := logrus.NewEntry(logrus.StandardLogger()).WithField("build", 1)
+
+	_spooler, err := New(WithLogger(logger))
+	if err != nil {
+		t.Errorf("WithLogger returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(_spooler.Logger, logger) {
+		t.Errorf("WithLogger is %v, want %v", _spooler.Logger, logger)
+	}
+}
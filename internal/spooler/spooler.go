@@ -0,0 +1,490 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package spooler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultInterval represents the default minimum
+	// time between uploads of the log for a container.
+	DefaultInterval = time.Second
+
+	// DefaultMaxBackoff represents the default maximum
+	// time between retries of a failed upload.
+	DefaultMaxBackoff = time.Minute
+
+	// paceSize represents the log size after which uploads are
+	// spaced further apart. The server requires the full log for
+	// every upload so pacing keeps the bandwidth used by long
+	// running containers from growing quadratically.
+	paceSize = 1 << 20
+
+	// maxInterval represents the maximum time
+	// between uploads of the log for a container.
+	maxInterval = 30 * time.Second
+
+	// spillPrefix represents the prefix of the
+	// directories created for logs spilled to disk.
+	spillPrefix = "vela-logs-"
+
+	// spillExt represents the extension of the
+	// files created for logs spilled to disk.
+	spillExt = ".log"
+
+	// uploadTimeout represents the maximum time allowed for a single upload.
+	uploadTimeout = 30 * time.Second
+)
+
+// UploadFunc represents the function invoked to
+// upload the full contents of a log to the server.
+type UploadFunc func(ctx context.Context, data []byte) error
+
+// RecoverFunc represents the function invoked to upload the full
+// contents of a log spilled to disk by a previous spooler.
+type RecoverFunc func(ctx context.Context, key string, data []byte) error
+
+// Spooler represents the buffer for batching
+// and retrying uploads of container logs.
+type Spooler struct {
+	// https://pkg.go.dev/github.com/sirupsen/logrus#Entry
+	Logger *logrus.Entry
+
+	// configuration for the spooler
+	dir        string
+	interval   time.Duration
+	maxBackoff time.Duration
+
+	mu       sync.Mutex
+	logs     map[string]*spool
+	spillDir string
+
+	// background upload loop
+	start  sync.Once
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// spool represents the pending log for a container.
+type spool struct {
+	upload UploadFunc
+	// latest contents of the log when held in memory
+	data []byte
+	// location of the latest contents of the log when spilled to disk
+	path string
+	// incremented for every write to the log
+	version int
+	// version of the log last uploaded to the server
+	uploaded  int
+	uploading bool
+	failures  int
+	next      time.Time
+}
+
+// pending returns true if the log has contents not yet uploaded.
+func (s *spool) pending() bool {
+	return s.version > s.uploaded
+}
+
+// New returns a Spooler configured with the provided options.
+func New(opts ...Opt) (*Spooler, error) {
+	s := &Spooler{
+		interval:   DefaultInterval,
+		maxBackoff: DefaultMaxBackoff,
+		logs:       make(map[string]*spool),
+		done:       make(chan struct{}),
+	}
+
+	// create new logger for the spooler
+	//
+	// https://pkg.go.dev/github.com/sirupsen/logrus#NewEntry
+	s.Logger = logrus.NewEntry(logrus.StandardLogger())
+
+	// apply all provided configuration options
+	for _, opt := range opts {
+		err := opt(s)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+
+	return s, nil
+}
+
+// Write records the latest full contents of the log for the
+// provided key. The log is uploaded in the background with
+// the upload function no more than once per interval.
+func (s *Spooler) Write(key string, data []byte, upload UploadFunc) {
+	s.mu.Lock()
+
+	sp, ok := s.logs[key]
+	if !ok {
+		sp = new(spool)
+		s.logs[key] = sp
+	}
+
+	sp.upload = upload
+	sp.version++
+
+	// keep writing to disk while uploads for the log are failing
+	if len(sp.path) > 0 {
+		err := os.WriteFile(sp.path, data, 0o600)
+		if err == nil {
+			s.mu.Unlock()
+			s.run()
+
+			return
+		}
+
+		s.Logger.Errorf("unable to spill log %s to disk: %v", key, err)
+
+		_ = os.Remove(sp.path)
+		sp.path = ""
+	}
+
+	sp.data = slices.Clone(data)
+
+	s.mu.Unlock()
+	s.run()
+}
+
+// Flush uploads all pending logs, retrying failed uploads
+// until they succeed or the provided context is canceled.
+func (s *Spooler) Flush(ctx context.Context) error {
+	s.Logger.Trace("flushing pending logs")
+
+	// attempt to upload all pending logs regardless of backoff
+	s.process(ctx, true)
+
+	for {
+		wait, pending := s.nextAttempt()
+		if pending == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("unable to flush %d pending logs: %w", pending, ctx.Err())
+		case <-time.After(wait):
+			s.process(ctx, false)
+		}
+	}
+}
+
+// Close stops uploading logs in the background. The logs with
+// contents not yet uploaded are kept on disk to be uploaded
+// with Recover the next time the worker starts.
+func (s *Spooler) Close() error {
+	s.cancel()
+
+	// wait for the background loop if it was started
+	started := true
+
+	s.start.Do(func() {
+		started = false
+	})
+
+	if started {
+		<-s.done
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := false
+
+	for key, sp := range s.logs {
+		if !sp.pending() {
+			continue
+		}
+
+		s.spill(key, sp)
+
+		if len(sp.path) == 0 {
+			s.Logger.Errorf("discarding log %s with contents not uploaded", key)
+
+			continue
+		}
+
+		s.Logger.Warnf("keeping log %s with contents not uploaded in %s", key, sp.path)
+
+		kept = true
+	}
+
+	if len(s.spillDir) == 0 || kept {
+		return nil
+	}
+
+	return os.Remove(s.spillDir)
+}
+
+// Recover uploads the logs spilled to disk in the provided
+// directory by spoolers closed or interrupted before the
+// contents were uploaded. The logs are removed from disk once
+// uploaded and kept to retry on the next call otherwise.
+func Recover(ctx context.Context, dir string, upload RecoverFunc) error {
+	if len(dir) == 0 {
+		dir = os.TempDir()
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, spillPrefix+"*", "*"+spillExt))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, path := range paths {
+		key, err := url.PathUnescape(strings.TrimSuffix(filepath.Base(path), spillExt))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to parse spilled log %s: %w", path, err))
+
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to read spilled log %s: %w", key, err))
+
+			continue
+		}
+
+		if len(data) > 0 {
+			uploadCtx, cancel := context.WithTimeout(ctx, uploadTimeout)
+			err = upload(uploadCtx, key, data)
+
+			cancel()
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf("unable to upload spilled log %s: %w", key, err))
+
+				continue
+			}
+		}
+
+		err = os.Remove(path)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	dirs, err := filepath.Glob(filepath.Join(dir, spillPrefix+"*"))
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+
+	// remove the directories left empty by the uploaded logs
+	for _, d := range dirs {
+		_ = os.Remove(d)
+	}
+
+	return errors.Join(errs...)
+}
+
+// run starts uploading logs in the background
+// if it has not already been started.
+func (s *Spooler) run() {
+	s.start.Do(func() {
+		go func() {
+			defer close(s.done)
+
+			ticker := time.NewTicker(s.interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-s.ctx.Done():
+					return
+				case <-ticker.C:
+					s.process(s.ctx, false)
+				}
+			}
+		}()
+	})
+}
+
+// process uploads the pending logs that are due for an upload.
+// If force is true, the logs are uploaded regardless of backoff.
+func (s *Spooler) process(ctx context.Context, force bool) {
+	type upload struct {
+		key     string
+		sp      *spool
+		data    []byte
+		version int
+	}
+
+	var uploads []upload
+
+	now := time.Now()
+
+	s.mu.Lock()
+
+	for key, sp := range s.logs {
+		if !sp.pending() || sp.uploading || (!force && now.Before(sp.next)) {
+			continue
+		}
+
+		data := sp.data
+
+		// read the log from disk if it was spilled
+		if len(sp.path) > 0 {
+			var err error
+
+			data, err = os.ReadFile(sp.path)
+			if err != nil {
+				s.Logger.Errorf("unable to read spilled log %s: %v", key, err)
+
+				continue
+			}
+		}
+
+		sp.uploading = true
+
+		uploads = append(uploads, upload{key: key, sp: sp, data: data, version: sp.version})
+	}
+
+	s.mu.Unlock()
+
+	for _, u := range uploads {
+		uploadCtx, cancel := context.WithTimeout(ctx, uploadTimeout)
+		err := u.sp.upload(uploadCtx, u.data)
+
+		cancel()
+
+		s.mu.Lock()
+
+		u.sp.uploading = false
+
+		if err != nil {
+			u.sp.failures++
+			u.sp.next = time.Now().Add(s.backoff(u.sp.failures))
+
+			s.Logger.Warnf("unable to upload log %s (attempt %d): %v", u.key, u.sp.failures, err)
+
+			s.spill(u.key, u.sp)
+			s.mu.Unlock()
+
+			continue
+		}
+
+		u.sp.failures = 0
+		u.sp.uploaded = max(u.sp.uploaded, u.version)
+		u.sp.next = time.Now().Add(s.delay(len(u.data)))
+
+		// remove the log from disk once the latest contents are uploaded
+		if !u.sp.pending() && len(u.sp.path) > 0 {
+			_ = os.Remove(u.sp.path)
+			u.sp.path = ""
+		}
+
+		s.mu.Unlock()
+	}
+}
+
+// spill writes the in-memory contents of the log to disk so they
+// survive until the server is reachable. The lock must be held.
+func (s *Spooler) spill(key string, sp *spool) {
+	// check if the log is already on disk
+	if len(sp.path) > 0 {
+		return
+	}
+
+	if len(s.spillDir) == 0 {
+		dir := s.dir
+		if len(dir) == 0 {
+			dir = os.TempDir()
+		}
+
+		err := os.MkdirAll(dir, 0o700)
+		if err != nil {
+			s.Logger.Errorf("unable to create log spool directory %s: %v", dir, err)
+
+			return
+		}
+
+		s.spillDir, err = os.MkdirTemp(dir, spillPrefix)
+		if err != nil {
+			s.Logger.Errorf("unable to create log spool directory in %s: %v", dir, err)
+
+			return
+		}
+	}
+
+	// escape the key so it can be recovered from the file name
+	path := filepath.Join(s.spillDir, url.PathEscape(key)+spillExt)
+
+	err := os.WriteFile(path, sp.data, 0o600)
+	if err != nil {
+		s.Logger.Errorf("unable to spill log %s to disk: %v", key, err)
+
+		return
+	}
+
+	s.Logger.Debugf("spilled log %s to %s", key, path)
+
+	sp.path = path
+	sp.data = nil
+}
+
+// nextAttempt returns the time until the next pending
+// log is due for an upload and the number of pending logs.
+func (s *Spooler) nextAttempt() (time.Duration, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var (
+		wait    = s.interval
+		pending int
+	)
+
+	for _, sp := range s.logs {
+		if !sp.pending() {
+			continue
+		}
+
+		pending++
+
+		if !sp.uploading {
+			wait = min(wait, time.Until(sp.next))
+		}
+	}
+
+	return max(wait, 0), pending
+}
+
+// backoff returns the time to wait before retrying
+// an upload after the provided number of failures.
+func (s *Spooler) backoff(failures int) time.Duration {
+	d := s.interval
+
+	for i := 1; i < failures && d < s.maxBackoff; i++ {
+		d *= 2
+	}
+
+	return min(d, s.maxBackoff)
+}
+
+// delay returns the time to wait before uploading
+// the log again based on the size of the log.
+func (s *Spooler) delay(size int) time.Duration {
+	if size <= paceSize {
+		return s.interval
+	}
+
+	return max(s.interval, min(s.interval*time.Duration(size/paceSize+1), maxInterval))
+}
@@ -0,0 +1,407 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package spooler
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder is a test helper for capturing uploads.
+type recorder struct {
+	sync.Mutex
+
+	uploads [][]byte
+	fail    bool
+}
+
+// upload records the data uploaded or returns an error if failing.
+func (r *recorder) upload(_ context.Context, data []byte) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.fail {
+		return errors.New("server unavailable")
+	}
+
+	r.uploads = append(r.uploads, data)
+
+	return nil
+}
+
+// setFail sets whether uploads should fail.
+func (r *recorder) setFail(fail bool) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.fail = fail
+}
+
+// captured returns the uploads recorded.
+func (r *recorder) captured() [][]byte {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.uploads
+}
+
+func TestSpooler_Write_Coalesce(t *testing.T) {
+	// setup types
+	r := new(recorder)
+
+	s, err := New(WithInterval(50 * time.Millisecond))
+	if err != nil {
+		t.Fatalf("New returned err: %v", err)
+	}
+	defer s.Close()
+
+	s.Write("step_1", []byte("foo\n"), r.upload)
+	s.Write("step_1", []byte("foo\nbar\n"), r.upload)
+	s.Write("step_1", []byte("foo\nbar\nbaz\n"), r.upload)
+
+	waitFor(t, func() bool { return len(r.captured()) > 0 })
+
+	got := r.captured()
+
+	if len(got) != 1 {
+		t.Errorf("Write uploaded %d times, want 1", len(got))
+	}
+
+	if string(got[0]) != "foo\nbar\nbaz\n" {
+		t.Errorf("Write uploaded %q, want %q", got[0], "foo\nbar\nbaz\n")
+	}
+}
+
+func TestSpooler_Write_Retry(t *testing.T) {
+	// setup types
+	dir := t.TempDir()
+	r := &recorder{fail: true}
+
+	s, err := New(
+		WithDirectory(dir),
+		WithInterval(10*time.Millisecond),
+		WithMaxBackoff(20*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("New returned err: %v", err)
+	}
+	defer s.Close()
+
+	s.Write("step_1", []byte("foo\n"), r.upload)
+
+	// wait for the log to be spilled to disk
+	waitFor(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		return len(s.logs["step_1"].path) > 0
+	})
+
+	s.mu.Lock()
+	path := s.logs["step_1"].path
+	s.mu.Unlock()
+
+	// write more data while the server is unavailable
+	s.Write("step_1", []byte("foo\nbar\n"), r.upload)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("ReadFile returned err: %v", err)
+	}
+
+	if string(data) != "foo\nbar\n" {
+		t.Errorf("spilled log is %q, want %q", data, "foo\nbar\n")
+	}
+
+	// recover the server
+	r.setFail(false)
+
+	waitFor(t, func() bool { return len(r.captured()) > 0 })
+
+	got := r.captured()
+	if string(got[len(got)-1]) != "foo\nbar\n" {
+		t.Errorf("Write uploaded %q, want %q", got[len(got)-1], "foo\nbar\n")
+	}
+
+	waitFor(t, func() bool {
+		_, err := os.Stat(path)
+
+		return os.IsNotExist(err)
+	})
+}
+
+func TestSpooler_Flush(t *testing.T) {
+	// setup types
+	r := new(recorder)
+
+	s, err := New(WithInterval(time.Hour))
+	if err != nil {
+		t.Fatalf("New returned err: %v", err)
+	}
+	defer s.Close()
+
+	s.Write("step_1", []byte("foo\n"), r.upload)
+	s.Write("service_1", []byte("bar\n"), r.upload)
+
+	err = s.Flush(context.Background())
+	if err != nil {
+		t.Errorf("Flush returned err: %v", err)
+	}
+
+	if len(r.captured()) != 2 {
+		t.Errorf("Flush uploaded %d times, want 2", len(r.captured()))
+	}
+
+	// ensure nothing is uploaded without new data
+	err = s.Flush(context.Background())
+	if err != nil {
+		t.Errorf("Flush returned err: %v", err)
+	}
+
+	if len(r.captured()) != 2 {
+		t.Errorf("Flush uploaded %d times, want 2", len(r.captured()))
+	}
+}
+
+func TestSpooler_Flush_Failure(t *testing.T) {
+	// setup types
+	dir := t.TempDir()
+	r := &recorder{fail: true}
+
+	s, err := New(
+		WithDirectory(dir),
+		WithInterval(10*time.Millisecond),
+		WithMaxBackoff(20*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("New returned err: %v", err)
+	}
+
+	s.Write("octocat/hello-world/1/step/1", []byte("foo\n"), r.upload)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err = s.Flush(ctx)
+	if err == nil {
+		t.Errorf("Flush should have returned err")
+	}
+
+	// ensure the spilled logs are kept
+	err = s.Close()
+	if err != nil {
+		t.Errorf("Close returned err: %v", err)
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*", "*"))
+	if err != nil {
+		t.Errorf("Glob returned err: %v", err)
+	}
+
+	if len(paths) != 1 {
+		t.Errorf("Close left %d logs in %s, want 1", len(paths), dir)
+	}
+}
+
+func TestSpooler_Close(t *testing.T) {
+	// setup types
+	dir := t.TempDir()
+	r := new(recorder)
+
+	s, err := New(WithDirectory(dir), WithInterval(time.Hour))
+	if err != nil {
+		t.Fatalf("New returned err: %v", err)
+	}
+
+	// spill a log that was uploaded
+	s.Write("step_1", []byte("foo\n"), r.upload)
+
+	s.mu.Lock()
+	s.spill("step_1", s.logs["step_1"])
+	s.mu.Unlock()
+
+	err = s.Flush(context.Background())
+	if err != nil {
+		t.Errorf("Flush returned err: %v", err)
+	}
+
+	err = s.Close()
+	if err != nil {
+		t.Errorf("Close returned err: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Errorf("ReadDir returned err: %v", err)
+	}
+
+	if len(entries) > 0 {
+		t.Errorf("Close left %d entries in %s, want 0", len(entries), dir)
+	}
+}
+
+func TestSpooler_Recover(t *testing.T) {
+	// setup types
+	dir := t.TempDir()
+
+	s, err := New(WithDirectory(dir), WithInterval(time.Hour))
+	if err != nil {
+		t.Fatalf("New returned err: %v", err)
+	}
+
+	first := &recorder{}
+
+	s.Write("octocat/hello-world/1/step/1", []byte("foo\n"), first.upload)
+
+	err = s.Flush(context.Background())
+	if err != nil {
+		t.Errorf("Flush returned err: %v", err)
+	}
+
+	// close the spooler with contents not uploaded
+	s.Write("octocat/hello-world/1/step/1", []byte("foo\nbar\n"), first.upload)
+
+	err = s.Close()
+	if err != nil {
+		t.Errorf("Close returned err: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		name    string
+		failure bool
+		fail    bool
+		want    map[string]string
+		left    int
+	}{
+		{
+			name:    "server unavailable",
+			failure: true,
+			fail:    true,
+			want:    map[string]string{},
+			left:    1,
+		},
+		{
+			name:    "uploaded",
+			failure: false,
+			want:    map[string]string{"octocat/hello-world/1/step/1": "foo\nbar\n"},
+		},
+		{
+			name:    "nothing spilled",
+			failure: false,
+			want:    map[string]string{},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := make(map[string]string)
+
+			err := Recover(context.Background(), dir, func(_ context.Context, key string, data []byte) error {
+				if test.fail {
+					return errors.New("server unavailable")
+				}
+
+				got[key] = string(data)
+
+				return nil
+			})
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("Recover should have returned err")
+				}
+			} else if err != nil {
+				t.Errorf("Recover returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Recover uploaded %v, want %v", got, test.want)
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Errorf("ReadDir returned err: %v", err)
+			}
+
+			if len(entries) != test.left {
+				t.Errorf("Recover left %d entries in %s, want %d", len(entries), dir, test.left)
+			}
+		})
+	}
+}
+
+func TestSpooler_backoff(t *testing.T) {
+	// setup types
+	s := &Spooler{interval: time.Second, maxBackoff: 10 * time.Second}
+
+	// setup tests
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{failures: 1, want: time.Second},
+		{failures: 2, want: 2 * time.Second},
+		{failures: 3, want: 4 * time.Second},
+		{failures: 4, want: 8 * time.Second},
+		{failures: 5, want: 10 * time.Second},
+		{failures: 100, want: 10 * time.Second},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := s.backoff(test.failures)
+
+		if got != test.want {
+			t.Errorf("backoff for %d failures is %v, want %v", test.failures, got, test.want)
+		}
+	}
+}
+
+func TestSpooler_delay(t *testing.T) {
+	// setup types
+	s := &Spooler{interval: time.Second}
+
+	// setup tests
+	tests := []struct {
+		size int
+		want time.Duration
+	}{
+		{size: 0, want: time.Second},
+		{size: paceSize, want: time.Second},
+		{size: 4 * paceSize, want: 5 * time.Second},
+		{size: 100 * paceSize, want: maxInterval},
+	}
+
+	// run tests
+	for _, test := range tests {
+		got := s.delay(test.size)
+
+		if got != test.want {
+			t.Errorf("delay for %d bytes is %v, want %v", test.size, got, test.want)
+		}
+	}
+}
+
+// waitFor is a helper function to wait
+// for the condition to be met for tests.
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for condition")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package step
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-vela/server/compiler/types/pipeline"
+)
+
+const (
+	// RetryAttemptsKey represents the reserved environment key for
+	// the maximum number of times a step runs before it fails.
+	RetryAttemptsKey = "VELA_STEP_RETRY_ATTEMPTS"
+
+	// RetryBackoffKey represents the reserved environment key for the
+	// delay before the first retry of a step, doubled for every retry after it.
+	RetryBackoffKey = "VELA_STEP_RETRY_BACKOFF"
+
+	// RetryExitCodesKey represents the reserved environment key for
+	// the comma-separated exit codes a step is retried for.
+	RetryExitCodesKey = "VELA_STEP_RETRY_EXIT_CODES"
+
+	// maxRetryBackoff represents the maximum delay between retries of a step.
+	maxRetryBackoff = 5 * time.Minute
+)
+
+// RetryPolicy represents the policy for retrying a failed step.
+type RetryPolicy struct {
+	// Attempts is the maximum number of times the step runs
+	Attempts int
+	// Backoff is the delay before the first retry of the step
+	// which is doubled for every retry after it
+	Backoff time.Duration
+	// ExitCodes are the exit codes the step is retried for,
+	// any unsuccessful exit code is retried when empty
+	ExitCodes []int32
+}
+
+// Retry returns the policy for retrying the container from the
+// reserved environment keys, using the provided default policy
+// for the keys that are unset.
+func Retry(c *pipeline.Container, defaults RetryPolicy) (RetryPolicy, error) {
+	p := defaults
+
+	// check if the container provided is empty
+	if c == nil {
+		return p, nil
+	}
+
+	if value := c.Environment[RetryAttemptsKey]; len(value) > 0 {
+		attempts, err := strconv.Atoi(value)
+		if err != nil || attempts < 1 {
+			return p, fmt.Errorf("invalid %s provided for %s: %s", RetryAttemptsKey, c.Name, value)
+		}
+
+		p.Attempts = attempts
+	}
+
+	if value := c.Environment[RetryBackoffKey]; len(value) > 0 {
+		backoff, err := time.ParseDuration(value)
+		if err != nil || backoff < 0 {
+			return p, fmt.Errorf("invalid %s provided for %s: %s", RetryBackoffKey, c.Name, value)
+		}
+
+		p.Backoff = backoff
+	}
+
+	if value := c.Environment[RetryExitCodesKey]; len(value) > 0 {
+		p.ExitCodes = nil
+
+		for code := range strings.SplitSeq(value, ",") {
+			exitCode, err := strconv.ParseInt(strings.TrimSpace(code), 10, 32)
+			if err != nil || exitCode == 0 {
+				return p, fmt.Errorf("invalid %s provided for %s: %s", RetryExitCodesKey, c.Name, value)
+			}
+
+			p.ExitCodes = append(p.ExitCodes, int32(exitCode))
+		}
+	}
+
+	return p, nil
+}
+
+// Retryable returns true if the step should run again
+// after the attempt completed with the exit code.
+func (p RetryPolicy) Retryable(attempt int, exitCode int32) bool {
+	if exitCode == 0 || attempt >= p.Attempts {
+		return false
+	}
+
+	return len(p.ExitCodes) == 0 || slices.Contains(p.ExitCodes, exitCode)
+}
+
+// Delay returns the time to wait before running the
+// step again after the attempt completed.
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	delay := p.Backoff
+
+	for range attempt - 1 {
+		if delay >= maxRetryBackoff {
+			break
+		}
+
+		delay *= 2
+	}
+
+	return min(delay, maxRetryBackoff)
+}
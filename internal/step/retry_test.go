@@ -0,0 +1,195 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package step
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-vela/server/compiler/types/pipeline"
+)
+
+func TestStep_Retry(t *testing.T) {
+	// setup types
+	defaults := RetryPolicy{Attempts: 1, Backoff: 10 * time.Second}
+
+	// setup tests
+	tests := []struct {
+		name      string
+		failure   bool
+		container *pipeline.Container
+		want      RetryPolicy
+	}{
+		{
+			name:      "default",
+			failure:   false,
+			container: &pipeline.Container{Name: "test", Environment: map[string]string{}},
+			want:      defaults,
+		},
+		{
+			name:    "attempts and backoff",
+			failure: false,
+			container: &pipeline.Container{Name: "test", Environment: map[string]string{
+				RetryAttemptsKey: "3",
+				RetryBackoffKey:  "30s",
+			}},
+			want: RetryPolicy{Attempts: 3, Backoff: 30 * time.Second},
+		},
+		{
+			name:    "exit codes",
+			failure: false,
+			container: &pipeline.Container{Name: "test", Environment: map[string]string{
+				RetryAttemptsKey:  "2",
+				RetryExitCodesKey: "1, 137",
+			}},
+			want: RetryPolicy{Attempts: 2, Backoff: 10 * time.Second, ExitCodes: []int32{1, 137}},
+		},
+		{
+			name:      "nil container",
+			failure:   false,
+			container: nil,
+			want:      defaults,
+		},
+		{
+			name:      "invalid attempts",
+			failure:   true,
+			container: &pipeline.Container{Name: "test", Environment: map[string]string{RetryAttemptsKey: "0"}},
+		},
+		{
+			name:      "invalid backoff",
+			failure:   true,
+			container: &pipeline.Container{Name: "test", Environment: map[string]string{RetryBackoffKey: "-5s"}},
+		},
+		{
+			name:      "invalid exit codes",
+			failure:   true,
+			container: &pipeline.Container{Name: "test", Environment: map[string]string{RetryExitCodesKey: "1,foo"}},
+		},
+		{
+			name:      "successful exit code",
+			failure:   true,
+			container: &pipeline.Container{Name: "test", Environment: map[string]string{RetryExitCodesKey: "0"}},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Retry(test.container, defaults)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("Retry should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("Retry returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Retry is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestStep_RetryPolicy_Retryable(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name     string
+		policy   RetryPolicy
+		attempt  int
+		exitCode int32
+		want     bool
+	}{
+		{
+			name:     "success",
+			policy:   RetryPolicy{Attempts: 3},
+			attempt:  1,
+			exitCode: 0,
+			want:     false,
+		},
+		{
+			name:     "any exit code",
+			policy:   RetryPolicy{Attempts: 3},
+			attempt:  2,
+			exitCode: 1,
+			want:     true,
+		},
+		{
+			name:     "attempts exhausted",
+			policy:   RetryPolicy{Attempts: 3},
+			attempt:  3,
+			exitCode: 1,
+			want:     false,
+		},
+		{
+			name:     "matching exit code",
+			policy:   RetryPolicy{Attempts: 3, ExitCodes: []int32{137}},
+			attempt:  1,
+			exitCode: 137,
+			want:     true,
+		},
+		{
+			name:     "other exit code",
+			policy:   RetryPolicy{Attempts: 3, ExitCodes: []int32{137}},
+			attempt:  1,
+			exitCode: 1,
+			want:     false,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.policy.Retryable(test.attempt, test.exitCode)
+
+			if got != test.want {
+				t.Errorf("Retryable is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestStep_RetryPolicy_Delay(t *testing.T) {
+	// setup types
+	policy := RetryPolicy{Attempts: 10, Backoff: 30 * time.Second}
+
+	// setup tests
+	tests := []struct {
+		name    string
+		attempt int
+		want    time.Duration
+	}{
+		{
+			name:    "first retry",
+			attempt: 1,
+			want:    30 * time.Second,
+		},
+		{
+			name:    "third retry",
+			attempt: 3,
+			want:    2 * time.Minute,
+		},
+		{
+			name:    "capped",
+			attempt: 8,
+			want:    maxRetryBackoff,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := policy.Delay(test.attempt)
+
+			if got != test.want {
+				t.Errorf("Delay is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
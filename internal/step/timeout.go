@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package step
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-vela/server/compiler/types/pipeline"
+)
+
+const (
+	// TimeoutKey represents the reserved environment key
+	// for the maximum time a step may run before it is stopped.
+	TimeoutKey = "VELA_STEP_TIMEOUT"
+
+	// StageTimeoutKey represents the reserved environment key
+	// for the maximum time the steps of a stage may run.
+	StageTimeoutKey = "VELA_STAGE_TIMEOUT"
+
+	// ExitCodeTimeout represents the exit code for a step stopped
+	// after exceeding its timeout, following the timeout(1) convention.
+	ExitCodeTimeout = 124
+)
+
+// Timeout returns the maximum time the container may run from the
+// reserved environment key or the provided default if it is unset.
+//
+// The timeout is a duration (i.e. 90s or 10m) or a number of minutes
+// matching the timeout for the repo, and 0 disables the timeout.
+func Timeout(c *pipeline.Container, fallback time.Duration) (time.Duration, error) {
+	// check if the container provided is empty
+	if c == nil {
+		return fallback, nil
+	}
+
+	return parseTimeout(TimeoutKey, c.Name, c.Environment[TimeoutKey], fallback)
+}
+
+// StageTimeout returns the maximum time the steps of the stage may run
+// from the reserved environment key or the provided default if it is unset.
+//
+// The environment for the stage is merged into each of its steps when
+// the pipeline is compiled, so the key is captured from the first step
+// that sets it.
+func StageTimeout(s *pipeline.Stage, fallback time.Duration) (time.Duration, error) {
+	// check if the stage provided is empty
+	if s == nil {
+		return fallback, nil
+	}
+
+	for _, ctn := range s.Steps {
+		value, ok := ctn.Environment[StageTimeoutKey]
+		if ok && len(value) > 0 {
+			return parseTimeout(StageTimeoutKey, s.Name, value, fallback)
+		}
+	}
+
+	return fallback, nil
+}
+
+// parseTimeout is a helper function to parse the value
+// provided for a timeout from the reserved environment key.
+func parseTimeout(key, name, value string, fallback time.Duration) (time.Duration, error) {
+	if len(value) == 0 {
+		return fallback, nil
+	}
+
+	original := value
+
+	// handle a number of minutes being provided
+	if minutes, err := strconv.Atoi(value); err == nil {
+		value = fmt.Sprintf("%dm", minutes)
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s provided for %s: %s", key, name, original)
+	}
+
+	if timeout < 0 {
+		return 0, fmt.Errorf("invalid %s provided for %s: %s must not be negative", key, name, timeout)
+	}
+
+	return timeout, nil
+}
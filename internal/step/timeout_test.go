@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package step
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-vela/server/compiler/types/pipeline"
+)
+
+func TestStep_Timeout(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name      string
+		failure   bool
+		container *pipeline.Container
+		want      time.Duration
+	}{
+		{
+			name:      "default",
+			failure:   false,
+			container: &pipeline.Container{Name: "test", Environment: map[string]string{}},
+			want:      time.Hour,
+		},
+		{
+			name:      "duration",
+			failure:   false,
+			container: &pipeline.Container{Name: "test", Environment: map[string]string{TimeoutKey: "90s"}},
+			want:      90 * time.Second,
+		},
+		{
+			name:      "minutes",
+			failure:   false,
+			container: &pipeline.Container{Name: "test", Environment: map[string]string{TimeoutKey: "10"}},
+			want:      10 * time.Minute,
+		},
+		{
+			name:      "disabled",
+			failure:   false,
+			container: &pipeline.Container{Name: "test", Environment: map[string]string{TimeoutKey: "0"}},
+			want:      0,
+		},
+		{
+			name:      "nil container",
+			failure:   false,
+			container: nil,
+			want:      time.Hour,
+		},
+		{
+			name:      "invalid",
+			failure:   true,
+			container: &pipeline.Container{Name: "test", Environment: map[string]string{TimeoutKey: "forever"}},
+		},
+		{
+			name:      "negative",
+			failure:   true,
+			container: &pipeline.Container{Name: "test", Environment: map[string]string{TimeoutKey: "-5m"}},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Timeout(test.container, time.Hour)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("Timeout should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("Timeout returned err: %v", err)
+			}
+
+			if got != test.want {
+				t.Errorf("Timeout is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestStep_StageTimeout(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		failure bool
+		stage   *pipeline.Stage
+		want    time.Duration
+	}{
+		{
+			name:    "default",
+			failure: false,
+			stage: &pipeline.Stage{Name: "test", Steps: pipeline.ContainerSlice{
+				{Name: "echo", Environment: map[string]string{TimeoutKey: "90s"}},
+			}},
+			want: time.Hour,
+		},
+		{
+			name:    "duration",
+			failure: false,
+			stage: &pipeline.Stage{Name: "test", Steps: pipeline.ContainerSlice{
+				{Name: "echo", Environment: map[string]string{}},
+				{Name: "test", Environment: map[string]string{StageTimeoutKey: "90s"}},
+			}},
+			want: 90 * time.Second,
+		},
+		{
+			name:    "minutes",
+			failure: false,
+			stage: &pipeline.Stage{Name: "test", Steps: pipeline.ContainerSlice{
+				{Name: "echo", Environment: map[string]string{StageTimeoutKey: "10"}},
+			}},
+			want: 10 * time.Minute,
+		},
+		{
+			name:    "disabled",
+			failure: false,
+			stage: &pipeline.Stage{Name: "test", Steps: pipeline.ContainerSlice{
+				{Name: "echo", Environment: map[string]string{StageTimeoutKey: "0"}},
+			}},
+			want: 0,
+		},
+		{
+			name:    "nil stage",
+			failure: false,
+			stage:   nil,
+			want:    time.Hour,
+		},
+		{
+			name:    "invalid",
+			failure: true,
+			stage: &pipeline.Stage{Name: "test", Steps: pipeline.ContainerSlice{
+				{Name: "echo", Environment: map[string]string{StageTimeoutKey: "forever"}},
+			}},
+		},
+		{
+			name:    "negative",
+			failure: true,
+			stage: &pipeline.Stage{Name: "test", Steps: pipeline.ContainerSlice{
+				{Name: "echo", Environment: map[string]string{StageTimeoutKey: "-5m"}},
+			}},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := StageTimeout(test.stage, time.Hour)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("StageTimeout should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("StageTimeout returned err: %v", err)
+			}
+
+			if got != test.want {
+				t.Errorf("StageTimeout is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
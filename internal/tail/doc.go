@@ -0,0 +1,13 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tail provides the ability for Vela to fan out
+// the live output of containers to the clients watching
+// the logs from the worker API.
+//
+// Output is only delivered while it is published, so clients
+// that subscribe after a container finishes receive nothing.
+//
+// Usage:
+//
+//	import "github.com/go-vela/worker/internal/tail"
+package tail
@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tail
+
+import (
+	"context"
+	"sync"
+)
+
+// bufferSize represents the number of lines held for each
+// subscriber before the subscriber is considered too slow
+// to keep up with the container and is disconnected.
+const bufferSize = 1024
+
+// Hub represents the fan out of the live
+// output for the containers in a build.
+type Hub struct {
+	mu sync.Mutex
+	// subscribers for each container
+	subs map[string]map[chan []byte]struct{}
+	// containers that finished publishing output
+	finished map[string]bool
+	// set once the build is complete
+	closed bool
+}
+
+// New returns a Hub for the containers in a build.
+func New() *Hub {
+	return &Hub{
+		subs:     make(map[string]map[chan []byte]struct{}),
+		finished: make(map[string]bool),
+	}
+}
+
+// Subscribe returns a channel receiving the lines published for the
+// container. The channel is closed once the container finishes, the
+// hub is closed, the subscriber falls behind or the context is done.
+func (h *Hub) Subscribe(ctx context.Context, id string) <-chan []byte {
+	ch := make(chan []byte, bufferSize)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// nothing else will be published for the container
+	if h.closed || h.finished[id] {
+		close(ch)
+
+		return ch
+	}
+
+	if h.subs[id] == nil {
+		h.subs[id] = make(map[chan []byte]struct{})
+	}
+
+	h.subs[id][ch] = struct{}{}
+
+	// remove the subscriber once the client goes away
+	go func() {
+		<-ctx.Done()
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		h.remove(id, ch)
+	}()
+
+	return ch
+}
+
+// Publish sends the line to the subscribers for the container.
+func (h *Hub) Publish(id string, line []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for ch := range h.subs[id] {
+		select {
+		case ch <- line:
+		default:
+			// disconnect the subscriber rather than block the
+			// container output or silently drop lines from it
+			h.remove(id, ch)
+		}
+	}
+}
+
+// Finish closes the subscribers for the container
+// since no more output will be published for it.
+func (h *Hub) Finish(id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.finished[id] = true
+
+	for ch := range h.subs[id] {
+		h.remove(id, ch)
+	}
+}
+
+// Reopen allows subscribing to the container again
+// since it will publish more output, i.e. when a
+// step is retried after the previous attempt failed.
+func (h *Hub) Reopen(id string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.finished, id)
+}
+
+// Close closes the subscribers for all containers
+// since no more output will be published for the build.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+
+	for id, subs := range h.subs {
+		for ch := range subs {
+			h.remove(id, ch)
+		}
+	}
+}
+
+// remove is a helper function to close the channel
+// for the subscriber if it has not been removed yet.
+//
+// The caller must hold the lock for the hub.
+func (h *Hub) remove(id string, ch chan []byte) {
+	if _, ok := h.subs[id][ch]; !ok {
+		return
+	}
+
+	delete(h.subs[id], ch)
+
+	if len(h.subs[id]) == 0 {
+		delete(h.subs, id)
+	}
+
+	close(ch)
+}
@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tail
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// drain is a test helper to capture the lines
+// received until the channel is closed.
+func drain(t *testing.T, ch <-chan []byte) []string {
+	t.Helper()
+
+	var lines []string
+
+	for {
+		select {
+		case line, ok := <-ch:
+			if !ok {
+				return lines
+			}
+
+			lines = append(lines, string(line))
+		case <-time.After(time.Second):
+			t.Fatalf("channel was not closed")
+		}
+	}
+}
+
+func TestTail_Hub_Publish(t *testing.T) {
+	// setup types
+	h := New()
+
+	step := h.Subscribe(context.Background(), "step_github_octocat_1_echo")
+	other := h.Subscribe(context.Background(), "step_github_octocat_1_test")
+
+	h.Publish("step_github_octocat_1_echo", []byte("hello"))
+	h.Publish("step_github_octocat_1_echo", []byte("world"))
+	h.Finish("step_github_octocat_1_echo")
+	h.Close()
+
+	want := []string{"hello", "world"}
+
+	got := drain(t, step)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Subscribe received %v, want %v", got, want)
+	}
+
+	got = drain(t, other)
+	if len(got) > 0 {
+		t.Errorf("Subscribe received %v for other container", got)
+	}
+}
+
+func TestTail_Hub_Subscribe(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name  string
+		setup func(h *Hub)
+	}{
+		{
+			name:  "finished container",
+			setup: func(h *Hub) { h.Finish("step_github_octocat_1_echo") },
+		},
+		{
+			name:  "closed hub",
+			setup: func(h *Hub) { h.Close() },
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := New()
+
+			test.setup(h)
+
+			ch := h.Subscribe(context.Background(), "step_github_octocat_1_echo")
+
+			h.Publish("step_github_octocat_1_echo", []byte("hello"))
+
+			got := drain(t, ch)
+			if len(got) > 0 {
+				t.Errorf("Subscribe received %v, want nothing", got)
+			}
+		})
+	}
+}
+
+func TestTail_Hub_Reopen(t *testing.T) {
+	// setup types
+	h := New()
+
+	h.Finish("step_github_octocat_1_echo")
+	h.Reopen("step_github_octocat_1_echo")
+
+	ch := h.Subscribe(context.Background(), "step_github_octocat_1_echo")
+
+	h.Publish("step_github_octocat_1_echo", []byte("hello"))
+	h.Finish("step_github_octocat_1_echo")
+
+	want := []string{"hello"}
+
+	got := drain(t, ch)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Subscribe received %v, want %v", got, want)
+	}
+}
+
+func TestTail_Hub_Subscribe_Canceled(t *testing.T) {
+	// setup types
+	h := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch := h.Subscribe(ctx, "step_github_octocat_1_echo")
+
+	cancel()
+
+	drain(t, ch)
+
+	// publishing after the subscriber is removed should not panic
+	h.Publish("step_github_octocat_1_echo", []byte("hello"))
+}
+
+func TestTail_Hub_Publish_Slow(t *testing.T) {
+	// setup types
+	h := New()
+
+	ch := h.Subscribe(context.Background(), "step_github_octocat_1_echo")
+
+	for range bufferSize + 1 {
+		h.Publish("step_github_octocat_1_echo", []byte("hello"))
+	}
+
+	got := drain(t, ch)
+	if len(got) != bufferSize {
+		t.Errorf("Subscribe received %d lines, want %d", len(got), bufferSize)
+	}
+}
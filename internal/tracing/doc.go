@@ -0,0 +1,12 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tracing provides the ability for Vela to trace
+// the builds executed by the worker with OpenTelemetry.
+//
+// Spans are exported with OTLP over HTTP when tracing is
+// enabled, otherwise the spans created are discarded.
+//
+// Usage:
+//
+//	import "github.com/go-vela/worker/internal/tracing"
+package tracing
@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// name represents the name of the tracer for the worker.
+const name = "github.com/go-vela/worker"
+
+// clientTimeout represents the timeout for the API calls
+// made with the client for the Vela SDK.
+const clientTimeout = 15 * time.Second
+
+// Config represents the configuration for exporting traces.
+type Config struct {
+	// Enabled exports the spans created by the worker
+	Enabled bool
+	// Endpoint is the OTLP HTTP endpoint for exporting spans,
+	// the OTEL_EXPORTER_OTLP_* environment variables are used when empty
+	Endpoint string
+	// SampleRatio is the ratio of builds sampled for tracing
+	SampleRatio float64
+	// Hostname is the hostname of the worker
+	Hostname string
+	// Version is the version of the worker
+	Version string
+}
+
+// propagator represents the format for propagating trace context
+// to the server and the containers executed by the worker.
+var propagator = propagation.NewCompositeTextMapPropagator(
+	propagation.TraceContext{},
+	propagation.Baggage{},
+)
+
+// Setup configures the global tracer provider for exporting the spans
+// created by the worker and returns the function to flush the spans
+// pending export on shutdown.
+func Setup(ctx context.Context, c *Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagator)
+
+	if c == nil || !c.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return nil, fmt.Errorf("invalid tracing sample ratio %v: must be between 0 and 1", c.SampleRatio)
+	}
+
+	var opts []otlptracehttp.Option
+
+	if len(c.Endpoint) > 0 {
+		opts = append(opts, otlptracehttp.WithEndpointURL(c.Endpoint))
+	}
+
+	// https://pkg.go.dev/go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp#New
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName("vela-worker"),
+		semconv.ServiceVersion(c.Version),
+		semconv.HostName(c.Hostname),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(c.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Start creates a span with the name as a child of the span in the context.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartAt creates a span with the name as a child of the span
+// in the context that started at the provided time.
+func StartAt(ctx context.Context, name string, t time.Time, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer().Start(ctx, name, trace.WithTimestamp(t), trace.WithAttributes(attrs...))
+}
+
+// End records the error, if any, on the span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// Environment returns the environment variables for propagating
+// the trace context in the context to a container.
+//
+// The variables follow the naming of the W3C trace context headers,
+// i.e. TRACEPARENT and TRACESTATE, used by most OpenTelemetry SDKs.
+func Environment(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+
+	propagator.Inject(ctx, carrier)
+
+	env := make(map[string]string, len(carrier))
+
+	for key, value := range carrier {
+		env[strings.ToUpper(key)] = value
+	}
+
+	return env
+}
+
+// Transport returns an http.RoundTripper that creates a span
+// for every request and propagates the trace context with it.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(base,
+		otelhttp.WithPropagators(propagator),
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			// the path is captured as an attribute to keep span names generic
+			return fmt.Sprintf("vela.sdk %s", r.Method)
+		}),
+	)
+}
+
+// HTTPClient returns an http.Client for the Vela SDK
+// that creates a span for every API call.
+//
+// The client times out API calls like the default client for the
+// Vela SDK, which is only used when no client is provided.
+func HTTPClient() *http.Client {
+	return &http.Client{
+		Transport: Transport(http.DefaultTransport),
+		Timeout:   clientTimeout,
+	}
+}
+
+// tracer is a helper function to return the tracer
+// from the global tracer provider when it is used
+// so spans are exported once tracing is set up.
+func tracer() trace.Tracer {
+	return otel.Tracer(name)
+}
@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing_Setup(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		failure bool
+		config  *Config
+	}{
+		{
+			name:    "nil config",
+			failure: false,
+			config:  nil,
+		},
+		{
+			name:    "disabled",
+			failure: false,
+			config:  &Config{Enabled: false, SampleRatio: 5},
+		},
+		{
+			name:    "enabled",
+			failure: false,
+			config:  &Config{Enabled: true, Endpoint: "http://localhost:4318", SampleRatio: 1},
+		},
+		{
+			name:    "invalid sample ratio",
+			failure: true,
+			config:  &Config{Enabled: true, SampleRatio: 1.5},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			shutdown, err := Setup(context.Background(), test.config)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("Setup should have returned err")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Errorf("Setup returned err: %v", err)
+			}
+
+			err = shutdown(context.Background())
+			if err != nil {
+				t.Errorf("shutdown returned err: %v", err)
+			}
+		})
+	}
+}
+
+func TestTracing_Environment(t *testing.T) {
+	// setup types
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	want := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	// run test
+	got := Environment(ctx)
+
+	if got["TRACEPARENT"] != want {
+		t.Errorf("Environment TRACEPARENT is %s, want %s", got["TRACEPARENT"], want)
+	}
+
+	got = Environment(context.Background())
+
+	if len(got) > 0 {
+		t.Errorf("Environment is %v, want empty", got)
+	}
+}
+
+func TestTracing_End(t *testing.T) {
+	// setup types
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	_, success := provider.Tracer(name).Start(context.Background(), "success")
+	_, failure := provider.Tracer(name).Start(context.Background(), "failure")
+
+	// run test
+	End(success, nil)
+	End(failure, errors.New("test"))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("End ended %d spans, want 2", len(spans))
+	}
+
+	if spans[0].Status().Code != codes.Unset {
+		t.Errorf("End status is %v, want %v", spans[0].Status().Code, codes.Unset)
+	}
+
+	if spans[1].Status().Code != codes.Error {
+		t.Errorf("End status is %v, want %v", spans[1].Status().Code, codes.Error)
+	}
+}
+
+func TestTracing_HTTPClient(t *testing.T) {
+	client := HTTPClient()
+
+	if client.Timeout != clientTimeout {
+		t.Errorf("HTTPClient timeout is %s, want %s", client.Timeout, clientTimeout)
+	}
+
+	if client.Transport == nil {
+		t.Errorf("HTTPClient transport is nil")
+	}
+}
@@ -97,13 +97,40 @@ func (c *ContainerService) ContainerInspect(_ context.Context, ctn string, _ cli
 		},
 	}
 
+	// check if the container should have exited unsuccessfully
+	if strings.Contains(ctn, "exit-failure") {
+		response.Container.State = &container.State{ExitCode: 1}
+	}
+
+	// check if the container should have run out of memory
+	if strings.Contains(ctn, "oom-killed") {
+		response.Container.State = &container.State{ExitCode: 137, OOMKilled: true}
+	}
+
 	return response, nil
 }
 
 // ContainerList is a helper function to simulate
 // a mocked call to list Docker containers.
-func (c *ContainerService) ContainerList(_ context.Context, _ client.ContainerListOptions) (client.ContainerListResult, error) {
-	return client.ContainerListResult{}, nil
+func (c *ContainerService) ContainerList(_ context.Context, opts client.ContainerListOptions) (client.ContainerListResult, error) {
+	// check if the containers are filtered by label
+	if len(opts.Filters["label"]) == 0 {
+		return client.ContainerListResult{}, nil
+	}
+
+	// create response object to return
+	response := client.ContainerListResult{}
+
+	for _, p := range mockPipelines() {
+		response.Items = append(response.Items, container.Summary{
+			ID:      stringid.GenerateRandomID(),
+			Names:   []string{"/step_" + p.id + "_init"},
+			Created: p.created.Unix(),
+			Labels:  p.labels,
+		})
+	}
+
+	return response, nil
 }
 
 // ContainerUpdate is a helper function to simulate
@@ -210,7 +237,7 @@ func (c *ContainerService) ContainerUnpause(_ context.Context, _ string, _ clien
 // ContainerWait is a helper function to simulate
 // a mocked call to wait for a running Docker
 // container to finish.
-func (c *ContainerService) ContainerWait(_ context.Context, ctn string, _ client.ContainerWaitOptions) client.ContainerWaitResult {
+func (c *ContainerService) ContainerWait(ctx context.Context, ctn string, _ client.ContainerWaitOptions) client.ContainerWaitResult {
 	ctnCh := make(chan container.WaitResponse, 1)
 	errCh := make(chan error, 1)
 
@@ -247,6 +274,18 @@ func (c *ContainerService) ContainerWait(_ context.Context, ctn string, _ client
 		return result
 	}
 
+	// check if the container never completes
+	if strings.Contains(ctn, "wait-hang") {
+		// propagate the context error once the caller stops waiting
+		go func() {
+			<-ctx.Done()
+
+			errCh <- ctx.Err()
+		}()
+
+		return result
+	}
+
 	// create goroutine for responding to call
 	go func() {
 		// create response object to return
@@ -2,6 +2,8 @@
 
 package docker
 
+import "time"
+
 // Version represents the supported Docker API version for the mock.
 //
 // The Docker API version is pinned to ensure compatibility between the
@@ -37,3 +39,43 @@ func New() (*mock, error) {
 		Version:             Version,
 	}, nil
 }
+
+// mockPipeline represents a pipeline with
+// resources listed by the Docker mock.
+type mockPipeline struct {
+	id      string
+	created time.Time
+	labels  map[string]string
+}
+
+// mockPipelines is a helper function to return the pipelines
+// with resources listed by the Docker mock.
+//
+// The pipelines are created an hour ago by the worker
+// except for github_octocat_3 which was created just now,
+// github_octocat_4 which was created by another worker and
+// github_octocat_5 which has no label for the worker.
+func mockPipelines() []mockPipeline {
+	now := time.Now()
+
+	pipelines := []mockPipeline{
+		{id: "github_octocat_1", created: now.Add(-time.Hour)},
+		{id: "github_octocat_2", created: now.Add(-time.Hour)},
+		{id: "github_octocat_3", created: now},
+		{id: "github_octocat_4", created: now.Add(-time.Hour)},
+		{id: "github_octocat_5", created: now.Add(-time.Hour)},
+	}
+
+	for i, p := range pipelines {
+		pipelines[i].labels = map[string]string{
+			"vela.pipeline": p.id,
+			"vela.worker":   "worker",
+		}
+	}
+
+	pipelines[3].labels["vela.worker"] = "other-worker"
+
+	delete(pipelines[4].labels, "vela.worker")
+
+	return pipelines
+}
@@ -5,6 +5,8 @@ package docker
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -15,6 +17,7 @@ import (
 	cerrdefs "github.com/containerd/errdefs"
 	"github.com/moby/moby/api/types/image"
 	"github.com/moby/moby/api/types/jsonstream"
+	"github.com/moby/moby/api/types/registry"
 	"github.com/moby/moby/api/types/storage"
 	"github.com/moby/moby/client"
 	"github.com/moby/moby/client/pkg/stringid"
@@ -58,12 +61,40 @@ func (m *mockImagePullResponse) Wait(_ context.Context) error {
 
 // ImagePull is a helper function to simulate
 // a mocked call to pull a Docker image.
-func (i *ImageService) ImagePull(_ context.Context, image string, _ client.ImagePullOptions) (client.ImagePullResponse, error) {
+func (i *ImageService) ImagePull(_ context.Context, image string, options client.ImagePullOptions) (client.ImagePullResponse, error) {
 	// verify an image was provided
 	if len(image) == 0 {
 		return nil, errors.New("no container provided")
 	}
 
+	// check if registry credentials were provided
+	if len(options.RegistryAuth) > 0 {
+		// decode the registry credentials
+		data, err := base64.URLEncoding.DecodeString(options.RegistryAuth)
+		if err != nil {
+			return nil, cerrdefs.ErrInvalidArgument.WithMessage("invalid registry auth encoding")
+		}
+
+		auth := new(registry.AuthConfig)
+
+		err = json.Unmarshal(data, auth)
+		if err != nil {
+			return nil, cerrdefs.ErrInvalidArgument.WithMessage("invalid registry auth")
+		}
+
+		// check if the registry credentials are invalid
+		if strings.Contains(auth.Password, "invalid") {
+			return nil, cerrdefs.ErrUnauthenticated
+		}
+	}
+
+	// check if the image is private and
+	// check if registry credentials were provided
+	if strings.Contains(image, "private") &&
+		len(options.RegistryAuth) == 0 {
+		return nil, cerrdefs.ErrUnauthenticated
+	}
+
 	// check if the image is notfound and
 	// check if the notfound should be ignored
 	if strings.Contains(image, "notfound") &&
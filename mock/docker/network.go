@@ -88,8 +88,27 @@ func (n *NetworkService) NetworkInspect(_ context.Context, networkID string, _ c
 
 // NetworkList is a helper function to simulate
 // a mocked call to list Docker networks.
-func (n *NetworkService) NetworkList(_ context.Context, _ client.NetworkListOptions) (client.NetworkListResult, error) {
-	return client.NetworkListResult{}, nil
+func (n *NetworkService) NetworkList(_ context.Context, opts client.NetworkListOptions) (client.NetworkListResult, error) {
+	// check if the networks are filtered by label
+	if len(opts.Filters["label"]) == 0 {
+		return client.NetworkListResult{}, nil
+	}
+
+	// create response object to return
+	response := client.NetworkListResult{}
+
+	for _, p := range mockPipelines() {
+		response.Items = append(response.Items, network.Summary{
+			Network: network.Network{
+				Name:    p.id,
+				ID:      stringid.GenerateRandomID(),
+				Created: p.created,
+				Labels:  p.labels,
+			},
+		})
+	}
+
+	return response, nil
 }
 
 // NetworkRemove is a helper function to simulate
@@ -91,8 +91,24 @@ func (v *VolumeService) VolumeInspect(_ context.Context, volumeID string, _ clie
 
 // VolumeList is a helper function to simulate
 // a mocked call to list Docker volumes.
-func (v *VolumeService) VolumeList(_ context.Context, _ client.VolumeListOptions) (client.VolumeListResult, error) {
-	return client.VolumeListResult{}, nil
+func (v *VolumeService) VolumeList(_ context.Context, opts client.VolumeListOptions) (client.VolumeListResult, error) {
+	// check if the volumes are filtered by label
+	if len(opts.Filters["label"]) == 0 {
+		return client.VolumeListResult{}, nil
+	}
+
+	// create response object to return
+	response := client.VolumeListResult{}
+
+	for _, p := range mockPipelines() {
+		response.Items = append(response.Items, volume.Volume{
+			Name:      p.id,
+			CreatedAt: p.created.Format(time.RFC3339),
+			Labels:    p.labels,
+		})
+	}
+
+	return response, nil
 }
 
 // VolumeUpdate is a helper function to simulate
@@ -17,6 +17,8 @@ import (
 // GET     /api/v1/executors/:executor
 // GET     /api/v1/executors/:executor/build
 // DELETE  /api/v1/executors/:executor/build/cancel
+// GET     /api/v1/executors/:executor/logs/:step
+// GET     /api/v1/executors/:executor/logs/services/:service
 // GET     /api/v1/executors/:executor/pipeline
 // GET     /api/v1/executors/:executor/repo
 // .
@@ -46,6 +48,11 @@ func ExecutorHandlers(base *gin.RouterGroup) {
 			// https://pkg.go.dev/github.com/go-vela/worker/router#BuildHandlers
 			BuildHandlers(executor)
 
+			// add a collection of endpoints for handling log related requests
+			//
+			// https://pkg.go.dev/github.com/go-vela/worker/router#LogHandlers
+			LogHandlers(executor)
+
 			// add a collection of endpoints for handling pipeline related requests
 			//
 			// https://pkg.go.dev/github.com/go-vela/worker/router#PipelineHandlers
@@ -41,6 +41,18 @@ func TestRouter_ExecutorHandlers(t *testing.T) {
 			Handler:     "github.com/go-vela/worker/api.CancelBuild",
 			HandlerFunc: api.CancelBuild,
 		},
+		{
+			Method:      "GET",
+			Path:        "/executors/:executor/logs/:step",
+			Handler:     "github.com/go-vela/worker/api.StreamStepLogs",
+			HandlerFunc: api.StreamStepLogs,
+		},
+		{
+			Method:      "GET",
+			Path:        "/executors/:executor/logs/services/:service",
+			Handler:     "github.com/go-vela/worker/api.StreamServiceLogs",
+			HandlerFunc: api.StreamServiceLogs,
+		},
 		{
 			Method:      "GET",
 			Path:        "/executors/:executor/pipeline",
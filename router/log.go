@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-vela/worker/api"
+)
+
+// LogHandlers extends the provided base router group
+// by adding a collection of endpoints for handling
+// log related requests.
+//
+// GET  /api/v1/executors/:executor/logs/:step
+// GET  /api/v1/executors/:executor/logs/services/:service
+// .
+func LogHandlers(base *gin.RouterGroup) {
+	// add a collection of endpoints for handling log related requests
+	//
+	// https://pkg.go.dev/github.com/gin-gonic/gin#RouterGroup.Group
+	logs := base.Group("/logs")
+	{
+		// add an endpoint for streaming the logs for a step
+		//
+		// https://pkg.go.dev/github.com/gin-gonic/gin#RouterGroup.GET
+		logs.GET("/:step", api.StreamStepLogs)
+
+		// add an endpoint for streaming the logs for a service
+		//
+		// https://pkg.go.dev/github.com/gin-gonic/gin#RouterGroup.GET
+		logs.GET("/services/:service", api.StreamServiceLogs)
+	}
+}
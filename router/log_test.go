@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package router
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-vela/worker/api"
+)
+
+func TestRouter_LogHandlers(t *testing.T) {
+	// setup types
+	gin.SetMode(gin.TestMode)
+
+	_engine := gin.New()
+
+	want := gin.RoutesInfo{
+		{
+			Method:      "GET",
+			Path:        "/logs/:step",
+			Handler:     "github.com/go-vela/worker/api.StreamStepLogs",
+			HandlerFunc: api.StreamStepLogs,
+		},
+		{
+			Method:      "GET",
+			Path:        "/logs/services/:service",
+			Handler:     "github.com/go-vela/worker/api.StreamServiceLogs",
+			HandlerFunc: api.StreamServiceLogs,
+		},
+	}
+
+	// run test
+	LogHandlers(&_engine.RouterGroup)
+
+	got := _engine.Routes()
+
+	if len(got) != len(want) {
+		t.Errorf("LogHandlers is %v, want %v", got, want)
+	}
+}
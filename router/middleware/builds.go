@@ -0,0 +1,18 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-vela/worker/api"
+)
+
+// Builds is a middleware function that attaches the
+// builds channel to the context of every http.Request.
+func Builds(b chan *api.BuildRequest) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("builds", b)
+		c.Next()
+	}
+}
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-vela/worker/api"
+)
+
+func TestMiddleware_Builds(t *testing.T) {
+	// setup types
+	want := make(chan *api.BuildRequest, 1)
+	got := make(chan *api.BuildRequest, 1)
+
+	// setup context
+	gin.SetMode(gin.TestMode)
+
+	resp := httptest.NewRecorder()
+	context, engine := gin.CreateTestContext(resp)
+	context.Request, _ = http.NewRequestWithContext(t.Context(), http.MethodGet, "/health", nil)
+
+	// setup mock server
+	engine.Use(Builds(want))
+	engine.GET("/health", func(c *gin.Context) {
+		got = c.Value("builds").(chan *api.BuildRequest)
+
+		c.Status(http.StatusOK)
+	})
+
+	// run test
+	engine.ServeHTTP(context.Writer, context.Request)
+
+	if resp.Code != http.StatusOK {
+		t.Errorf("Builds returned %v, want %v", resp.Code, http.StatusOK)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Builds is %v, want %v", got, want)
+	}
+}
@@ -8,11 +8,13 @@ import (
 	"github.com/go-vela/worker/executor"
 )
 
-// Executors is a middleware function that attaches the
-// executors to the context of every http.Request.
-func Executors(e map[int]executor.Engine) gin.HandlerFunc {
+// Executors is a middleware function that attaches a
+// snapshot of the executors to the context of every
+// http.Request, so handlers don't read the executors
+// while they are being written by the worker.
+func Executors(e func() map[int]executor.Engine) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Set("executors", e)
+		c.Set("executors", e())
 		c.Next()
 	}
 }
@@ -26,7 +26,7 @@ func TestMiddleware_Executors(t *testing.T) {
 	context.Request, _ = http.NewRequestWithContext(t.Context(), http.MethodGet, "/health", nil)
 
 	// setup mock server
-	engine.Use(Executors(want))
+	engine.Use(Executors(func() map[int]executor.Engine { return want }))
 	engine.GET("/health", func(c *gin.Context) {
 		got = c.Value("executors").(map[int]executor.Engine)
 
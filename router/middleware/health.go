@@ -0,0 +1,18 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-vela/worker/internal/health"
+)
+
+// Health is a middleware function that attaches the
+// health checker to the context of every http.Request.
+func Health(h *health.Checker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("health", h)
+		c.Next()
+	}
+}
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/go-vela/worker/internal/health"
+)
+
+func TestMiddleware_Health(t *testing.T) {
+	// setup types
+	want := health.New(0)
+	got := new(health.Checker)
+
+	// setup context
+	gin.SetMode(gin.TestMode)
+
+	resp := httptest.NewRecorder()
+	context, engine := gin.CreateTestContext(resp)
+	context.Request, _ = http.NewRequestWithContext(t.Context(), http.MethodGet, "/ready", nil)
+
+	// setup mock server
+	engine.Use(Health(want))
+	engine.GET("/ready", func(c *gin.Context) {
+		got = c.Value("health").(*health.Checker)
+
+		c.Status(http.StatusOK)
+	})
+
+	// run test
+	engine.ServeHTTP(context.Writer, context.Request)
+
+	if resp.Code != http.StatusOK {
+		t.Errorf("Health returned %v, want %v", resp.Code, http.StatusOK)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Health is %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,16 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// BuildLimit is a middleware function that attaches the
+// build limit channel to the context of every http.Request.
+func BuildLimit(l chan int32) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("build-limit", l)
+		c.Next()
+	}
+}
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMiddleware_BuildLimit(t *testing.T) {
+	// setup types
+	want := make(chan int32, 1)
+	got := make(chan int32, 1)
+
+	// setup context
+	gin.SetMode(gin.TestMode)
+
+	resp := httptest.NewRecorder()
+	context, engine := gin.CreateTestContext(resp)
+	context.Request, _ = http.NewRequestWithContext(t.Context(), http.MethodGet, "/health", nil)
+
+	// setup mock server
+	engine.Use(BuildLimit(want))
+	engine.GET("/health", func(c *gin.Context) {
+		got = c.Value("build-limit").(chan int32)
+
+		c.Status(http.StatusOK)
+	})
+
+	// run test
+	engine.ServeHTTP(context.Writer, context.Request)
+
+	if resp.Code != http.StatusOK {
+		t.Errorf("BuildLimit returned %v, want %v", resp.Code, http.StatusOK)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildLimit is %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package perm
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// cacheSize represents the maximum number
+	// of validated tokens held in the cache.
+	cacheSize = 1024
+
+	// cacheTTL represents the maximum time a validated token is held
+	// in the cache, even if the token expires later than that.
+	cacheTTL = 5 * time.Minute
+)
+
+// tokenCache represents the bounded cache of the
+// tokens validated for requests to the worker API.
+type tokenCache struct {
+	mu sync.Mutex
+	// time the validation expires keyed by the hash of the token
+	entries map[string]time.Time
+	size    int
+	ttl     time.Duration
+	// now returns the current time and is replaced for tests
+	now func() time.Time
+}
+
+// newTokenCache returns a cache holding up to size
+// tokens for no longer than the provided duration.
+func newTokenCache(size int, ttl time.Duration) *tokenCache {
+	return &tokenCache{
+		entries: make(map[string]time.Time),
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+	}
+}
+
+// valid returns true if the token was validated
+// and the validation has not expired yet.
+func (tc *tokenCache) valid(token string) bool {
+	key := hash(token)
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	expires, ok := tc.entries[key]
+	if !ok {
+		return false
+	}
+
+	if !tc.now().Before(expires) {
+		delete(tc.entries, key)
+
+		return false
+	}
+
+	return true
+}
+
+// add captures the token as validated until the provided time
+// or the maximum time for the cache, whichever comes first.
+func (tc *tokenCache) add(token string, expires time.Time) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	now := tc.now()
+
+	if limit := now.Add(tc.ttl); expires.IsZero() || expires.After(limit) {
+		expires = limit
+	}
+
+	// skip tokens that already expired
+	if !now.Before(expires) {
+		return
+	}
+
+	key := hash(token)
+
+	if _, ok := tc.entries[key]; !ok && len(tc.entries) >= tc.size {
+		tc.evict(now)
+	}
+
+	tc.entries[key] = expires
+}
+
+// evict is a helper function to remove the expired tokens from
+// the cache or the token expiring soonest if none have expired.
+//
+// The caller must hold the lock for the cache.
+func (tc *tokenCache) evict(now time.Time) {
+	var (
+		soonest string
+		first   time.Time
+	)
+
+	for key, expires := range tc.entries {
+		if !now.Before(expires) {
+			delete(tc.entries, key)
+
+			continue
+		}
+
+		if first.IsZero() || expires.Before(first) {
+			soonest, first = key, expires
+		}
+	}
+
+	if len(tc.entries) >= tc.size {
+		delete(tc.entries, soonest)
+	}
+}
+
+// hash is a helper function to return the key for the token
+// so the tokens themselves are never held in memory.
+func hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// expiration is a helper function to return the time the
+// token expires or the zero time if it doesn't expire.
+func expiration(token string) time.Time {
+	claims := new(jwt.RegisteredClaims)
+
+	// the token is only inspected after it was validated
+	_, _, err := jwt.NewParser().ParseUnverified(token, claims)
+	if err != nil || claims.ExpiresAt == nil {
+		return time.Time{}
+	}
+
+	return claims.ExpiresAt.Time
+}
@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package perm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPerm_tokenCache(t *testing.T) {
+	// setup types
+	now := time.Now()
+
+	// setup tests
+	tests := []struct {
+		name    string
+		expires time.Time
+		elapsed time.Duration
+		want    bool
+	}{
+		{
+			name:    "token without expiration",
+			expires: time.Time{},
+			elapsed: 30 * time.Second,
+			want:    true,
+		},
+		{
+			name:    "token past maximum time",
+			expires: time.Time{},
+			elapsed: 2 * time.Minute,
+			want:    false,
+		},
+		{
+			name:    "token before expiration",
+			expires: now.Add(30 * time.Second),
+			elapsed: 10 * time.Second,
+			want:    true,
+		},
+		{
+			name:    "token past expiration",
+			expires: now.Add(30 * time.Second),
+			elapsed: 30 * time.Second,
+			want:    false,
+		},
+		{
+			name:    "expired token",
+			expires: now.Add(-time.Second),
+			elapsed: 0,
+			want:    false,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clock := now
+
+			tc := newTokenCache(10, time.Minute)
+			tc.now = func() time.Time { return clock }
+
+			tc.add("superSecret", test.expires)
+
+			clock = clock.Add(test.elapsed)
+
+			got := tc.valid("superSecret")
+			if got != test.want {
+				t.Errorf("valid is %v, want %v", got, test.want)
+			}
+
+			if tc.valid("notSecret") {
+				t.Errorf("valid should have returned false for unknown token")
+			}
+		})
+	}
+}
+
+func TestPerm_tokenCache_Evict(t *testing.T) {
+	// setup types
+	now := time.Now()
+
+	tc := newTokenCache(2, time.Hour)
+	tc.now = func() time.Time { return now }
+
+	tc.add("first", now.Add(time.Minute))
+	tc.add("second", now.Add(2*time.Minute))
+	tc.add("third", now.Add(3*time.Minute))
+
+	if len(tc.entries) != 2 {
+		t.Errorf("tokenCache has %d entries, want 2", len(tc.entries))
+	}
+
+	// the token expiring soonest is evicted
+	if tc.valid("first") {
+		t.Errorf("valid should have returned false for evicted token")
+	}
+
+	for _, tkn := range []string{"second", "third"} {
+		if !tc.valid(tkn) {
+			t.Errorf("valid should have returned true for %s", tkn)
+		}
+	}
+
+	// the cache never holds the tokens themselves
+	if _, ok := tc.entries["second"]; ok {
+		t.Errorf("tokenCache should not hold tokens")
+	}
+}
@@ -3,20 +3,34 @@
 package perm
 
 import (
+	"crypto/subtle"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/sirupsen/logrus"
 
 	"github.com/go-vela/sdk-go/vela"
 	api "github.com/go-vela/server/api/types"
+	"github.com/go-vela/server/constants"
+	"github.com/go-vela/worker/internal/metrics"
 	"github.com/go-vela/worker/router/middleware/token"
 )
 
 // MustServer ensures the caller is the vela server.
+//
+// Validated tokens are cached until they expire, for no longer
+// than five minutes, to avoid validating every request with the
+// server. When the key the server signs tokens with is configured,
+// tokens are verified without the server.
 func MustServer() gin.HandlerFunc {
+	// create the cache shared by the requests to the worker API
+	cache := newTokenCache(cacheSize, cacheTTL)
+
 	return func(c *gin.Context) {
 		// retrieve the callers token from the request headers
 		tkn, err := token.Retrieve(c.Request)
@@ -30,17 +44,15 @@ func MustServer() gin.HandlerFunc {
 			return
 		}
 
-		// retrieve the configured server address from the context
-		addr := c.MustGet("server-address").(string)
+		// validate the token with the secret when the worker runs without a server
+		if secret := c.GetString("standalone-secret"); len(secret) > 0 {
+			if subtle.ConstantTimeCompare([]byte(tkn), []byte(secret)) != 1 {
+				msg := "unable to validate token"
 
-		// create a temporary client to validate the incoming request
-		vela, err := vela.NewClient(addr, "vela-worker", nil)
-		if err != nil {
-			msg := fmt.Sprintf("error creating vela client: %s", err)
+				logrus.Error(msg)
 
-			logrus.Error(msg)
-
-			c.AbortWithStatusJSON(http.StatusInternalServerError, api.Error{Message: &msg})
+				c.AbortWithStatusJSON(http.StatusUnauthorized, api.Error{Message: &msg})
+			}
 
 			return
 		}
@@ -56,6 +68,52 @@ func MustServer() gin.HandlerFunc {
 			return
 		}
 
+		// skip validating tokens that were validated recently
+		if cache.valid(tkn) {
+			metrics.TokenCacheHit()
+
+			return
+		}
+
+		metrics.TokenCacheMiss()
+
+		// verify the token locally with the key the server signs tokens with
+		if key := c.GetString("signing-key"); len(key) > 0 {
+			expires, err := verifyToken(tkn, key)
+			if err == nil {
+				cache.add(tkn, expires)
+
+				return
+			}
+
+			// tokens that are not a JWT, such as the
+			// server secret, are validated by the server
+			if !errors.Is(err, jwt.ErrTokenMalformed) {
+				msg := "unable to validate token"
+
+				logrus.Errorf("%s: %v", msg, err)
+
+				c.AbortWithStatusJSON(http.StatusUnauthorized, api.Error{Message: &msg})
+
+				return
+			}
+		}
+
+		// retrieve the configured server address from the context
+		addr := c.MustGet("server-address").(string)
+
+		// create a temporary client to validate the incoming request
+		vela, err := vela.NewClient(addr, "vela-worker", nil)
+		if err != nil {
+			msg := fmt.Sprintf("error creating vela client: %s", err)
+
+			logrus.Error(msg)
+
+			c.AbortWithStatusJSON(http.StatusInternalServerError, api.Error{Message: &msg})
+
+			return
+		}
+
 		// set the token auth provided in the callers request header
 		vela.Authentication.SetTokenAuth(tkn)
 
@@ -81,5 +139,62 @@ func MustServer() gin.HandlerFunc {
 
 			return
 		}
+
+		// capture the token as validated until it expires
+		cache.add(tkn, expiration(tkn))
+	}
+}
+
+// MustClientCert ensures the caller presented a client certificate
+// verified against the configured CA bundle when one is required.
+func MustClientCert() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !c.GetBool("client-cert-required") {
+			return
+		}
+
+		// the TLS handshake only verifies client certificates that are provided
+		if c.Request.TLS == nil || len(c.Request.TLS.VerifiedChains) == 0 {
+			msg := "verified client certificate required"
+
+			logrus.Error(msg)
+
+			c.AbortWithStatusJSON(http.StatusUnauthorized, api.Error{Message: &msg})
+
+			return
+		}
 	}
 }
+
+// serverClaims represents the claims for the tokens
+// minted by the server for requests to the worker.
+type serverClaims struct {
+	TokenType string `json:"token_type"`
+	jwt.RegisteredClaims
+}
+
+// verifyToken is a helper function to verify the token was minted by the
+// server for requests to the worker and return the time it expires.
+func verifyToken(tkn, key string) (time.Time, error) {
+	claims := new(serverClaims)
+
+	// the server signs tokens with HMAC using its private key
+	p := jwt.NewParser(
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	)
+
+	_, err := p.ParseWithClaims(tkn, claims, func(*jwt.Token) (any, error) {
+		return []byte(key), nil
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// make sure the token was minted for requests to the worker
+	if claims.TokenType != constants.ServerWorkerTokenType {
+		return time.Time{}, fmt.Errorf("token type %q is not %s", claims.TokenType, constants.ServerWorkerTokenType)
+	}
+
+	return claims.ExpiresAt.Time, nil
+}
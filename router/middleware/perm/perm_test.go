@@ -3,12 +3,18 @@
 package perm
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/go-vela/server/constants"
 )
 
 func TestPerm_MustServer_ValidateToken200(t *testing.T) {
@@ -338,3 +344,294 @@ func TestPerm_MustServer_NoAuth(t *testing.T) {
 		t.Errorf("MustServer returned %v, want %v", workerResp.Code, http.StatusBadRequest)
 	}
 }
+
+func TestPerm_MustServer_StandaloneSecret(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{
+			name:  "matching secret",
+			token: "superSecret",
+			want:  http.StatusOK,
+		},
+		{
+			name:  "wrong secret",
+			token: "notSecret",
+			want:  http.StatusUnauthorized,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// setup context
+			gin.SetMode(gin.TestMode)
+
+			// setup mock worker router
+			workerResp := httptest.NewRecorder()
+			workerCtx, workerEngine := gin.CreateTestContext(workerResp)
+
+			// fake request made to the worker router
+			workerCtx.Request, _ = http.NewRequestWithContext(t.Context(), http.MethodPost, "/builds", nil)
+			workerCtx.Request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", test.token))
+
+			// no server address is needed when running standalone
+			workerEngine.Use(func(c *gin.Context) {
+				c.Set("server-address", "")
+				c.Set("standalone-secret", "superSecret")
+			})
+
+			// attach perm middleware that we are testing
+			workerEngine.Use(MustServer())
+			workerEngine.POST("/builds", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			// run test
+			workerEngine.ServeHTTP(workerCtx.Writer, workerCtx.Request)
+
+			if workerResp.Code != test.want {
+				t.Errorf("MustServer returned %v, want %v", workerResp.Code, test.want)
+			}
+		})
+	}
+}
+
+func TestPerm_MustServer_Cache(t *testing.T) {
+	// setup types
+	tkn := "superSecret"
+	calls := 0
+
+	// setup context
+	gin.SetMode(gin.TestMode)
+
+	// setup mock server router
+	_, serverEngine := gin.CreateTestContext(httptest.NewRecorder())
+
+	// mocked token validation endpoint used in MustServer
+	serverEngine.GET("/validate-token", func(c *gin.Context) {
+		calls++
+
+		c.Status(http.StatusOK)
+	})
+
+	serverMock := httptest.NewServer(serverEngine)
+	defer serverMock.Close()
+
+	// setup mock worker router
+	_, workerEngine := gin.CreateTestContext(httptest.NewRecorder())
+
+	workerEngine.Use(func(c *gin.Context) { c.Set("server-address", serverMock.URL) })
+
+	// attach perm middleware that we are testing
+	workerEngine.Use(MustServer())
+	workerEngine.GET("/build/cancel", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	// run test
+	for range 3 {
+		workerResp := httptest.NewRecorder()
+
+		req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, "/build/cancel", nil)
+		req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", tkn))
+
+		workerEngine.ServeHTTP(workerResp, req)
+
+		if workerResp.Code != http.StatusOK {
+			t.Errorf("MustServer returned %v, want %v", workerResp.Code, http.StatusOK)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("MustServer validated token with server %d times, want 1", calls)
+	}
+}
+
+func TestPerm_MustServer_SigningKey(t *testing.T) {
+	// setup types
+	key := "superSecretKey"
+
+	// setup tests
+	tests := []struct {
+		name  string
+		token string
+		want  int
+		calls int
+	}{
+		{
+			name:  "server token",
+			token: testToken(t, key, constants.ServerWorkerTokenType, time.Hour),
+			want:  http.StatusOK,
+			calls: 0,
+		},
+		{
+			name:  "expired server token",
+			token: testToken(t, key, constants.ServerWorkerTokenType, -time.Hour),
+			want:  http.StatusUnauthorized,
+			calls: 0,
+		},
+		{
+			name:  "worker token",
+			token: testToken(t, key, constants.WorkerAuthTokenType, time.Hour),
+			want:  http.StatusUnauthorized,
+			calls: 0,
+		},
+		{
+			name:  "wrong key",
+			token: testToken(t, "notSecretKey", constants.ServerWorkerTokenType, time.Hour),
+			want:  http.StatusUnauthorized,
+			calls: 0,
+		},
+		{
+			name:  "server secret",
+			token: "superSecret",
+			want:  http.StatusOK,
+			calls: 1,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			calls := 0
+
+			// setup context
+			gin.SetMode(gin.TestMode)
+
+			// setup mock server router
+			_, serverEngine := gin.CreateTestContext(httptest.NewRecorder())
+
+			// mocked token validation endpoint used in MustServer
+			serverEngine.GET("/validate-token", func(c *gin.Context) {
+				calls++
+
+				c.Status(http.StatusOK)
+			})
+
+			serverMock := httptest.NewServer(serverEngine)
+			defer serverMock.Close()
+
+			// setup mock worker router
+			workerResp := httptest.NewRecorder()
+			workerCtx, workerEngine := gin.CreateTestContext(workerResp)
+
+			// fake request made to the worker router
+			workerCtx.Request, _ = http.NewRequestWithContext(t.Context(), http.MethodGet, "/build/cancel", nil)
+			workerCtx.Request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", test.token))
+
+			workerEngine.Use(func(c *gin.Context) {
+				c.Set("server-address", serverMock.URL)
+				c.Set("signing-key", key)
+			})
+
+			// attach perm middleware that we are testing
+			workerEngine.Use(MustServer())
+			workerEngine.GET("/build/cancel", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			// run test
+			workerEngine.ServeHTTP(workerCtx.Writer, workerCtx.Request)
+
+			if workerResp.Code != test.want {
+				t.Errorf("MustServer returned %v, want %v", workerResp.Code, test.want)
+			}
+
+			if calls != test.calls {
+				t.Errorf("MustServer validated token with server %d times, want %d", calls, test.calls)
+			}
+		})
+	}
+}
+
+// testToken is a test helper function to create a
+// token signed with the key like the server does.
+func testToken(t *testing.T, key, tokenType string, expires time.Duration) string {
+	t.Helper()
+
+	claims := &serverClaims{
+		TokenType: tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "vela-server",
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expires)),
+		},
+	}
+
+	tkn, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(key))
+	if err != nil {
+		t.Fatalf("unable to sign token: %v", err)
+	}
+
+	return tkn
+}
+
+func TestPerm_MustClientCert(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name     string
+		required bool
+		tls      *tls.ConnectionState
+		want     int
+	}{
+		{
+			name:     "not required",
+			required: false,
+			tls:      nil,
+			want:     http.StatusOK,
+		},
+		{
+			name:     "verified certificate",
+			required: true,
+			tls:      &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{new(x509.Certificate)}}},
+			want:     http.StatusOK,
+		},
+		{
+			name:     "no certificate",
+			required: true,
+			tls:      new(tls.ConnectionState),
+			want:     http.StatusUnauthorized,
+		},
+		{
+			name:     "no tls",
+			required: true,
+			tls:      nil,
+			want:     http.StatusUnauthorized,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// setup context
+			gin.SetMode(gin.TestMode)
+
+			// setup mock worker router
+			workerResp := httptest.NewRecorder()
+			workerCtx, workerEngine := gin.CreateTestContext(workerResp)
+
+			// fake request made to the worker router
+			workerCtx.Request, _ = http.NewRequestWithContext(t.Context(), http.MethodGet, "/build/cancel", nil)
+			workerCtx.Request.TLS = test.tls
+
+			workerEngine.Use(func(c *gin.Context) { c.Set("client-cert-required", test.required) })
+
+			// attach perm middleware that we are testing
+			workerEngine.Use(MustClientCert())
+			workerEngine.GET("/build/cancel", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			// run test
+			workerEngine.ServeHTTP(workerCtx.Writer, workerCtx.Request)
+
+			if workerResp.Code != test.want {
+				t.Errorf("MustClientCert returned %v, want %v", workerResp.Code, test.want)
+			}
+		})
+	}
+}
@@ -14,3 +14,33 @@ func ServerAddress(addr string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// StandaloneSecret is a middleware function that attaches the secret
+// for authenticating requests without a server to the context of every
+// http.Request. The secret is empty unless the worker runs standalone.
+func StandaloneSecret(secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("standalone-secret", secret)
+		c.Next()
+	}
+}
+
+// SigningKey is a middleware function that attaches the key used
+// by the server to sign tokens to the context of every http.Request.
+// The key is empty unless it was configured for the worker.
+func SigningKey(key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("signing-key", key)
+		c.Next()
+	}
+}
+
+// ClientCertificate is a middleware function that attaches whether
+// requests to the API require a verified client certificate to the
+// context of every http.Request.
+func ClientCertificate(required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("client-cert-required", required)
+		c.Next()
+	}
+}
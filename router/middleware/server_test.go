@@ -42,3 +42,99 @@ func TestMiddleware_ServerAddress(t *testing.T) {
 		t.Errorf("ServerAddress is %v, want %v", got, want)
 	}
 }
+
+func TestMiddleware_StandaloneSecret(t *testing.T) {
+	// setup types
+	got := ""
+	want := "superSecret"
+
+	// setup context
+	gin.SetMode(gin.TestMode)
+
+	resp := httptest.NewRecorder()
+	context, engine := gin.CreateTestContext(resp)
+	context.Request, _ = http.NewRequestWithContext(t.Context(), http.MethodGet, "/health", nil)
+
+	// setup mock server
+	engine.Use(StandaloneSecret(want))
+	engine.GET("/health", func(c *gin.Context) {
+		got = c.Value("standalone-secret").(string)
+
+		c.Status(http.StatusOK)
+	})
+
+	// run test
+	engine.ServeHTTP(context.Writer, context.Request)
+
+	if resp.Code != http.StatusOK {
+		t.Errorf("StandaloneSecret returned %v, want %v", resp.Code, http.StatusOK)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("StandaloneSecret is %v, want %v", got, want)
+	}
+}
+
+func TestMiddleware_SigningKey(t *testing.T) {
+	// setup types
+	got := ""
+	want := "superSecret"
+
+	// setup context
+	gin.SetMode(gin.TestMode)
+
+	resp := httptest.NewRecorder()
+	context, engine := gin.CreateTestContext(resp)
+	context.Request, _ = http.NewRequestWithContext(t.Context(), http.MethodGet, "/health", nil)
+
+	// setup mock server
+	engine.Use(SigningKey(want))
+	engine.GET("/health", func(c *gin.Context) {
+		got = c.Value("signing-key").(string)
+
+		c.Status(http.StatusOK)
+	})
+
+	// run test
+	engine.ServeHTTP(context.Writer, context.Request)
+
+	if resp.Code != http.StatusOK {
+		t.Errorf("SigningKey returned %v, want %v", resp.Code, http.StatusOK)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SigningKey is %v, want %v", got, want)
+	}
+}
+
+func TestMiddleware_ClientCertificate(t *testing.T) {
+	// setup types
+	got := false
+	want := true
+
+	// setup context
+	gin.SetMode(gin.TestMode)
+
+	resp := httptest.NewRecorder()
+	context, engine := gin.CreateTestContext(resp)
+	context.Request, _ = http.NewRequestWithContext(t.Context(), http.MethodGet, "/health", nil)
+
+	// setup mock server
+	engine.Use(ClientCertificate(want))
+	engine.GET("/health", func(c *gin.Context) {
+		got = c.Value("client-cert-required").(bool)
+
+		c.Status(http.StatusOK)
+	})
+
+	// run test
+	engine.ServeHTTP(context.Writer, context.Request)
+
+	if resp.Code != http.StatusOK {
+		t.Errorf("ClientCertificate returned %v, want %v", resp.Code, http.StatusOK)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ClientCertificate is %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,16 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// Shutdown is a middleware function that attaches the
+// shutdown channel to the context of every http.Request.
+func Shutdown(s chan struct{}) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("shutdown", s)
+		c.Next()
+	}
+}
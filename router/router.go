@@ -80,6 +80,16 @@ func Load(options ...gin.HandlerFunc) *gin.Engine {
 	// https://pkg.go.dev/github.com/gin-gonic/gin#RouterGroup.GET
 	r.GET("/health", api.Health)
 
+	// add an endpoint for reporting the liveness of the worker
+	//
+	// https://pkg.go.dev/github.com/gin-gonic/gin#RouterGroup.GET
+	r.GET("/live", api.Live)
+
+	// add an endpoint for reporting the readiness of the worker
+	//
+	// https://pkg.go.dev/github.com/gin-gonic/gin#RouterGroup.GET
+	r.GET("/ready", api.Ready)
+
 	// add an endpoint for reporting metrics for the worker
 	//
 	// https://pkg.go.dev/github.com/gin-gonic/gin#RouterGroup.GET
@@ -93,13 +103,23 @@ func Load(options ...gin.HandlerFunc) *gin.Engine {
 	// add a collection of endpoints for handling API related requests
 	//
 	// https://pkg.go.dev/github.com/gin-gonic/gin#RouterGroup.Group
-	baseAPI := r.Group(base, perm.MustServer())
+	baseAPI := r.Group(base, perm.MustClientCert(), perm.MustServer())
 	{
 		// add an endpoint for shutting down the worker
 		//
 		// https://pkg.go.dev/github.com/gin-gonic/gin#RouterGroup.POST
 		baseAPI.POST("/shutdown", api.Shutdown)
 
+		// add an endpoint for updating the build limit of the worker
+		//
+		// https://pkg.go.dev/github.com/gin-gonic/gin#RouterGroup.PUT
+		baseAPI.PUT("/build-limit", api.UpdateBuildLimit)
+
+		// add an endpoint for running builds without a server
+		//
+		// https://pkg.go.dev/github.com/gin-gonic/gin#RouterGroup.POST
+		baseAPI.POST("/builds", api.RunBuild)
+
 		// add a collection of endpoints for handling executor related requests
 		//
 		// https://pkg.go.dev/github.com/go-vela/worker/router#ExecutorHandlers
@@ -21,6 +21,18 @@ func TestRouter_Load(t *testing.T) {
 			Handler:     "github.com/go-vela/worker/api.Health",
 			HandlerFunc: api.Health,
 		},
+		{
+			Method:      "GET",
+			Path:        "/live",
+			Handler:     "github.com/go-vela/worker/api.Live",
+			HandlerFunc: api.Live,
+		},
+		{
+			Method:      "GET",
+			Path:        "/ready",
+			Handler:     "github.com/go-vela/worker/api.Ready",
+			HandlerFunc: api.Ready,
+		},
 		{
 			Method:      "GET",
 			Path:        "/metrics",
@@ -39,6 +51,18 @@ func TestRouter_Load(t *testing.T) {
 			Handler:     "github.com/go-vela/worker/api.Shutdown",
 			HandlerFunc: api.Shutdown,
 		},
+		{
+			Method:      "POST",
+			Path:        "/api/v1/builds",
+			Handler:     "github.com/go-vela/worker/api.RunBuild",
+			HandlerFunc: api.RunBuild,
+		},
+		{
+			Method:      "PUT",
+			Path:        "/api/v1/build-limit",
+			Handler:     "github.com/go-vela/worker/api.UpdateBuildLimit",
+			HandlerFunc: api.UpdateBuildLimit,
+		},
 		{
 			Method:      "POST",
 			Path:        "/api/v1/register",
@@ -69,6 +93,18 @@ func TestRouter_Load(t *testing.T) {
 			Handler:     "github.com/go-vela/worker/api.CancelBuild",
 			HandlerFunc: api.CancelBuild,
 		},
+		{
+			Method:      "GET",
+			Path:        "/api/v1/executors/:executor/logs/:step",
+			Handler:     "github.com/go-vela/worker/api.StreamStepLogs",
+			HandlerFunc: api.StreamStepLogs,
+		},
+		{
+			Method:      "GET",
+			Path:        "/api/v1/executors/:executor/logs/services/:service",
+			Handler:     "github.com/go-vela/worker/api.StreamServiceLogs",
+			HandlerFunc: api.StreamServiceLogs,
+		},
 		{
 			Method:      "GET",
 			Path:        "/api/v1/executors/:executor/pipeline",
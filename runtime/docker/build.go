@@ -4,6 +4,9 @@ package docker
 
 import (
 	"context"
+	"errors"
+
+	"github.com/containerd/errdefs"
 
 	"github.com/go-vela/server/compiler/types/pipeline"
 )
@@ -47,3 +50,50 @@ func (c *client) RemoveBuild(_ context.Context, b *pipeline.Build) error {
 
 	return nil
 }
+
+// ReconcileBuild deletes the containers, network and volume left
+// behind for the pipeline build by a previous worker process.
+func (c *client) ReconcileBuild(ctx context.Context, b *pipeline.Build) error {
+	c.Logger.Tracef("reconciling build %s", b.ID)
+
+	var errs []error
+
+	// capture all containers for the pipeline
+	containers := append(pipeline.ContainerSlice{}, b.Services...)
+	containers = append(containers, b.Steps...)
+
+	for _, stage := range b.Stages {
+		containers = append(containers, stage.Steps...)
+	}
+
+	for _, secret := range b.Secrets {
+		if !secret.Origin.Empty() {
+			containers = append(containers, secret.Origin)
+		}
+	}
+
+	for _, ctn := range containers {
+		c.Logger.Debugf("removing leftover container %s", ctn.ID)
+		// remove the runtime container
+		err := c.RemoveContainer(ctx, ctn)
+		if err != nil && !errdefs.IsNotFound(err) {
+			errs = append(errs, err)
+		}
+	}
+
+	c.Logger.Debugf("removing leftover network %s", b.ID)
+	// remove the runtime network for the pipeline
+	err := c.RemoveNetwork(ctx, b)
+	if err != nil && !errdefs.IsNotFound(err) {
+		errs = append(errs, err)
+	}
+
+	c.Logger.Debugf("removing leftover volume %s", b.ID)
+	// remove the runtime volume for the pipeline
+	err = c.RemoveVolume(ctx, b)
+	if err != nil && !errdefs.IsNotFound(err) {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
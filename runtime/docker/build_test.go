@@ -201,3 +201,63 @@ func TestDocker_RemoveBuild(t *testing.T) {
 		})
 	}
 }
+
+func TestDocker_ReconcileBuild(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name     string
+		failure  bool
+		pipeline *pipeline.Build
+	}{
+		{
+			name:     "steps",
+			failure:  false,
+			pipeline: _pipeline,
+		},
+		{
+			name:    "containers already removed",
+			failure: false,
+			pipeline: &pipeline.Build{
+				ID: "github_octocat_1",
+				Steps: pipeline.ContainerSlice{
+					{ID: "step_github_octocat_1_notfound"},
+					{ID: "outputs_github_octocat_1_not-found"},
+				},
+			},
+		},
+		{
+			name:    "container without ID",
+			failure: true,
+			pipeline: &pipeline.Build{
+				ID: "github_octocat_1",
+				Steps: pipeline.ContainerSlice{
+					{ID: ""},
+				},
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_engine, err := NewMock()
+			if err != nil {
+				t.Errorf("unable to create runtime engine: %v", err)
+			}
+
+			err = _engine.ReconcileBuild(context.Background(), test.pipeline)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("ReconcileBuild should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("ReconcileBuild returned err: %v", err)
+			}
+		})
+	}
+}
@@ -18,7 +18,9 @@ import (
 
 	"github.com/go-vela/server/compiler/types/pipeline"
 	"github.com/go-vela/server/constants"
+	"github.com/go-vela/worker/internal/exit"
 	"github.com/go-vela/worker/internal/image"
+	"github.com/go-vela/worker/internal/resource"
 )
 
 // InspectContainer inspects the pipeline container.
@@ -42,6 +44,38 @@ func (c *client) InspectContainer(ctx context.Context, ctn *pipeline.Container)
 	return nil
 }
 
+// ExitReasonContainer captures why the pipeline container exited
+// when it was terminated by the infrastructure.
+func (c *client) ExitReasonContainer(ctx context.Context, ctn *pipeline.Container) (*exit.Reason, error) {
+	c.Logger.Tracef("capturing exit reason for container %s", ctn.ID)
+
+	// send API call to inspect the container
+	//
+	// https://pkg.go.dev/github.com/docker/docker/client#Client.ContainerInspect
+	result, err := c.Docker.ContainerInspect(ctx, ctn.ID, mobyClient.ContainerInspectOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// https://pkg.go.dev/github.com/docker/docker/api/types#ContainerState
+	state := result.Container.State
+	if state == nil {
+		return nil, nil
+	}
+
+	// check if the container was killed after running out of memory
+	if state.OOMKilled {
+		return &exit.Reason{Reason: exit.ReasonOOMKilled, Message: state.Error}, nil
+	}
+
+	// check if the runtime failed to run the container
+	if len(state.Error) > 0 {
+		return &exit.Reason{Reason: exit.ReasonRuntimeError, Message: state.Error}, nil
+	}
+
+	return nil, nil
+}
+
 // RemoveContainer deletes (kill, remove) the pipeline container.
 func (c *client) RemoveContainer(ctx context.Context, ctn *pipeline.Container) error {
 	c.Logger.Tracef("removing container %s", ctn.ID)
@@ -56,12 +90,10 @@ func (c *client) RemoveContainer(ctx context.Context, ctn *pipeline.Container) e
 
 	container := result.Container
 
-	// if the container is paused, restarting or running
-	//
 	// https://pkg.go.dev/github.com/docker/docker/api/types#ContainerState
-	if container.State.Paused ||
-		container.State.Restarting ||
-		container.State.Running {
+	switch {
+	// paused containers can't handle the stop signal so they are killed
+	case container.State.Paused:
 		// send API call to kill the container
 		//
 		// https://pkg.go.dev/github.com/docker/docker/client#Client.ContainerKill
@@ -71,6 +103,24 @@ func (c *client) RemoveContainer(ctx context.Context, ctn *pipeline.Container) e
 		if err != nil {
 			return err
 		}
+	case container.State.Restarting || container.State.Running:
+		// capture the time to wait for the container to stop
+		timeout := int(c.config.StopGracePeriod.Seconds())
+
+		c.Logger.Tracef("stopping container %s with %ds grace period", ctn.ID, timeout)
+
+		// send API call to stop the container
+		//
+		// the container receives the stop signal for the image (SIGTERM by default)
+		// and is killed if it is still running once the grace period expires
+		//
+		// https://pkg.go.dev/github.com/moby/moby/client#Client.ContainerStop
+		_, err := c.Docker.ContainerStop(ctx, ctn.ID, mobyClient.ContainerStopOptions{
+			Timeout: &timeout,
+		})
+		if err != nil {
+			return err
+		}
 	}
 
 	// create options for removing container
@@ -97,10 +147,22 @@ func (c *client) RemoveContainer(ctx context.Context, ctn *pipeline.Container) e
 func (c *client) RunContainer(ctx context.Context, ctn *pipeline.Container, b *pipeline.Build) error {
 	c.Logger.Tracef("running container %s", ctn.ID)
 
+	// resolve the resource limits for the container
+	//
+	// https://pkg.go.dev/github.com/go-vela/worker/internal/resource#Resolve
+	limits, err := resource.Resolve(ctn.Environment, c.config.DefaultLimits, c.config.MaxLimits)
+	if err != nil {
+		return err
+	}
+
+	c.Logger.Tracef("using resource limits %s for container %s", limits, ctn.ID)
+
 	// allocate new container config from pipeline container
 	containerConf := ctnConfig(ctn)
-	// allocate new host config with volume data
-	hostConf := hostConfig(c.Logger, b.ID, ctn.Ulimits, c.config.Volumes, c.config.DropCapabilities)
+	// add the labels for the pipeline and step to the container config
+	containerConf.Labels = c.ctnLabels(ctn, b)
+	// allocate new host config with volume and resource data
+	hostConf := hostConfig(c.Logger, b.ID, ctn.Ulimits, limits, c.config.Volumes, c.config.DropCapabilities)
 	// allocate new network config with container name
 	networkConf := netConfig(b.ID, ctn.Name)
 
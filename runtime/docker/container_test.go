@@ -4,9 +4,11 @@ package docker
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
 	"github.com/go-vela/server/compiler/types/pipeline"
+	"github.com/go-vela/worker/internal/exit"
 )
 
 func TestDocker_InspectContainer(t *testing.T) {
@@ -54,6 +56,63 @@ func TestDocker_InspectContainer(t *testing.T) {
 	}
 }
 
+func TestDocker_ExitReasonContainer(t *testing.T) {
+	// setup Docker
+	_engine, err := NewMock()
+	if err != nil {
+		t.Errorf("unable to create runtime engine: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		name      string
+		failure   bool
+		container *pipeline.Container
+		want      *exit.Reason
+	}{
+		{
+			name:      "build container",
+			failure:   false,
+			container: _container,
+			want:      nil,
+		},
+		{
+			name:      "oom killed build container",
+			failure:   false,
+			container: &pipeline.Container{ID: "step_github_octocat_1_oom-killed", Name: "oom"},
+			want:      &exit.Reason{Reason: exit.ReasonOOMKilled},
+		},
+		{
+			name:      "empty build container",
+			failure:   true,
+			container: new(pipeline.Container),
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := _engine.ExitReasonContainer(context.Background(), test.container)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("ExitReasonContainer should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("ExitReasonContainer returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ExitReasonContainer is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
 func TestDocker_RemoveContainer(t *testing.T) {
 	// setup Docker
 	_engine, err := NewMock()
@@ -149,6 +208,38 @@ func TestDocker_RunContainer(t *testing.T) {
 				Pull:        "always",
 			},
 		},
+		{
+			name:     "steps-echo step with resource limits",
+			failure:  false,
+			pipeline: _pipeline,
+			container: &pipeline.Container{
+				ID:          "step_github_octocat_1_echo",
+				Commands:    []string{"echo", "hello"},
+				Directory:   "/vela/src/github.com/octocat/helloworld",
+				Environment: map[string]string{"VELA_CPU_LIMIT": "2", "VELA_MEMORY_LIMIT": "1g"},
+				Entrypoint:  []string{"/bin/sh", "-c"},
+				Image:       "alpine:latest",
+				Name:        "echo",
+				Number:      2,
+				Pull:        "always",
+			},
+		},
+		{
+			name:     "steps-echo step with invalid resource limits",
+			failure:  true,
+			pipeline: _pipeline,
+			container: &pipeline.Container{
+				ID:          "step_github_octocat_1_echo",
+				Commands:    []string{"echo", "hello"},
+				Directory:   "/vela/src/github.com/octocat/helloworld",
+				Environment: map[string]string{"VELA_MEMORY_LIMIT": "lots"},
+				Entrypoint:  []string{"/bin/sh", "-c"},
+				Image:       "alpine:latest",
+				Name:        "echo",
+				Number:      2,
+				Pull:        "always",
+			},
+		},
 		{
 			name:     "steps-privileged",
 			failure:  false,
@@ -3,9 +3,13 @@
 package docker
 
 import (
+	"time"
+
 	docker "github.com/moby/moby/client"
 	"github.com/sirupsen/logrus"
 
+	"github.com/go-vela/worker/internal/registry"
+	"github.com/go-vela/worker/internal/resource"
 	mock "github.com/go-vela/worker/mock/docker"
 )
 
@@ -36,6 +40,16 @@ type config struct {
 	Volumes []string
 	// specifies a list of kernel capabilities to drop for each Docker container
 	DropCapabilities []string
+	// specifies the credentials to use for pulling images from registries
+	RegistryAuth *registry.Credentials
+	// specifies the default resource limits for each Docker container
+	DefaultLimits resource.Limits
+	// specifies the maximum resource limits for each Docker container
+	MaxLimits resource.Limits
+	// specifies the labels identifying the build and worker for each Docker resource
+	Labels map[string]string
+	// specifies the time to wait for each Docker container to stop before it is killed
+	StopGracePeriod time.Duration
 }
 
 type client struct {
@@ -75,10 +89,13 @@ func New(opts ...ClientOpt) (*client, error) {
 		}
 	}
 
+	// check if a shared Docker client was provided
+	if c.Docker != nil {
+		return c, nil
+	}
+
 	// create new Docker client from environment
-	//
-	// https://pkg.go.dev/github.com/docker/docker/client#NewClientWithOpts
-	_docker, err := docker.New(docker.FromEnv, docker.WithAPIVersionFromEnv())
+	_docker, err := NewClient()
 	if err != nil {
 		return nil, err
 	}
@@ -89,6 +106,14 @@ func New(opts ...ClientOpt) (*client, error) {
 	return c, nil
 }
 
+// NewClient returns a Docker API client created from the environment.
+//
+// The client is safe to share across the runtime clients for builds.
+func NewClient() (docker.APIClient, error) {
+	// https://pkg.go.dev/github.com/docker/docker/client#NewClientWithOpts
+	return docker.New(docker.FromEnv, docker.WithAPIVersionFromEnv())
+}
+
 // NewMock returns an Engine implementation that
 // integrates with a mock Docker runtime.
 //
@@ -2,9 +2,27 @@
 
 package docker
 
-import "github.com/go-vela/server/constants"
+import (
+	"context"
+
+	mobyClient "github.com/moby/moby/client"
+
+	"github.com/go-vela/server/constants"
+)
 
 // Driver outputs the configured runtime driver.
 func (c *client) Driver() string {
 	return constants.DriverDocker
 }
+
+// Ping verifies the Docker daemon is reachable.
+func (c *client) Ping(ctx context.Context) error {
+	c.Logger.Trace("pinging docker daemon")
+
+	// send API call to ping the Docker daemon
+	//
+	// https://pkg.go.dev/github.com/moby/moby/client#Client.Ping
+	_, err := c.Docker.Ping(ctx, mobyClient.PingOptions{})
+
+	return err
+}
@@ -9,12 +9,15 @@ import (
 	"os"
 	"strings"
 
+	"github.com/containerd/errdefs"
 	mobyClient "github.com/moby/moby/client"
 	"github.com/sirupsen/logrus"
 
 	"github.com/go-vela/server/compiler/types/pipeline"
 	"github.com/go-vela/server/constants"
 	"github.com/go-vela/worker/internal/image"
+	"github.com/go-vela/worker/internal/infra"
+	"github.com/go-vela/worker/internal/registry"
 )
 
 // CreateImage creates the pipeline container image.
@@ -29,27 +32,59 @@ func (c *client) CreateImage(ctx context.Context, ctn *pipeline.Container) error
 		return err
 	}
 
+	// capture the credentials for the registry the image is pulled from
+	//
+	// https://pkg.go.dev/github.com/go-vela/worker/internal/registry#Credentials.Lookup
+	auth, err := c.config.RegistryAuth.Lookup(ctx, _image)
+	if err != nil {
+		return err
+	}
+
+	// encode the credentials for the image pull
+	//
+	// https://pkg.go.dev/github.com/go-vela/worker/internal/registry#Encode
+	registryAuth, err := registry.Encode(auth)
+	if err != nil {
+		return err
+	}
+
 	// send API call to pull the image for the container
 	//
 	// https://pkg.go.dev/github.com/docker/docker/client#Client.ImagePull
-	reader, err := c.Docker.ImagePull(ctx, _image, mobyClient.ImagePullOptions{})
+	reader, err := c.Docker.ImagePull(ctx, _image, mobyClient.ImagePullOptions{
+		RegistryAuth: registryAuth,
+	})
 	if err != nil {
-		return err
+		// a missing image or credentials rejected by the registry
+		// are caused by the pipeline rather than the infrastructure
+		//
+		// https://pkg.go.dev/github.com/containerd/errdefs
+		if errdefs.IsNotFound(err) ||
+			errdefs.IsUnauthorized(err) ||
+			errdefs.IsPermissionDenied(err) ||
+			errdefs.IsInvalidArgument(err) {
+			return err
+		}
+
+		return infra.Wrap(err)
 	}
 	defer reader.Close()
 
 	// check if logrus is set up with trace level
+	//
+	// the output is only interrupted if the pull fails within the daemon
+	// or the connection to the daemon is lost, so mark it as infrastructure
 	if logrus.GetLevel() == logrus.TraceLevel {
 		// copy output from image pull to standard output
 		_, err = io.Copy(os.Stdout, reader)
 		if err != nil {
-			return err
+			return infra.Wrap(err)
 		}
 	} else {
 		// discard output from image pull
 		_, err = io.Copy(io.Discard, reader)
 		if err != nil {
-			return err
+			return infra.Wrap(err)
 		}
 	}
 
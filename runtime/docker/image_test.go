@@ -4,11 +4,92 @@ package docker
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/go-vela/server/compiler/types/pipeline"
 )
 
+func TestDocker_CreateImage(t *testing.T) {
+	// setup types
+	dir := t.TempDir()
+
+	secrets := filepath.Join(dir, "secrets.yml")
+
+	err := os.WriteFile(secrets, []byte(`
+registry.example.com:
+  username: octocat
+  password: superSecretPassword
+invalid.example.com:
+  username: octocat
+  password: invalidPassword
+`), 0o600)
+	if err != nil {
+		t.Errorf("unable to write registry credentials file: %v", err)
+	}
+
+	// isolate the tests from any Docker config on the host
+	t.Setenv("DOCKER_CONFIG", dir)
+
+	_engine, err := NewMock(WithRegistryAuth("", secrets))
+	if err != nil {
+		t.Errorf("unable to create runtime engine: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		name      string
+		failure   bool
+		container *pipeline.Container
+	}{
+		{
+			name:      "public image",
+			failure:   false,
+			container: _container,
+		},
+		{
+			name:      "private image with credentials",
+			failure:   false,
+			container: &pipeline.Container{ID: "step_github_octocat_1_private", Image: "registry.example.com/octocat/private:latest"},
+		},
+		{
+			name:      "private image without credentials",
+			failure:   true,
+			container: &pipeline.Container{ID: "step_github_octocat_1_private", Image: "ghcr.io/octocat/private:latest"},
+		},
+		{
+			name:      "private image with invalid credentials",
+			failure:   true,
+			container: &pipeline.Container{ID: "step_github_octocat_1_private", Image: "invalid.example.com/octocat/private:latest"},
+		},
+		{
+			name:      "image notfound",
+			failure:   true,
+			container: &pipeline.Container{ID: "step_github_octocat_1_clone", Image: "target/vela-git:notfound"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err = _engine.CreateImage(context.Background(), test.container)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("CreateImage should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("CreateImage returned err: %v", err)
+			}
+		})
+	}
+}
+
 func TestDocker_InspectImage(t *testing.T) {
 	// setup types
 	_engine, err := NewMock()
@@ -0,0 +1,232 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	mobyClient "github.com/moby/moby/client"
+
+	"github.com/go-vela/server/constants"
+	"github.com/go-vela/worker/internal/metrics"
+)
+
+// Janitor represents a background process that removes the
+// Docker resources left behind by pipelines that were aborted.
+type Janitor struct {
+	client *client
+	// active returns the IDs for the pipelines running on the worker
+	active func() []string
+	// interval is the time to wait between removing resources
+	interval time.Duration
+	// maxAge is the minimum age of resources before they are removed
+	maxAge time.Duration
+}
+
+// NewJanitor returns a Janitor that removes the Docker resources labeled
+// for the worker and a pipeline not returned by active once they are
+// older than maxAge.
+func NewJanitor(c *client, active func() []string, interval, maxAge time.Duration) (*Janitor, error) {
+	if c == nil {
+		return nil, fmt.Errorf("no docker runtime client provided")
+	}
+
+	if active == nil {
+		return nil, fmt.Errorf("no active pipelines provided")
+	}
+
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid janitor interval provided: %s", interval)
+	}
+
+	if maxAge <= 0 {
+		return nil, fmt.Errorf("invalid janitor max age provided: %s", maxAge)
+	}
+
+	// resources are only removed if they are labeled for the worker
+	// since other workers may be sharing the Docker host
+	if len(c.config.Labels[LabelWorker]) == 0 {
+		return nil, fmt.Errorf("no worker hostname provided for janitor")
+	}
+
+	return &Janitor{
+		client:   c,
+		active:   active,
+		interval: interval,
+		maxAge:   maxAge,
+	}, nil
+}
+
+// Run removes the orphaned Docker resources on
+// the configured interval until the context is done.
+func (j *Janitor) Run(ctx context.Context) {
+	j.client.Logger.Infof("removing orphaned docker resources older than %s every %s", j.maxAge, j.interval)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			removed, err := j.Clean(ctx)
+			if err != nil {
+				j.client.Logger.Errorf("unable to remove orphaned docker resources: %v", err)
+			}
+
+			if removed > 0 {
+				j.client.Logger.Infof("removed %d orphaned docker resources", removed)
+			}
+		}
+	}
+}
+
+// Clean removes the containers, networks and volumes labeled for the worker
+// and a pipeline that is not running on it and returns the number removed.
+func (j *Janitor) Clean(ctx context.Context) (int, error) {
+	j.client.Logger.Trace("removing orphaned docker resources")
+
+	// capture the pipelines running on the worker
+	active := make(map[string]bool)
+
+	for _, id := range j.active() {
+		active[id] = true
+	}
+
+	// only list the resources created for a pipeline by the worker
+	//
+	// https://pkg.go.dev/github.com/moby/moby/client#Filters
+	filters := make(mobyClient.Filters).Add("label",
+		LabelPipeline,
+		fmt.Sprintf("%s=%s", LabelWorker, j.client.config.Labels[LabelWorker]),
+	)
+
+	var (
+		removed int
+		errs    []error
+	)
+
+	// remove the containers first since they hold on to the networks and volumes
+	//
+	// https://pkg.go.dev/github.com/moby/moby/client#Client.ContainerList
+	containers, err := j.client.Docker.ContainerList(ctx, mobyClient.ContainerListOptions{
+		All:     true,
+		Filters: filters,
+	})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("unable to list containers: %w", err))
+	}
+
+	for _, ctn := range containers.Items {
+		if !j.stale(active, ctn.Labels, time.Unix(ctn.Created, 0)) {
+			continue
+		}
+
+		name := ctn.ID
+		if len(ctn.Names) > 0 {
+			name = strings.TrimPrefix(ctn.Names[0], "/")
+		}
+
+		// https://pkg.go.dev/github.com/moby/moby/client#Client.ContainerRemove
+		_, err := j.client.Docker.ContainerRemove(ctx, ctn.ID, mobyClient.ContainerRemoveOptions{
+			Force:         true,
+			RemoveVolumes: true,
+		})
+
+		removed, errs = j.record("container", name, ctn.Labels[LabelPipeline], err, removed, errs)
+	}
+
+	// https://pkg.go.dev/github.com/moby/moby/client#Client.NetworkList
+	networks, err := j.client.Docker.NetworkList(ctx, mobyClient.NetworkListOptions{
+		Filters: filters,
+	})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("unable to list networks: %w", err))
+	}
+
+	for _, network := range networks.Items {
+		if !j.stale(active, network.Labels, network.Created) {
+			continue
+		}
+
+		// https://pkg.go.dev/github.com/moby/moby/client#Client.NetworkRemove
+		_, err := j.client.Docker.NetworkRemove(ctx, network.ID, mobyClient.NetworkRemoveOptions{})
+
+		removed, errs = j.record("network", network.Name, network.Labels[LabelPipeline], err, removed, errs)
+	}
+
+	// https://pkg.go.dev/github.com/moby/moby/client#Client.VolumeList
+	volumes, err := j.client.Docker.VolumeList(ctx, mobyClient.VolumeListOptions{
+		Filters: filters,
+	})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("unable to list volumes: %w", err))
+	}
+
+	for _, volume := range volumes.Items {
+		created, err := time.Parse(time.RFC3339, volume.CreatedAt)
+		if err != nil {
+			j.client.Logger.Warnf("skipping volume %s with invalid creation time %q", volume.Name, volume.CreatedAt)
+
+			continue
+		}
+
+		if !j.stale(active, volume.Labels, created) {
+			continue
+		}
+
+		// https://pkg.go.dev/github.com/moby/moby/client#Client.VolumeRemove
+		_, err = j.client.Docker.VolumeRemove(ctx, volume.Name, mobyClient.VolumeRemoveOptions{
+			Force: true,
+		})
+
+		removed, errs = j.record("volume", volume.Name, volume.Labels[LabelPipeline], err, removed, errs)
+	}
+
+	return removed, errors.Join(errs...)
+}
+
+// stale is a helper function to determine if a resource was created by the
+// worker for a pipeline that is not running on it and is older than the max age.
+func (j *Janitor) stale(active map[string]bool, labels map[string]string, created time.Time) bool {
+	id := labels[LabelPipeline]
+
+	if len(id) == 0 || active[id] {
+		return false
+	}
+
+	// skip resources created by another worker sharing the Docker
+	// host, or without the label for the worker that created them
+	if labels[LabelWorker] != j.client.config.Labels[LabelWorker] {
+		return false
+	}
+
+	return time.Since(created) >= j.maxAge
+}
+
+// record is a helper function to log and capture metrics
+// for the result of removing an orphaned resource.
+func (j *Janitor) record(resource, name, pipeline string, err error, removed int, errs []error) (int, []error) {
+	// ignore resources already removed
+	if cerrdefs.IsNotFound(err) {
+		return removed, errs
+	}
+
+	if err != nil {
+		metrics.JanitorError(constants.DriverDocker, resource)
+
+		return removed, append(errs, fmt.Errorf("unable to remove %s %s: %w", resource, name, err))
+	}
+
+	j.client.Logger.Infof("removed orphaned %s %s for pipeline %s", resource, name, pipeline)
+
+	metrics.JanitorRemoved(constants.DriverDocker, resource)
+
+	return removed + 1, errs
+}
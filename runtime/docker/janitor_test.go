@@ -0,0 +1,166 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package docker
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDocker_NewJanitor(t *testing.T) {
+	// setup types
+	_engine, err := NewMock(WithBuildLabels("", "", 0, "worker"))
+	if err != nil {
+		t.Errorf("unable to create runtime engine: %v", err)
+	}
+
+	_noHostname, err := NewMock()
+	if err != nil {
+		t.Errorf("unable to create runtime engine: %v", err)
+	}
+
+	active := func() []string { return nil }
+
+	// setup tests
+	tests := []struct {
+		name     string
+		failure  bool
+		client   *client
+		active   func() []string
+		interval time.Duration
+		maxAge   time.Duration
+	}{
+		{
+			name:     "janitor",
+			failure:  false,
+			client:   _engine,
+			active:   active,
+			interval: time.Minute,
+			maxAge:   time.Hour,
+		},
+		{
+			name:     "nil client",
+			failure:  true,
+			client:   nil,
+			active:   active,
+			interval: time.Minute,
+			maxAge:   time.Hour,
+		},
+		{
+			name:     "nil active",
+			failure:  true,
+			client:   _engine,
+			active:   nil,
+			interval: time.Minute,
+			maxAge:   time.Hour,
+		},
+		{
+			name:     "zero interval",
+			failure:  true,
+			client:   _engine,
+			active:   active,
+			interval: 0,
+			maxAge:   time.Hour,
+		},
+		{
+			name:     "zero max age",
+			failure:  true,
+			client:   _engine,
+			active:   active,
+			interval: time.Minute,
+			maxAge:   0,
+		},
+		{
+			name:     "no hostname",
+			failure:  true,
+			client:   _noHostname,
+			active:   active,
+			interval: time.Minute,
+			maxAge:   time.Hour,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewJanitor(test.client, test.active, test.interval, test.maxAge)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("NewJanitor should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("NewJanitor returned err: %v", err)
+			}
+		})
+	}
+}
+
+func TestDocker_Janitor_Clean(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name     string
+		hostname string
+		active   []string
+		maxAge   time.Duration
+		want     int
+	}{
+		{
+			name:     "running pipeline",
+			hostname: "worker",
+			active:   []string{"github_octocat_1"},
+			maxAge:   30 * time.Minute,
+			want:     3,
+		},
+		{
+			name:     "no running pipelines",
+			hostname: "worker",
+			active:   nil,
+			maxAge:   30 * time.Minute,
+			want:     6,
+		},
+		{
+			name:     "other worker",
+			hostname: "other-worker",
+			active:   nil,
+			maxAge:   30 * time.Minute,
+			want:     3,
+		},
+		{
+			name:     "resources too recent",
+			hostname: "worker",
+			active:   nil,
+			maxAge:   2 * time.Hour,
+			want:     0,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_engine, err := NewMock(WithBuildLabels("", "", 0, test.hostname))
+			if err != nil {
+				t.Errorf("unable to create runtime engine: %v", err)
+			}
+
+			j, err := NewJanitor(_engine, func() []string { return test.active }, time.Minute, test.maxAge)
+			if err != nil {
+				t.Errorf("NewJanitor returned err: %v", err)
+			}
+
+			got, err := j.Clean(context.Background())
+			if err != nil {
+				t.Errorf("Clean returned err: %v", err)
+			}
+
+			if got != test.want {
+				t.Errorf("Clean removed %d resources, want %d", got, test.want)
+			}
+		})
+	}
+}
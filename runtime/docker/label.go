@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package docker
+
+import (
+	"maps"
+
+	"github.com/go-vela/server/compiler/types/pipeline"
+)
+
+// Labels applied to the Docker resources created by the runtime.
+//
+// Operators can filter the resources with the labels:
+//
+// docker ps --filter label=vela.repo=github/octocat --filter label=vela.build=1
+// .
+const (
+	// LabelPipeline is the label with the ID of the pipeline for every resource.
+	LabelPipeline = "vela.pipeline"
+	// LabelOrg is the label with the org for the repo of the build for every resource.
+	LabelOrg = "vela.org"
+	// LabelRepo is the label with the full name of the repo of the build for every resource.
+	LabelRepo = "vela.repo"
+	// LabelBuild is the label with the number of the build for every resource.
+	LabelBuild = "vela.build"
+	// LabelWorker is the label with the hostname of the worker for every resource.
+	LabelWorker = "vela.worker"
+	// LabelStep is the label with the name of the step or service for containers.
+	LabelStep = "vela.step"
+)
+
+// labels is a helper function to generate the labels
+// applied to the Docker resources created for a pipeline.
+func (c *client) labels(b *pipeline.Build) map[string]string {
+	labels := map[string]string{
+		LabelPipeline: b.ID,
+	}
+
+	// add the labels for the build and worker
+	maps.Copy(labels, c.config.Labels)
+
+	return labels
+}
+
+// ctnLabels is a helper function to generate the labels
+// applied to the Docker container created for a pipeline.
+func (c *client) ctnLabels(ctn *pipeline.Container, b *pipeline.Build) map[string]string {
+	labels := c.labels(b)
+
+	if len(ctn.Name) > 0 {
+		labels[LabelStep] = ctn.Name
+	}
+
+	return labels
+}
@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package docker
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/go-vela/server/compiler/types/pipeline"
+)
+
+func TestDocker_labels(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name string
+		opts []ClientOpt
+		want map[string]string
+	}{
+		{
+			name: "build labels",
+			opts: []ClientOpt{WithBuildLabels("github", "github/octocat", 1, "worker")},
+			want: map[string]string{
+				LabelPipeline: "github_octocat_1",
+				LabelOrg:      "github",
+				LabelRepo:     "github/octocat",
+				LabelBuild:    "1",
+				LabelWorker:   "worker",
+			},
+		},
+		{
+			name: "no build labels",
+			opts: nil,
+			want: map[string]string{LabelPipeline: "github_octocat_1"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_engine, err := NewMock(test.opts...)
+			if err != nil {
+				t.Errorf("unable to create runtime engine: %v", err)
+			}
+
+			got := _engine.labels(_pipeline)
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("labels is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDocker_ctnLabels(t *testing.T) {
+	// setup types
+	_engine, err := NewMock(WithBuildLabels("github", "github/octocat", 1, "worker"))
+	if err != nil {
+		t.Errorf("unable to create runtime engine: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		name      string
+		container *pipeline.Container
+		want      map[string]string
+	}{
+		{
+			name:      "step",
+			container: _container,
+			want: map[string]string{
+				LabelPipeline: "github_octocat_1",
+				LabelOrg:      "github",
+				LabelRepo:     "github/octocat",
+				LabelBuild:    "1",
+				LabelWorker:   "worker",
+				LabelStep:     "clone",
+			},
+		},
+		{
+			name:      "no name",
+			container: &pipeline.Container{ID: "step_github_octocat_1_clone"},
+			want: map[string]string{
+				LabelPipeline: "github_octocat_1",
+				LabelOrg:      "github",
+				LabelRepo:     "github/octocat",
+				LabelBuild:    "1",
+				LabelWorker:   "worker",
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := _engine.ctnLabels(test.container, _pipeline)
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ctnLabels is %v, want %v", got, test.want)
+			}
+		})
+	}
+
+	// ensure the container labels do not modify the build labels
+	if _, ok := _engine.config.Labels[LabelStep]; ok {
+		t.Errorf("ctnLabels modified the build labels")
+	}
+}
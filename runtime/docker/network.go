@@ -22,6 +22,7 @@ func (c *client) CreateNetwork(ctx context.Context, b *pipeline.Build) error {
 	// https://pkg.go.dev/github.com/docker/docker/api/types#NetworkCreate
 	opts := mobyClient.NetworkCreateOptions{
 		Driver: "bridge",
+		Labels: c.labels(b),
 	}
 
 	// send API call to create the network
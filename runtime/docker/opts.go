@@ -3,7 +3,15 @@
 package docker
 
 import (
+	"fmt"
+	"strconv"
+	"time"
+
+	mobyClient "github.com/moby/moby/client"
 	"github.com/sirupsen/logrus"
+
+	"github.com/go-vela/worker/internal/registry"
+	"github.com/go-vela/worker/internal/resource"
 )
 
 // ClientOpt represents a configuration option to initialize the runtime client for Docker.
@@ -21,6 +29,18 @@ func WithHostVolumes(volumes []string) ClientOpt {
 	}
 }
 
+// WithClient sets the shared Docker API client in the runtime client for Docker.
+func WithClient(_docker mobyClient.APIClient) ClientOpt {
+	return func(c *client) error {
+		c.Logger.Trace("configuring shared client in docker runtime client")
+
+		// set the Docker API client in the docker client
+		c.Docker = _docker
+
+		return nil
+	}
+}
+
 // WithLogger sets the logger in the runtime client for Docker.
 func WithLogger(logger *logrus.Entry) ClientOpt {
 	return func(c *client) error {
@@ -59,3 +79,125 @@ func WithDropCapabilities(caps []string) ClientOpt {
 		return nil
 	}
 }
+
+// WithRegistryAuth sets the registry credentials in the runtime client for Docker.
+func WithRegistryAuth(dockerConfig, credentialsFile string) ClientOpt {
+	return func(c *client) error {
+		c.Logger.Trace("configuring registry credentials in docker runtime client")
+
+		// load the registry credentials from the provided files
+		//
+		// https://pkg.go.dev/github.com/go-vela/worker/internal/registry#Load
+		creds, err := registry.Load(dockerConfig, credentialsFile)
+		if err != nil {
+			return err
+		}
+
+		// set the runtime registry credentials in the docker client
+		c.config.RegistryAuth = creds
+
+		return nil
+	}
+}
+
+// WithRegistryCredentials sets the loaded registry credentials in the runtime client for Docker.
+func WithRegistryCredentials(creds *registry.Credentials) ClientOpt {
+	return func(c *client) error {
+		c.Logger.Trace("configuring loaded registry credentials in docker runtime client")
+
+		// set the runtime registry credentials in the docker client
+		c.config.RegistryAuth = creds
+
+		return nil
+	}
+}
+
+// WithDefaultResources sets the default resource limits in the runtime client for Docker.
+func WithDefaultResources(cpu, memory string) ClientOpt {
+	return func(c *client) error {
+		c.Logger.Trace("configuring default resource limits in docker runtime client")
+
+		// parse the default resource limits
+		//
+		// https://pkg.go.dev/github.com/go-vela/worker/internal/resource#Parse
+		limits, err := resource.Parse(cpu, memory)
+		if err != nil {
+			return err
+		}
+
+		// set the runtime default resource limits in the docker client
+		c.config.DefaultLimits = limits
+
+		return nil
+	}
+}
+
+// WithMaxResources sets the maximum resource limits in the runtime client for Docker.
+func WithMaxResources(cpu, memory string) ClientOpt {
+	return func(c *client) error {
+		c.Logger.Trace("configuring maximum resource limits in docker runtime client")
+
+		// parse the maximum resource limits
+		//
+		// https://pkg.go.dev/github.com/go-vela/worker/internal/resource#Parse
+		limits, err := resource.Parse(cpu, memory)
+		if err != nil {
+			return err
+		}
+
+		// set the runtime maximum resource limits in the docker client
+		c.config.MaxLimits = limits
+
+		return nil
+	}
+}
+
+// WithBuildLabels sets the labels identifying the build and worker
+// applied to the resources created by the runtime client for Docker.
+func WithBuildLabels(org, repo string, number int64, hostname string) ClientOpt {
+	return func(c *client) error {
+		c.Logger.Trace("configuring build labels in docker runtime client")
+
+		labels := make(map[string]string)
+
+		// only add the labels with values provided
+		if len(org) > 0 {
+			labels[LabelOrg] = org
+		}
+
+		if len(repo) > 0 {
+			labels[LabelRepo] = repo
+		}
+
+		if number > 0 {
+			labels[LabelBuild] = strconv.FormatInt(number, 10)
+		}
+
+		if len(hostname) > 0 {
+			labels[LabelWorker] = hostname
+		}
+
+		// set the runtime build labels in the docker client
+		c.config.Labels = labels
+
+		return nil
+	}
+}
+
+// WithStopGracePeriod sets the time to wait for containers
+// to stop before they are killed in the runtime client for Docker.
+func WithStopGracePeriod(period time.Duration) ClientOpt {
+	return func(c *client) error {
+		c.Logger.Trace("configuring stop grace period in docker runtime client")
+
+		// check if the stop grace period provided is negative
+		if period < 0 {
+			return fmt.Errorf("invalid stop grace period provided: %s", period)
+		}
+
+		// set the runtime stop grace period in the docker client
+		c.config.StopGracePeriod = period
+
+		return nil
+	}
+}
@@ -3,10 +3,17 @@
 package docker
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
+
+	"github.com/go-vela/worker/internal/registry"
+	"github.com/go-vela/worker/internal/resource"
+	mock "github.com/go-vela/worker/mock/docker"
 )
 
 func TestDocker_ClientOpt_WithPrivilegedImages(t *testing.T) {
@@ -165,3 +172,289 @@ func TestDocker_ClientOpt_WithDropCapabilities(t *testing.T) {
 		})
 	}
 }
+
+func TestDocker_ClientOpt_WithRegistryAuth(t *testing.T) {
+	// setup types
+	dir := t.TempDir()
+
+	secrets := filepath.Join(dir, "secrets.yml")
+
+	err := os.WriteFile(secrets, []byte("registry.example.com:\n  username: octocat\n  password: superSecretPassword\n"), 0o600)
+	if err != nil {
+		t.Errorf("unable to write registry credentials file: %v", err)
+	}
+
+	// setup tests
+	tests := []struct {
+		name            string
+		failure         bool
+		dockerConfig    string
+		credentialsFile string
+	}{
+		{
+			name:            "credentials file",
+			failure:         false,
+			credentialsFile: secrets,
+		},
+		{
+			name:         "missing docker config",
+			failure:      true,
+			dockerConfig: filepath.Join(dir, "config.json"),
+		},
+		{
+			name:            "missing credentials file",
+			failure:         true,
+			credentialsFile: filepath.Join(dir, "missing.yml"),
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_service, err := New(
+				WithRegistryAuth(test.dockerConfig, test.credentialsFile),
+			)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("WithRegistryAuth should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("WithRegistryAuth returned err: %v", err)
+			}
+
+			if _service.config.RegistryAuth == nil {
+				t.Errorf("WithRegistryAuth is nil")
+			}
+		})
+	}
+}
+
+func TestDocker_ClientOpt_WithRegistryCredentials(t *testing.T) {
+	// setup types
+	creds := new(registry.Credentials)
+
+	// setup tests
+	tests := []struct {
+		name  string
+		creds *registry.Credentials
+		want  *registry.Credentials
+	}{
+		{
+			name:  "credentials",
+			creds: creds,
+			want:  creds,
+		},
+		{
+			name:  "no credentials",
+			creds: nil,
+			want:  nil,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_service, err := New(
+				WithRegistryCredentials(test.creds),
+			)
+			if err != nil {
+				t.Errorf("WithRegistryCredentials returned err: %v", err)
+			}
+
+			if _service.config.RegistryAuth != test.want {
+				t.Errorf("WithRegistryCredentials is %v, want %v", _service.config.RegistryAuth, test.want)
+			}
+		})
+	}
+}
+
+func TestDocker_ClientOpt_WithResources(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name         string
+		failure      bool
+		cpu          string
+		memory       string
+		wantDefaults resource.Limits
+		wantMax      resource.Limits
+	}{
+		{
+			name:         "defined",
+			failure:      false,
+			cpu:          "1.5",
+			memory:       "512m",
+			wantDefaults: resource.Limits{CPU: 1500, Memory: 512 * 1024 * 1024},
+			wantMax:      resource.Limits{CPU: 1500, Memory: 512 * 1024 * 1024},
+		},
+		{
+			name:         "empty",
+			failure:      false,
+			wantDefaults: resource.Limits{},
+			wantMax:      resource.Limits{},
+		},
+		{
+			name:    "invalid",
+			failure: true,
+			cpu:     "lots",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_service, err := New(
+				WithDefaultResources(test.cpu, test.memory),
+				WithMaxResources(test.cpu, test.memory),
+			)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("WithDefaultResources should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("WithDefaultResources returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(_service.config.DefaultLimits, test.wantDefaults) {
+				t.Errorf("WithDefaultResources is %v, want %v", _service.config.DefaultLimits, test.wantDefaults)
+			}
+
+			if !reflect.DeepEqual(_service.config.MaxLimits, test.wantMax) {
+				t.Errorf("WithMaxResources is %v, want %v", _service.config.MaxLimits, test.wantMax)
+			}
+		})
+	}
+}
+
+func TestDocker_ClientOpt_WithBuildLabels(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name     string
+		org      string
+		repo     string
+		number   int64
+		hostname string
+		want     map[string]string
+	}{
+		{
+			name:     "defined",
+			org:      "github",
+			repo:     "github/octocat",
+			number:   1,
+			hostname: "worker",
+			want: map[string]string{
+				LabelOrg:    "github",
+				LabelRepo:   "github/octocat",
+				LabelBuild:  "1",
+				LabelWorker: "worker",
+			},
+		},
+		{
+			name:     "hostname",
+			hostname: "worker",
+			want:     map[string]string{LabelWorker: "worker"},
+		},
+		{
+			name: "empty",
+			want: map[string]string{},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_service, err := New(
+				WithBuildLabels(test.org, test.repo, test.number, test.hostname),
+			)
+			if err != nil {
+				t.Errorf("WithBuildLabels returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(_service.config.Labels, test.want) {
+				t.Errorf("WithBuildLabels is %v, want %v", _service.config.Labels, test.want)
+			}
+		})
+	}
+}
+
+func TestDocker_ClientOpt_WithClient(t *testing.T) {
+	// setup types
+	_docker, err := mock.New()
+	if err != nil {
+		t.Errorf("unable to create docker mock: %v", err)
+	}
+
+	// run test
+	_service, err := New(
+		WithClient(_docker),
+	)
+	if err != nil {
+		t.Errorf("WithClient returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(_service.Docker, _docker) {
+		t.Errorf("WithClient is %v, want %v", _service.Docker, _docker)
+	}
+}
+
+func TestDocker_ClientOpt_WithStopGracePeriod(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		failure bool
+		period  time.Duration
+		want    time.Duration
+	}{
+		{
+			name:    "defined",
+			failure: false,
+			period:  30 * time.Second,
+			want:    30 * time.Second,
+		},
+		{
+			name:    "empty",
+			failure: false,
+			period:  0,
+			want:    0,
+		},
+		{
+			name:    "negative",
+			failure: true,
+			period:  -time.Second,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_service, err := New(
+				WithStopGracePeriod(test.period),
+			)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("WithStopGracePeriod should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("WithStopGracePeriod returned err: %v", err)
+			}
+
+			if _service.config.StopGracePeriod != test.want {
+				t.Errorf("WithStopGracePeriod is %v, want %v", _service.config.StopGracePeriod, test.want)
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/go-vela/server/compiler/types/pipeline"
 	"github.com/go-vela/server/constants"
+	"github.com/go-vela/worker/internal/resource"
 	vol "github.com/go-vela/worker/internal/volume"
 )
 
@@ -28,6 +29,7 @@ func (c *client) CreateVolume(ctx context.Context, b *pipeline.Build) error {
 	opts := mobyClient.VolumeCreateOptions{
 		Name:   b.ID,
 		Driver: "local",
+		Labels: c.labels(b),
 	}
 
 	// send API call to create the volume
@@ -89,8 +91,8 @@ func (c *client) RemoveVolume(ctx context.Context, b *pipeline.Build) error {
 }
 
 // hostConfig is a helper function to generate the host config
-// with Ulimit and volume specifications for a container.
-func hostConfig(logger *logrus.Entry, id string, ulimits pipeline.UlimitSlice, volumes []string, dropCaps []string) *container.HostConfig {
+// with Ulimit, resource and volume specifications for a container.
+func hostConfig(logger *logrus.Entry, id string, ulimits pipeline.UlimitSlice, limits resource.Limits, volumes []string, dropCaps []string) *container.HostConfig {
 	logger.Tracef("creating mount for default volume %s", id)
 
 	// create default mount for pipeline volume
@@ -102,9 +104,15 @@ func hostConfig(logger *logrus.Entry, id string, ulimits pipeline.UlimitSlice, v
 		},
 	}
 
-	resources := container.Resources{}
-	// iterate through all ulimits provided
+	// set the CPU and memory limits for the container
+	//
+	// https://pkg.go.dev/github.com/moby/moby/api/types/container#Resources
+	resources := container.Resources{
+		NanoCPUs: limits.CPU * 1e6,
+		Memory:   limits.Memory,
+	}
 
+	// iterate through all ulimits provided
 	for _, v := range ulimits {
 		resources.Ulimits = append(resources.Ulimits, &units.Ulimit{
 			Name: v.Name,
@@ -7,6 +7,7 @@ import (
 	"io"
 
 	"github.com/go-vela/server/compiler/types/pipeline"
+	"github.com/go-vela/worker/internal/exit"
 )
 
 // Engine represents the interface for Vela integrating
@@ -18,6 +19,9 @@ type Engine interface {
 	// Driver defines a function that outputs
 	// the configured runtime driver.
 	Driver() string
+	// Ping defines a function that verifies
+	// the runtime environment is reachable.
+	Ping(context.Context) error
 
 	// Build Engine Interface Functions
 
@@ -37,12 +41,20 @@ type Engine interface {
 	// RemoveBuild defines a function that deletes
 	// (kill, remove) the pipeline build metadata.
 	RemoveBuild(context.Context, *pipeline.Build) error
+	// ReconcileBuild defines a function that deletes the
+	// resources left behind for the pipeline build by a
+	// previous worker process.
+	ReconcileBuild(context.Context, *pipeline.Build) error
 
 	// Container Engine Interface Functions
 
 	// InspectContainer defines a function that inspects
 	// the pipeline container.
 	InspectContainer(context.Context, *pipeline.Container) error
+	// ExitReasonContainer defines a function that captures why
+	// the pipeline container exited when it was terminated by
+	// the infrastructure rather than the commands it executed.
+	ExitReasonContainer(context.Context, *pipeline.Container) (*exit.Reason, error)
 	// PollOutputsContainer defines a function that captures
 	// file contents from the outputs container.
 	PollOutputsContainer(context.Context, *pipeline.Container, string) ([]byte, error)
@@ -3,6 +3,8 @@
 package runtime
 
 import (
+	"time"
+
 	"github.com/urfave/cli/v3"
 
 	"github.com/go-vela/server/constants"
@@ -88,4 +90,98 @@ var Flags = []cli.Flag{
 			cli.File("/vela/runtime/drop_capabilities"),
 		),
 	},
+	&cli.StringFlag{
+		Name:  "runtime.docker-config",
+		Usage: "path to docker config.json file containing registry credentials and credential helpers - defaults to $DOCKER_CONFIG/config.json or ~/.docker/config.json when no registry credentials file is provided; reloaded on SIGHUP (only used by Docker)",
+		Sources: cli.NewValueSourceChain(
+			cli.EnvVar("VELA_RUNTIME_DOCKER_CONFIG"),
+			cli.EnvVar("RUNTIME_DOCKER_CONFIG"),
+			cli.File("/vela/runtime/docker_config"),
+		),
+	},
+	&cli.StringFlag{
+		Name:  "runtime.registry-credentials",
+		Usage: "path to YAML or JSON file containing credentials per image registry - reloaded on SIGHUP (only used by Docker)",
+		Sources: cli.NewValueSourceChain(
+			cli.EnvVar("VELA_RUNTIME_REGISTRY_CREDENTIALS"),
+			cli.EnvVar("RUNTIME_REGISTRY_CREDENTIALS"),
+			cli.File("/vela/runtime/registry_credentials"),
+		),
+	},
+	&cli.StringFlag{
+		Name:  "runtime.default-cpu-limit",
+		Usage: "default CPU limit for each container as a number of CPUs (i.e. 1.5) or millicores (i.e. 1500m)",
+		Sources: cli.NewValueSourceChain(
+			cli.EnvVar("VELA_RUNTIME_DEFAULT_CPU_LIMIT"),
+			cli.EnvVar("RUNTIME_DEFAULT_CPU_LIMIT"),
+			cli.File("/vela/runtime/default_cpu_limit"),
+		),
+	},
+	&cli.StringFlag{
+		Name:  "runtime.default-memory-limit",
+		Usage: "default memory limit for each container (i.e. 512m or 2g)",
+		Sources: cli.NewValueSourceChain(
+			cli.EnvVar("VELA_RUNTIME_DEFAULT_MEMORY_LIMIT"),
+			cli.EnvVar("RUNTIME_DEFAULT_MEMORY_LIMIT"),
+			cli.File("/vela/runtime/default_memory_limit"),
+		),
+	},
+	&cli.StringFlag{
+		Name:  "runtime.max-cpu-limit",
+		Usage: "maximum CPU limit a container can request as a number of CPUs (i.e. 4) or millicores (i.e. 4000m)",
+		Sources: cli.NewValueSourceChain(
+			cli.EnvVar("VELA_RUNTIME_MAX_CPU_LIMIT"),
+			cli.EnvVar("RUNTIME_MAX_CPU_LIMIT"),
+			cli.File("/vela/runtime/max_cpu_limit"),
+		),
+	},
+	&cli.StringFlag{
+		Name:  "runtime.max-memory-limit",
+		Usage: "maximum memory limit a container can request (i.e. 8g)",
+		Sources: cli.NewValueSourceChain(
+			cli.EnvVar("VELA_RUNTIME_MAX_MEMORY_LIMIT"),
+			cli.EnvVar("RUNTIME_MAX_MEMORY_LIMIT"),
+			cli.File("/vela/runtime/max_memory_limit"),
+		),
+	},
+	&cli.DurationFlag{
+		Name:  "runtime.janitor-interval",
+		Usage: "interval to remove orphaned runtime resources left behind by aborted builds - set to 0 to disable (only used by Docker)",
+		Sources: cli.NewValueSourceChain(
+			cli.EnvVar("VELA_RUNTIME_JANITOR_INTERVAL"),
+			cli.EnvVar("RUNTIME_JANITOR_INTERVAL"),
+			cli.File("/vela/runtime/janitor_interval"),
+		),
+		Value: 10 * time.Minute,
+	},
+	&cli.DurationFlag{
+		Name:  "runtime.janitor-max-age",
+		Usage: "minimum age of orphaned runtime resources before they are removed (only used by Docker)",
+		Sources: cli.NewValueSourceChain(
+			cli.EnvVar("VELA_RUNTIME_JANITOR_MAX_AGE"),
+			cli.EnvVar("RUNTIME_JANITOR_MAX_AGE"),
+			cli.File("/vela/runtime/janitor_max_age"),
+		),
+		Value: time.Hour,
+	},
+	&cli.DurationFlag{
+		Name:  "runtime.stop-grace-period",
+		Usage: "time to wait for containers to stop after the stop signal (SIGTERM by default) before they are killed - set to 0 to kill immediately",
+		Sources: cli.NewValueSourceChain(
+			cli.EnvVar("VELA_RUNTIME_STOP_GRACE_PERIOD"),
+			cli.EnvVar("RUNTIME_STOP_GRACE_PERIOD"),
+			cli.File("/vela/runtime/stop_grace_period"),
+		),
+		Value: 10 * time.Second,
+	},
+	&cli.DurationFlag{
+		Name:  "runtime.start-timeout",
+		Usage: "time to wait for the pod for a build to be scheduled and started before the build fails (only used by Kubernetes) - set to 0 to disable",
+		Sources: cli.NewValueSourceChain(
+			cli.EnvVar("VELA_RUNTIME_START_TIMEOUT"),
+			cli.EnvVar("RUNTIME_START_TIMEOUT"),
+			cli.File("/vela/runtime/start_timeout"),
+		),
+		Value: 10 * time.Minute,
+	},
 }
@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-vela/server/compiler/types/pipeline"
+)
+
+// PollFileNames searches for files matching the provided patterns within a container.
+func (c *client) PollFileNames(ctx context.Context, ctn *pipeline.Container, _step *pipeline.Container) ([]string, error) {
+	c.Logger.Tracef("gathering files from container %s", ctn.ID)
+
+	if len(ctn.Image) == 0 {
+		return nil, nil
+	}
+
+	var results []string
+
+	paths := _step.Artifacts.Paths
+
+	for _, pattern := range paths {
+		// use find command to locate files matching the pattern
+		cmd := []string{"find", _step.Environment["VELA_WORKSPACE"], "-type", "f", "-path", "*" + pattern, "-print"}
+		c.Logger.Debugf("searching for files with pattern: %s", pattern)
+
+		stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+
+		err := c.podExec.Exec(ctx, c.config.Namespace, c.Pod.Name, ctn.ID, cmd, nil, stdout, stderr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search for pattern %q: %w: %s", pattern, err, strings.TrimSpace(stderr.String()))
+		}
+
+		if stderr.Len() > 0 {
+			return nil, fmt.Errorf("failed to search for pattern %q: exec error: %s", pattern, stderr.String())
+		}
+
+		lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+
+		c.Logger.Tracef("found %d candidates for pattern %s", len(lines), pattern)
+
+		// process each found file
+		for _, line := range lines {
+			if len(strings.TrimSpace(line)) == 0 {
+				continue
+			}
+
+			filePath := filepath.Clean(strings.TrimSpace(line))
+
+			c.Logger.Debugf("accepted file: %s", filePath)
+			results = append(results, filePath)
+		}
+	}
+
+	if len(results) == 0 {
+		return results, fmt.Errorf("no matching files found for patterns: %v", paths)
+	}
+
+	return results, nil
+}
+
+// PollFileContent captures the content and size of a file from the pipeline container.
+func (c *client) PollFileContent(ctx context.Context, ctn *pipeline.Container, path string) (io.Reader, int64, error) {
+	c.Logger.Tracef("gathering test results and attachments from container %s", ctn.ID)
+
+	if len(ctn.Image) == 0 || len(path) == 0 {
+		return nil, 0, nil
+	}
+
+	// read the file from the container
+	content, err := c.readFile(ctx, ctn, path, 0)
+	if err != nil {
+		c.Logger.Debugf("PollFileContent readFile failed for %q: %v", path, err)
+
+		return nil, 0, err
+	}
+
+	// early non-error exit if not found
+	if content == nil {
+		return nil, 0, nil
+	}
+
+	if len(content) == 0 {
+		c.Logger.Errorf("PollFileContent returned no data for path: %s", path)
+
+		return nil, 0, fmt.Errorf("no data returned from container for %q", path)
+	}
+
+	return bytes.NewReader(content), int64(len(content)), nil
+}
+
+// readFile captures the content of a file from the pipeline container
+// using the pod exec subresource. The shared workspace volume (emptyDir)
+// makes files written by any step readable from the container.
+//
+// If the file does not exist, nil is returned. If a limit is provided
+// and the file exceeds it, errFileTooLarge is returned.
+func (c *client) readFile(ctx context.Context, ctn *pipeline.Container, path string, limit int64) ([]byte, error) {
+	stdout := &limitWriter{limit: limit}
+	stderr := new(bytes.Buffer)
+
+	// capture the file content from the container
+	err := c.podExec.Exec(ctx, c.config.Namespace, c.Pod.Name, ctn.ID, []string{"cat", path}, nil, stdout, stderr)
+	if err != nil {
+		if errors.Is(err, errFileTooLarge) || stdout.exceeded {
+			return nil, errFileTooLarge
+		}
+
+		// early non-error exit if not found
+		if strings.Contains(stderr.String(), "No such file or directory") {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("unable to read %s from container %s: %w: %s", path, ctn.ID, err, strings.TrimSpace(stderr.String()))
+	}
+
+	// distinguish an empty file from a file that does not exist
+	if stdout.Len() == 0 {
+		return []byte{}, nil
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// errFileTooLarge is returned when a file read from
+// a container exceeds the provided limit.
+var errFileTooLarge = errors.New("file exceeds maximum allowed size")
+
+// limitWriter buffers the data written to it and returns
+// an error once the data exceeds the limit (if provided).
+type limitWriter struct {
+	bytes.Buffer
+
+	limit    int64
+	exceeded bool
+}
+
+// Write appends the data to the buffer unless it exceeds the limit.
+func (w *limitWriter) Write(p []byte) (int, error) {
+	if w.limit > 0 && int64(w.Len()+len(p)) > w.limit {
+		w.exceeded = true
+
+		return 0, errFileTooLarge
+	}
+
+	return w.Buffer.Write(p)
+}
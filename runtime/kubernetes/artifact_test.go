@@ -0,0 +1,225 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package kubernetes
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/go-vela/server/compiler/types/pipeline"
+)
+
+func TestKubernetes_PollFileNames(t *testing.T) {
+	// setup types
+	_engine, err := NewMock(_pod)
+	if err != nil {
+		t.Errorf("unable to create runtime engine: %v", err)
+	}
+
+	_engine.SimulateFile("/vela/src/github.com/octocat/helloworld/test-results/junit.xml", []byte("<testsuites/>"))
+	_engine.SimulateFile("/vela/src/github.com/octocat/helloworld/test-results/report.xml", []byte("<testsuites/>"))
+	_engine.SimulateFile("/vela/src/github.com/octocat/helloworld/artifacts/build/alpha.txt", []byte("alpha"))
+	_engine.SimulateFile("/vela/src/github.com/octocat/helloworld/README.md", []byte("# helloworld"))
+
+	// setup tests
+	tests := []struct {
+		name      string
+		failure   bool
+		container *pipeline.Container
+		step      *pipeline.Container
+		want      []string
+	}{
+		{
+			name:      "test-results XML files",
+			failure:   false,
+			container: _container,
+			step: &pipeline.Container{
+				Artifacts: pipeline.Artifacts{
+					Paths: []string{"test-results/*.xml"},
+				},
+				Environment: map[string]string{
+					"VELA_WORKSPACE": "/vela/src/github.com/octocat/helloworld",
+				},
+			},
+			want: []string{
+				"/vela/src/github.com/octocat/helloworld/test-results/junit.xml",
+				"/vela/src/github.com/octocat/helloworld/test-results/report.xml",
+			},
+		},
+		{
+			name:      "multiple patterns",
+			failure:   false,
+			container: _container,
+			step: &pipeline.Container{
+				Artifacts: pipeline.Artifacts{
+					Paths: []string{"artifacts/*/*.txt", "README.md"},
+				},
+				Environment: map[string]string{
+					"VELA_WORKSPACE": "/vela/src/github.com/octocat/helloworld",
+				},
+			},
+			want: []string{
+				"/vela/src/github.com/octocat/helloworld/artifacts/build/alpha.txt",
+				"/vela/src/github.com/octocat/helloworld/README.md",
+			},
+		},
+		{
+			name:      "no matching files",
+			failure:   true,
+			container: _container,
+			step: &pipeline.Container{
+				Artifacts: pipeline.Artifacts{
+					Paths: []string{"cypress/screenshots/**/*.png"},
+				},
+				Environment: map[string]string{
+					"VELA_WORKSPACE": "/vela/src/github.com/octocat/helloworld",
+				},
+			},
+		},
+		{
+			name:    "container not in pod",
+			failure: true,
+			container: &pipeline.Container{
+				ID:    "step-github-octocat-1-notfound",
+				Image: "alpine:latest",
+			},
+			step: &pipeline.Container{
+				Artifacts: pipeline.Artifacts{
+					Paths: []string{"test-results/*.xml"},
+				},
+				Environment: map[string]string{
+					"VELA_WORKSPACE": "/vela/src/github.com/octocat/helloworld",
+				},
+			},
+		},
+		{
+			name:      "empty container image",
+			failure:   false,
+			container: new(pipeline.Container),
+			step:      _container,
+			want:      nil,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := _engine.PollFileNames(context.Background(), test.container, test.step)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("PollFileNames should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("PollFileNames returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("PollFileNames is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestKubernetes_PollFileContent(t *testing.T) {
+	// setup types
+	_engine, err := NewMock(_pod)
+	if err != nil {
+		t.Errorf("unable to create runtime engine: %v", err)
+	}
+
+	_engine.SimulateFile("/vela/src/github.com/octocat/helloworld/test-results/junit.xml", []byte("<testsuites/>"))
+	_engine.SimulateFile("/vela/src/github.com/octocat/helloworld/test-results/empty.xml", []byte{})
+
+	// setup tests
+	tests := []struct {
+		name      string
+		failure   bool
+		container *pipeline.Container
+		path      string
+		want      string
+	}{
+		{
+			name:      "file exists",
+			failure:   false,
+			container: _container,
+			path:      "/vela/src/github.com/octocat/helloworld/test-results/junit.xml",
+			want:      "<testsuites/>",
+		},
+		{
+			name:      "file not found",
+			failure:   false,
+			container: _container,
+			path:      "/vela/src/github.com/octocat/helloworld/test-results/notfound.xml",
+			want:      "",
+		},
+		{
+			name:      "empty file",
+			failure:   true,
+			container: _container,
+			path:      "/vela/src/github.com/octocat/helloworld/test-results/empty.xml",
+		},
+		{
+			name:    "container not in pod",
+			failure: true,
+			container: &pipeline.Container{
+				ID:    "step-github-octocat-1-notfound",
+				Image: "alpine:latest",
+			},
+			path: "/vela/src/github.com/octocat/helloworld/test-results/junit.xml",
+		},
+		{
+			name:      "empty path",
+			failure:   false,
+			container: _container,
+			path:      "",
+			want:      "",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reader, size, err := _engine.PollFileContent(context.Background(), test.container, test.path)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("PollFileContent should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("PollFileContent returned err: %v", err)
+			}
+
+			if size != int64(len(test.want)) {
+				t.Errorf("PollFileContent size is %d, want %d", size, len(test.want))
+			}
+
+			if reader == nil {
+				if len(test.want) > 0 {
+					t.Errorf("PollFileContent reader is nil, want %s", test.want)
+				}
+
+				return
+			}
+
+			got, err := io.ReadAll(reader)
+			if err != nil {
+				t.Errorf("unable to read content: %v", err)
+			}
+
+			if string(got) != test.want {
+				t.Errorf("PollFileContent is %s, want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -4,11 +4,17 @@ package kubernetes
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	// The k8s libraries have some quirks around yaml marshaling (see opts.go).
 	// So, just use the same library for all kubernetes-related YAML.
@@ -16,6 +22,7 @@ import (
 
 	"github.com/go-vela/server/compiler/types/pipeline"
 	"github.com/go-vela/server/constants"
+	"github.com/go-vela/worker/internal/infra"
 	"github.com/go-vela/worker/runtime/kubernetes/apis/vela/v1alpha1"
 )
 
@@ -212,6 +219,14 @@ func (c *client) AssembleBuild(ctx context.Context, b *pipeline.Build) error {
 		}
 	}
 
+	// setup the environment for the containers outside of the pipeline (i.e. the outputs container)
+	for _, name := range slices.Sorted(maps.Keys(c.pendingEnvironment)) {
+		err = c.setupContainerEnvironment(c.pendingEnvironment[name])
+		if err != nil {
+			return err
+		}
+	}
+
 	// setup containerTrackers now that all containers are defined.
 	c.PodTracker.TrackContainers(c.Pod.Spec.Containers)
 
@@ -239,9 +254,110 @@ func (c *client) AssembleBuild(ctx context.Context, b *pipeline.Build) error {
 		return err
 	}
 
+	// wait for the pod to start before any containers execute
+	return c.waitForPod(ctx)
+}
+
+// waitForPod is a helper function to wait for the pod to be scheduled
+// and for every container in the pod to start the kubernetes/pause image.
+//
+// The pod failing to start is caused by the cluster rather than the
+// pipeline, i.e. no node has the resources left to run the pod, so
+// the failure is marked as an infrastructure failure.
+func (c *client) waitForPod(ctx context.Context) error {
+	// check if waiting for the pod is disabled
+	if c.config.StartTimeout <= 0 {
+		return nil
+	}
+
+	c.Logger.Tracef("waiting for pod %s to start", c.Pod.Name)
+
+	// capture why the pod has not started yet
+	var reason string
+
+	// https://pkg.go.dev/k8s.io/apimachinery/pkg/util/wait#PollUntilContextTimeout
+	err := wait.PollUntilContextTimeout(ctx, time.Second, c.config.StartTimeout, true, func(context.Context) (bool, error) {
+		// get the pod from the local cache, which the Informer keeps up-to-date
+		pod, err := c.PodTracker.PodLister.
+			Pods(c.config.Namespace).
+			Get(c.Pod.Name)
+		if err != nil {
+			// the Informer has not observed the pod yet
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+
+			return false, err
+		}
+
+		reason, err = podPending(pod)
+		if err != nil {
+			return false, err
+		}
+
+		return len(reason) == 0, nil
+	})
+
+	// check if the build timed out or was canceled
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	// check if the pod failed while starting
+	var infraErr *infra.Error
+	if errors.As(err, &infraErr) {
+		return err
+	}
+
+	if err != nil {
+		// https://pkg.go.dev/github.com/go-vela/worker/internal/infra#Wrap
+		return infra.Wrap(fmt.Errorf("pod %s did not start within %s: %s", c.Pod.Name, c.config.StartTimeout, reason))
+	}
+
 	return nil
 }
 
+// podPending is a helper function to capture why the pod has not
+// started yet. An empty reason is returned once the pod started.
+//
+// An error is returned if the pod failed, i.e. it was evicted from
+// the node due to node pressure.
+func podPending(pod *v1.Pod) (string, error) {
+	// check if the pod failed
+	//
+	// https://pkg.go.dev/k8s.io/api/core/v1#PodStatus
+	if pod.Status.Phase == v1.PodFailed {
+		// https://pkg.go.dev/github.com/go-vela/worker/internal/infra#Wrap
+		return "", infra.Wrap(fmt.Errorf("pod %s failed (%s): %s", pod.Name, pod.Status.Reason, pod.Status.Message))
+	}
+
+	// check if the pod could not be scheduled to a node
+	//
+	// https://pkg.go.dev/k8s.io/api/core/v1#PodCondition
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionFalse {
+			return fmt.Sprintf("pod is %s: %s", strings.ToLower(condition.Reason), condition.Message), nil
+		}
+	}
+
+	// check if any container is waiting to start, i.e. the
+	// kubernetes/pause image could not be pulled
+	//
+	// https://pkg.go.dev/k8s.io/api/core/v1#ContainerState
+	for _, cst := range pod.Status.ContainerStatuses {
+		if cst.State.Waiting != nil {
+			return fmt.Sprintf("container %s is waiting (%s): %s", cst.Name, cst.State.Waiting.Reason, cst.State.Waiting.Message), nil
+		}
+	}
+
+	// check if the pod is still pending
+	if pod.Status.Phase == v1.PodPending || len(pod.Status.Phase) == 0 {
+		return "pod is pending", nil
+	}
+
+	return "", nil
+}
+
 // RemoveBuild deletes (kill, remove) the pipeline build metadata.
 // This deletes the kubernetes pod.
 func (c *client) RemoveBuild(ctx context.Context, b *pipeline.Build) error {
@@ -265,8 +381,12 @@ func (c *client) RemoveBuild(ctx context.Context, b *pipeline.Build) error {
 	//
 	// This is necessary because the delete options
 	// expect all values to be passed by reference.
+	//
+	// The containers in the pod receive the stop signal for the image
+	// (SIGTERM by default) and are killed if they are still running
+	// once the grace period expires.
 	var (
-		period = int64(0)
+		period = int64(c.config.StopGracePeriod.Seconds())
 		policy = metav1.DeletePropagationForeground
 	)
 
@@ -293,3 +413,38 @@ func (c *client) RemoveBuild(ctx context.Context, b *pipeline.Build) error {
 
 	return nil
 }
+
+// ReconcileBuild deletes the pod left behind for the
+// pipeline build by a previous worker process.
+func (c *client) ReconcileBuild(ctx context.Context, b *pipeline.Build) error {
+	c.Logger.Tracef("reconciling build %s", b.ID)
+
+	// create variables for the delete options
+	//
+	// This is necessary because the delete options
+	// expect all values to be passed by reference.
+	var (
+		period = int64(0)
+		policy = metav1.DeletePropagationForeground
+	)
+
+	// create options for removing the pod
+	//
+	// https://pkg.go.dev/k8s.io/apimachinery/pkg/apis/meta/v1#DeleteOptions
+	opts := metav1.DeleteOptions{
+		GracePeriodSeconds: &period,
+		// https://pkg.go.dev/k8s.io/apimachinery/pkg/apis/meta/v1#DeletionPropagation
+		PropagationPolicy: &policy,
+	}
+
+	c.Logger.Debugf("removing leftover pod %s", b.ID)
+	// send API call to delete the pod
+	err := c.Kubernetes.CoreV1().
+		Pods(c.config.Namespace).
+		Delete(ctx, b.ID, opts)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
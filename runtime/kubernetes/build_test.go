@@ -6,11 +6,14 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/go-vela/server/compiler/types/pipeline"
+	"github.com/go-vela/worker/internal/infra"
 	velav1alpha1 "github.com/go-vela/worker/runtime/kubernetes/apis/vela/v1alpha1"
 )
 
@@ -532,6 +535,127 @@ func TestKubernetes_AssembleBuild(t *testing.T) {
 	}
 }
 
+func TestKubernetes_waitForPod(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		failure bool
+		timeout time.Duration
+		pod     *v1.Pod
+	}{
+		{
+			name:    "pod started",
+			failure: false,
+			timeout: time.Minute,
+			pod:     _pod,
+		},
+		{
+			name:    "pod evicted",
+			failure: true,
+			timeout: time.Minute,
+			pod: &v1.Pod{
+				ObjectMeta: _pod.ObjectMeta,
+				TypeMeta:   _pod.TypeMeta,
+				Spec:       _pod.Spec,
+				Status: v1.PodStatus{
+					Phase:   v1.PodFailed,
+					Reason:  "Evicted",
+					Message: "The node was low on resource: memory.",
+				},
+			},
+		},
+		{
+			name:    "pod unschedulable",
+			failure: true,
+			timeout: 10 * time.Millisecond,
+			pod: &v1.Pod{
+				ObjectMeta: _pod.ObjectMeta,
+				TypeMeta:   _pod.TypeMeta,
+				Spec:       _pod.Spec,
+				Status: v1.PodStatus{
+					Phase: v1.PodPending,
+					Conditions: []v1.PodCondition{
+						{
+							Type:    v1.PodScheduled,
+							Status:  v1.ConditionFalse,
+							Reason:  v1.PodReasonUnschedulable,
+							Message: "0/3 nodes are available: 3 Insufficient cpu.",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "pause image could not be pulled",
+			failure: true,
+			timeout: 10 * time.Millisecond,
+			pod: &v1.Pod{
+				ObjectMeta: _pod.ObjectMeta,
+				TypeMeta:   _pod.TypeMeta,
+				Spec:       _pod.Spec,
+				Status: v1.PodStatus{
+					Phase: v1.PodPending,
+					ContainerStatuses: []v1.ContainerStatus{
+						{
+							Name: "step-github-octocat-1-clone",
+							State: v1.ContainerState{
+								Waiting: &v1.ContainerStateWaiting{
+									Reason:  "ImagePullBackOff",
+									Message: "Back-off pulling image",
+								},
+							},
+							Image: pauseImage,
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "disabled",
+			failure: false,
+			timeout: 0,
+			pod: &v1.Pod{
+				ObjectMeta: _pod.ObjectMeta,
+				TypeMeta:   _pod.TypeMeta,
+				Spec:       _pod.Spec,
+				Status: v1.PodStatus{
+					Phase: v1.PodPending,
+				},
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_engine, err := NewMock(test.pod)
+			if err != nil {
+				t.Errorf("unable to create runtime engine: %v", err)
+			}
+
+			_engine.config.StartTimeout = test.timeout
+
+			err = _engine.waitForPod(context.Background())
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("waitForPod should have returned err")
+				}
+
+				if !infra.Is(err) {
+					t.Errorf("waitForPod returned err %v, want an infrastructure failure", err)
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("waitForPod returned err: %v", err)
+			}
+		})
+	}
+}
+
 func TestKubernetes_RemoveBuild(t *testing.T) {
 	// setup tests
 	tests := []struct {
@@ -610,3 +734,62 @@ func TestKubernetes_RemoveBuild(t *testing.T) {
 		})
 	}
 }
+
+func TestKubernetes_ReconcileBuild(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name     string
+		failure  bool
+		pipeline *pipeline.Build
+		pod      *v1.Pod
+	}{
+		{
+			name:     "stages-pod in k8s",
+			failure:  false,
+			pipeline: _stages,
+			pod:      _pod,
+		},
+		{
+			name:     "steps-pod in k8s",
+			failure:  false,
+			pipeline: _steps,
+			pod:      _pod,
+		},
+		{
+			name:     "steps-pod not in k8s",
+			failure:  false,
+			pipeline: _steps,
+			pod:      &v1.Pod{},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_engine, err := NewMock(test.pod)
+			if err != nil {
+				t.Errorf("unable to create runtime engine: %v", err)
+			}
+
+			err = _engine.ReconcileBuild(context.Background(), test.pipeline)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("ReconcileBuild should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("ReconcileBuild returned err: %v", err)
+			}
+
+			// ensure the pod was removed
+			_, err = _engine.Kubernetes.CoreV1().Pods(_engine.config.Namespace).Get(context.Background(), test.pipeline.ID, metav1.GetOptions{})
+			if !apierrors.IsNotFound(err) {
+				t.Errorf("ReconcileBuild did not remove pod %s: %v", test.pipeline.ID, err)
+			}
+		})
+	}
+}
@@ -5,19 +5,25 @@ package kubernetes
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8sResource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 
 	"github.com/go-vela/server/compiler/types/pipeline"
 	"github.com/go-vela/server/constants"
+	"github.com/go-vela/worker/internal/exit"
 	"github.com/go-vela/worker/internal/image"
+	"github.com/go-vela/worker/internal/infra"
+	"github.com/go-vela/worker/internal/resource"
 )
 
 // InspectContainer inspects the pipeline container.
@@ -61,36 +67,143 @@ func (c *client) InspectContainer(_ context.Context, ctn *pipeline.Container) er
 	return nil
 }
 
-// RemoveContainer deletes (kill, remove) the pipeline container.
-// This is a no-op for kubernetes. RemoveBuild handles deleting the pod.
-func (c *client) RemoveContainer(_ context.Context, ctn *pipeline.Container) error {
-	c.Logger.Tracef("no-op: removing container %s", ctn.ID)
+// ExitReasonContainer captures why the pipeline container exited
+// when it was terminated by the infrastructure.
+func (c *client) ExitReasonContainer(_ context.Context, ctn *pipeline.Container) (*exit.Reason, error) {
+	c.Logger.Tracef("capturing exit reason for container %s", ctn.ID)
 
-	return nil
-}
+	// get the pod from the local cache, which the Informer keeps up-to-date
+	pod, err := c.PodTracker.PodLister.
+		Pods(c.config.Namespace).
+		Get(c.Pod.Name)
+	if err != nil {
+		return nil, err
+	}
 
-// PollOutputsContainer captures the `cat` response for a given path in the Docker volume.
-// This is a no-op for kubernetes. Pod environments cannot be dynamic.
-func (c *client) PollOutputsContainer(_ context.Context, ctn *pipeline.Container, _ string) ([]byte, error) {
-	c.Logger.Tracef("no-op: removing container %s", ctn.ID)
+	// check if the pod was evicted from the node due to node pressure,
+	// which terminates the containers without a reason of their own
+	//
+	// https://pkg.go.dev/k8s.io/api/core/v1#PodStatus
+	if strings.EqualFold(pod.Status.Reason, exit.ReasonEvicted) {
+		return &exit.Reason{Reason: exit.ReasonEvicted, Message: pod.Status.Message}, nil
+	}
+
+	// iterate through each container in the pod
+	for _, cst := range pod.Status.ContainerStatuses {
+		// check if the container has a matching ID
+		//
+		// https://pkg.go.dev/k8s.io/api/core/v1#ContainerStatus
+		if !strings.EqualFold(cst.Name, ctn.ID) || cst.State.Terminated == nil {
+			continue
+		}
+
+		// https://pkg.go.dev/k8s.io/api/core/v1#ContainerStateTerminated
+		terminated := cst.State.Terminated
+
+		// the kubelet reports containers that exited on their own as
+		// "Completed" or "Error" depending on the exit code
+		switch terminated.Reason {
+		case "", "Completed", "Error":
+			return nil, nil
+		}
+
+		return &exit.Reason{Reason: terminated.Reason, Message: terminated.Message}, nil
+	}
 
 	return nil, nil
 }
 
-// PollFileNames grabs test results and attachments from provided path within a container.
-// This is a no-op for kubernetes. Pod environments cannot be dynamic.
-func (c *client) PollFileNames(_ context.Context, ctn *pipeline.Container, _ *pipeline.Container) ([]string, error) {
-	c.Logger.Tracef("no-op: gathering test results and attachments from container %s", ctn.ID)
+// RemoveContainer deletes (kill, remove) the pipeline container.
+// RemoveBuild handles deleting the pod, so this only stops a container
+// that is still running the image for the pipeline container (i.e. a step
+// that exceeded its timeout) by patching it back to the kubernetes/pause image.
+func (c *client) RemoveContainer(ctx context.Context, ctn *pipeline.Container) error {
+	c.Logger.Tracef("removing container %s", ctn.ID)
+
+	// check if the pod is still tracked
+	if c.PodTracker == nil {
+		return nil
+	}
 
-	return nil, nil
+	// check if the container is part of the pod
+	index, ok := c.containersLookup[ctn.ID]
+	if !ok {
+		return nil
+	}
+
+	// get the pod from the local cache, which the Informer keeps up-to-date
+	pod, err := c.PodTracker.PodLister.
+		Pods(c.config.Namespace).
+		Get(c.Pod.Name)
+	if err != nil {
+		// the pod was never created or is already deleted
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	_image := image.Parse(pauseImage)
+
+	// iterate through each container in the pod
+	for _, cst := range pod.Status.ContainerStatuses {
+		// check if the container is running the image for the pipeline container
+		//
+		// https://pkg.go.dev/k8s.io/api/core/v1#ContainerStatus
+		if !strings.EqualFold(cst.Name, ctn.ID) || cst.State.Running == nil ||
+			cst.Image == pauseImage || cst.Image == _image {
+			continue
+		}
+
+		c.Logger.Debugf("stopping container %s", ctn.ID)
+
+		// set the pod container image back to the pause image
+		c.Pod.Spec.Containers[index].Image = _image
+
+		// send API call to patch the pod with the pause image, which
+		// makes the kubelet kill the container running the step image
+		//
+		// https://pkg.go.dev/k8s.io/client-go/kubernetes/typed/core/v1#PodInterface
+		_, err = c.Kubernetes.CoreV1().Pods(c.config.Namespace).Patch(
+			ctx,
+			c.Pod.Name,
+			types.StrategicMergePatchType,
+			fmt.Appendf(nil, imagePatch, ctn.ID, _image),
+			metav1.PatchOptions{},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-// PollFileContent captures the content and size of a file from the pipeline container.
-// This is a no-op for kubernetes. Pod environments cannot be dynamic.
-func (c *client) PollFileContent(_ context.Context, ctn *pipeline.Container, _ string) (io.Reader, int64, error) {
-	c.Logger.Tracef("no-op: gathering test results and attachments from container %s", ctn.ID)
+// PollOutputsContainer captures the `cat` response for a given path in the pod volume.
+func (c *client) PollOutputsContainer(ctx context.Context, ctn *pipeline.Container, path string) ([]byte, error) {
+	c.Logger.Tracef("polling outputs from container %s", ctn.ID)
+
+	if len(ctn.Image) == 0 || len(path) == 0 {
+		return nil, nil
+	}
+
+	// capture the outputs container for writing the environment of containers
+	c.mutex.Lock()
+	c.outputsCtn = ctn.ID
+	c.mutex.Unlock()
+
+	// read the file from the outputs container
+	content, err := c.readFile(ctx, ctn, path, MaxOutputsSize)
+	if err != nil {
+		if errors.Is(err, errFileTooLarge) {
+			return nil, fmt.Errorf("outputs file size exceeds maximum allowed size of %d", MaxOutputsSize)
+		}
+
+		return nil, err
+	}
 
-	return nil, 0, nil
+	return content, nil
 }
 
 // RunContainer creates and starts the pipeline container.
@@ -102,6 +215,12 @@ func (c *client) RunContainer(ctx context.Context, ctn *pipeline.Container, _ *p
 		return err
 	}
 
+	// write the environment that changed since the pod was created
+	err = c.writeEnvironment(ctx, ctn)
+	if err != nil {
+		return err
+	}
+
 	// set the pod container image to the parsed step image
 	c.Pod.Spec.Containers[c.containersLookup[ctn.ID]].Image = _image
 
@@ -190,6 +309,18 @@ func (c *client) SetupContainer(ctx context.Context, ctn *pipeline.Container) er
 
 	container.SecurityContext.Privileged = &privileged
 
+	// resolve the resource limits for the container
+	//
+	// https://pkg.go.dev/github.com/go-vela/worker/internal/resource#Resolve
+	limits, err := resource.Resolve(ctn.Environment, c.config.DefaultLimits, c.config.MaxLimits)
+	if err != nil {
+		return err
+	}
+
+	c.Logger.Tracef("using resource limits %s for container %s", limits, ctn.ID)
+
+	container.Resources = resourceRequirements(limits)
+
 	if c.PipelinePodTemplate != nil && c.PipelinePodTemplate.Spec.Container != nil {
 		securityContext := c.PipelinePodTemplate.Spec.Container.SecurityContext
 
@@ -205,6 +336,9 @@ func (c *client) SetupContainer(ctx context.Context, ctn *pipeline.Container) er
 	// record the index for this container
 	c.containersLookup[ctn.ID] = len(c.Pod.Spec.Containers)
 
+	// record the container for setting up the environment
+	c.pendingEnvironment[ctn.ID] = ctn
+
 	// add the container definition to the pod spec
 	//
 	// https://pkg.go.dev/k8s.io/api/core/v1#PodSpec
@@ -213,6 +347,39 @@ func (c *client) SetupContainer(ctx context.Context, ctn *pipeline.Container) er
 	return nil
 }
 
+// resourceRequirements is a helper function to generate
+// the resource requirements for a container from the limits.
+func resourceRequirements(limits resource.Limits) v1.ResourceRequirements {
+	requirements := v1.ResourceRequirements{}
+
+	if limits.IsZero() {
+		return requirements
+	}
+
+	requirements.Limits = v1.ResourceList{}
+
+	// Kubernetes defaults the requests to the limits when no requests
+	// are provided. Every container in the pod (including the steps
+	// still running the pause image) counts towards the requests for
+	// the pod, which could leave the pod unschedulable. So, explicitly
+	// request nothing and only enforce the limits.
+	requirements.Requests = v1.ResourceList{}
+
+	if limits.CPU > 0 {
+		// https://pkg.go.dev/k8s.io/apimachinery/pkg/api/resource#NewMilliQuantity
+		requirements.Limits[v1.ResourceCPU] = *k8sResource.NewMilliQuantity(limits.CPU, k8sResource.DecimalSI)
+		requirements.Requests[v1.ResourceCPU] = *k8sResource.NewMilliQuantity(0, k8sResource.DecimalSI)
+	}
+
+	if limits.Memory > 0 {
+		// https://pkg.go.dev/k8s.io/apimachinery/pkg/api/resource#NewQuantity
+		requirements.Limits[v1.ResourceMemory] = *k8sResource.NewQuantity(limits.Memory, k8sResource.BinarySI)
+		requirements.Requests[v1.ResourceMemory] = *k8sResource.NewQuantity(0, k8sResource.BinarySI)
+	}
+
+	return requirements
+}
+
 // setupContainerEnvironment adds env vars to the Pod spec for a container.
 // Call this just before pod creation to capture as many env changes as possible.
 func (c *client) setupContainerEnvironment(ctn *pipeline.Container) error {
@@ -224,6 +391,8 @@ func (c *client) setupContainerEnvironment(ctn *pipeline.Container) error {
 		return fmt.Errorf("wrong container! got %s instead of %s", container.Name, ctn.ID)
 	}
 
+	delete(c.pendingEnvironment, ctn.ID)
+
 	// check if the environment is provided
 	if len(ctn.Environment) > 0 {
 		// iterate through each element in the container environment
@@ -241,8 +410,9 @@ func (c *client) setupContainerEnvironment(ctn *pipeline.Container) error {
 
 	// check if the commands are provided
 	if len(ctn.Commands) > 0 {
-		// add commands to container config
-		container.Args = append(container.Args, ctn.Commands...)
+		// add commands to container config that source
+		// the environment written after the pod is created
+		container.Args = append(container.Args, sourceEnvironment(ctn, ctn.Commands)...)
 	}
 
 	return nil
@@ -329,7 +499,7 @@ func (c *client) TailContainer(ctx context.Context, ctn *pipeline.Container) (io
 }
 
 // WaitContainer blocks until the pipeline container completes.
-func (c *client) WaitContainer(_ context.Context, ctn *pipeline.Container) error {
+func (c *client) WaitContainer(ctx context.Context, ctn *pipeline.Container) error {
 	c.Logger.Tracef("waiting for container %s", ctn.ID)
 
 	// get the containerTracker for this container
@@ -339,12 +509,18 @@ func (c *client) WaitContainer(_ context.Context, ctn *pipeline.Container) error
 	}
 
 	// wait for the container terminated signal
-	<-tracker.Terminated
-
-	return nil
+	select {
+	case <-tracker.Terminated:
+		return nil
+	case <-tracker.Failed:
+		return tracker.Err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
-// inspectContainerStatuses signals when a container reaches a terminal state.
+// inspectContainerStatuses signals when a container reaches a terminal state
+// or fails to start.
 func (p *podTracker) inspectContainerStatuses(pod *v1.Pod) {
 	// check if the pod is in a pending state
 	//
@@ -382,5 +558,41 @@ func (p *podTracker) inspectContainerStatuses(pod *v1.Pod) {
 				close(tracker.Terminated)
 			})
 		}
+
+		// check if the image for the container could not be pulled
+		//
+		// https://pkg.go.dev/k8s.io/api/core/v1#ContainerStateWaiting
+		if cst.State.Waiting != nil && cst.Image != pauseImage && cst.Image != image.Parse(pauseImage) {
+			switch cst.State.Waiting.Reason {
+			case "ErrImagePull", "ImagePullBackOff", "InvalidImageName":
+				tracker.failedOnce.Do(func() {
+					p.Logger.Debugf("container failed: %s in pod %s, %v", cst.Name, p.TrackedPod, cst)
+
+					tracker.Err = imagePullError(cst)
+
+					// let WaitContainer know the container failed
+					close(tracker.Failed)
+				})
+			}
+		}
 	}
 }
+
+// imagePullError is a helper function to create the error
+// for a container with an image that could not be pulled.
+//
+// A missing or invalid image is caused by the pipeline, while
+// any other failure, i.e. the registry timing out, is marked
+// as an infrastructure failure.
+func imagePullError(cst v1.ContainerStatus) error {
+	waiting := cst.State.Waiting
+
+	err := fmt.Errorf("unable to pull image %s for container %s (%s): %s", cst.Image, cst.Name, waiting.Reason, waiting.Message)
+
+	if waiting.Reason == "InvalidImageName" || strings.Contains(strings.ToLower(waiting.Message), "not found") {
+		return err
+	}
+
+	// https://pkg.go.dev/github.com/go-vela/worker/internal/infra#Wrap
+	return infra.Wrap(err)
+}
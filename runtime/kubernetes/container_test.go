@@ -11,7 +11,9 @@ import (
 	v1 "k8s.io/api/core/v1"
 
 	"github.com/go-vela/server/compiler/types/pipeline"
+	"github.com/go-vela/worker/internal/exit"
 	"github.com/go-vela/worker/internal/image"
+	"github.com/go-vela/worker/internal/infra"
 	velav1alpha1 "github.com/go-vela/worker/runtime/kubernetes/apis/vela/v1alpha1"
 )
 
@@ -131,41 +133,331 @@ func TestKubernetes_InspectContainer(t *testing.T) {
 	}
 }
 
+func TestKubernetes_ExitReasonContainer(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name      string
+		failure   bool
+		pod       *v1.Pod
+		container *pipeline.Container
+		want      *exit.Reason
+	}{
+		{
+			name:      "completed build container",
+			failure:   false,
+			pod:       _pod,
+			container: _container,
+			want:      nil,
+		},
+		{
+			name:    "oom killed build container",
+			failure: false,
+			pod: &v1.Pod{
+				ObjectMeta: _pod.ObjectMeta,
+				TypeMeta:   _pod.TypeMeta,
+				Spec:       _pod.Spec,
+				Status: v1.PodStatus{
+					Phase: v1.PodRunning,
+					ContainerStatuses: []v1.ContainerStatus{
+						{
+							Name: "step-github-octocat-1-clone",
+							State: v1.ContainerState{
+								Terminated: &v1.ContainerStateTerminated{
+									Reason:   "OOMKilled",
+									ExitCode: 137,
+								},
+							},
+							Image: _container.Image,
+						},
+					},
+				},
+			},
+			container: _container,
+			want:      &exit.Reason{Reason: exit.ReasonOOMKilled},
+		},
+		{
+			name:    "evicted build container",
+			failure: false,
+			pod: &v1.Pod{
+				ObjectMeta: _pod.ObjectMeta,
+				TypeMeta:   _pod.TypeMeta,
+				Spec:       _pod.Spec,
+				Status: v1.PodStatus{
+					Phase:   v1.PodFailed,
+					Reason:  "Evicted",
+					Message: "The node was low on resource: memory.",
+					ContainerStatuses: []v1.ContainerStatus{
+						{
+							Name: "step-github-octocat-1-clone",
+							State: v1.ContainerState{
+								Terminated: &v1.ContainerStateTerminated{
+									Reason:   "ContainerStatusUnknown",
+									ExitCode: 137,
+								},
+							},
+							Image: _container.Image,
+						},
+					},
+				},
+			},
+			container: _container,
+			want:      &exit.Reason{Reason: exit.ReasonEvicted, Message: "The node was low on resource: memory."},
+		},
+		{
+			name:    "errored build container",
+			failure: false,
+			pod: &v1.Pod{
+				ObjectMeta: _pod.ObjectMeta,
+				TypeMeta:   _pod.TypeMeta,
+				Spec:       _pod.Spec,
+				Status: v1.PodStatus{
+					Phase: v1.PodRunning,
+					ContainerStatuses: []v1.ContainerStatus{
+						{
+							Name: "step-github-octocat-1-clone",
+							State: v1.ContainerState{
+								Terminated: &v1.ContainerStateTerminated{
+									Reason:   "Error",
+									ExitCode: 1,
+								},
+							},
+							Image: _container.Image,
+						},
+					},
+				},
+			},
+			container: _container,
+			want:      nil,
+		},
+		{
+			name:    "build container that cannot run",
+			failure: false,
+			pod: &v1.Pod{
+				ObjectMeta: _pod.ObjectMeta,
+				TypeMeta:   _pod.TypeMeta,
+				Spec:       _pod.Spec,
+				Status: v1.PodStatus{
+					Phase: v1.PodRunning,
+					ContainerStatuses: []v1.ContainerStatus{
+						{
+							Name: "step-github-octocat-1-clone",
+							State: v1.ContainerState{
+								Terminated: &v1.ContainerStateTerminated{
+									Reason:   "ContainerCannotRun",
+									Message:  "executable file not found in $PATH",
+									ExitCode: 128,
+								},
+							},
+							Image: _container.Image,
+						},
+					},
+				},
+			},
+			container: _container,
+			want:      &exit.Reason{Reason: "ContainerCannotRun", Message: "executable file not found in $PATH"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// setup types
+			_engine, err := NewMock(test.pod)
+			if err != nil {
+				t.Errorf("unable to create runtime engine: %v", err)
+			}
+
+			got, err := _engine.ExitReasonContainer(context.Background(), test.container)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("ExitReasonContainer should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("ExitReasonContainer returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ExitReasonContainer is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
 func TestKubernetes_RemoveContainer(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name      string
+		failure   bool
+		pod       *v1.Pod
+		container *pipeline.Container
+		want      string
+	}{
+		{
+			name:      "completed build container",
+			failure:   false,
+			pod:       _pod,
+			container: _container,
+			want:      "target/vela-git:v0.4.0",
+		},
+		{
+			name:    "running build container",
+			failure: false,
+			pod: &v1.Pod{
+				ObjectMeta: _pod.ObjectMeta,
+				TypeMeta:   _pod.TypeMeta,
+				Spec:       _pod.Spec,
+				Status: v1.PodStatus{
+					Phase: v1.PodRunning,
+					ContainerStatuses: []v1.ContainerStatus{
+						{
+							Name: "step-github-octocat-1-clone",
+							State: v1.ContainerState{
+								Running: &v1.ContainerStateRunning{},
+							},
+							Image: "target/vela-git:v0.4.0",
+						},
+					},
+				},
+			},
+			container: _container,
+			want:      image.Parse(pauseImage),
+		},
+		{
+			name:    "build container running the pause image",
+			failure: false,
+			pod: &v1.Pod{
+				ObjectMeta: _pod.ObjectMeta,
+				TypeMeta:   _pod.TypeMeta,
+				Spec:       _pod.Spec,
+				Status: v1.PodStatus{
+					Phase: v1.PodRunning,
+					ContainerStatuses: []v1.ContainerStatus{
+						{
+							Name: "step-github-octocat-1-clone",
+							State: v1.ContainerState{
+								Running: &v1.ContainerStateRunning{},
+							},
+							Image: pauseImage,
+						},
+					},
+				},
+			},
+			container: _container,
+			want:      "target/vela-git:v0.4.0",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_engine, err := NewMock(test.pod)
+			if err != nil {
+				t.Errorf("unable to create runtime engine: %v", err)
+			}
+
+			err = _engine.RemoveContainer(context.Background(), test.container)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("RemoveContainer should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("RemoveContainer returned err: %v", err)
+			}
+
+			got := _engine.Pod.Spec.Containers[0].Image
+
+			if got != test.want {
+				t.Errorf("RemoveContainer image is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestKubernetes_PollOutputsContainer(t *testing.T) {
 	// setup types
 	_engine, err := NewMock(_pod)
 	if err != nil {
 		t.Errorf("unable to create runtime engine: %v", err)
 	}
 
+	_engine.SimulateFile("/vela/outputs/.env", []byte("FOO=bar\n"))
+	_engine.SimulateFile("/vela/outputs/masked.env", make([]byte, MaxOutputsSize+1))
+
 	// setup tests
 	tests := []struct {
 		name      string
 		failure   bool
 		container *pipeline.Container
+		path      string
+		want      []byte
 	}{
 		{
-			name:      "build container",
+			name:      "outputs file",
 			failure:   false,
 			container: _container,
+			path:      "/vela/outputs/.env",
+			want:      []byte("FOO=bar\n"),
+		},
+		{
+			name:      "outputs file not found",
+			failure:   false,
+			container: _container,
+			path:      "/vela/outputs/base64.env",
+			want:      nil,
+		},
+		{
+			name:      "outputs file exceeds maximum size",
+			failure:   true,
+			container: _container,
+			path:      "/vela/outputs/masked.env",
+		},
+		{
+			name:    "container not in pod",
+			failure: true,
+			container: &pipeline.Container{
+				ID:    "step-github-octocat-1-notfound",
+				Image: "alpine:latest",
+			},
+			path: "/vela/outputs/.env",
+		},
+		{
+			name:      "empty build container",
+			failure:   false,
+			container: new(pipeline.Container),
+			path:      "/vela/outputs/.env",
+			want:      nil,
 		},
 	}
 
 	// run tests
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			err = _engine.RemoveContainer(context.Background(), test.container)
+			got, err := _engine.PollOutputsContainer(context.Background(), test.container, test.path)
 
 			if test.failure {
 				if err == nil {
-					t.Errorf("RemoveContainer should have returned err")
+					t.Errorf("PollOutputsContainer should have returned err")
 				}
 
 				return // continue to next test
 			}
 
 			if err != nil {
-				t.Errorf("RemoveContainer returned err: %v", err)
+				t.Errorf("PollOutputsContainer returned err: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("PollOutputsContainer is %s, want %s", got, test.want)
 			}
 		})
 	}
@@ -236,6 +528,8 @@ func TestKubernetes_SetupContainer(t *testing.T) {
 		opts             []ClientOpt
 		wantPrivileged   bool
 		wantFromTemplate any
+		wantCPU          int64
+		wantMemory       int64
 	}{
 		{
 			name:             "step-clone",
@@ -294,6 +588,46 @@ func TestKubernetes_SetupContainer(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:           "default resource limits",
+			failure:        false,
+			container:      _container,
+			opts:           []ClientOpt{WithDefaultResources("1", "1g")},
+			wantPrivileged: false,
+			wantCPU:        1000,
+			wantMemory:     1024 * 1024 * 1024,
+		},
+		{
+			name:    "resource limits from environment capped at maximum",
+			failure: false,
+			container: &pipeline.Container{
+				ID:          "step_github_octocat_1_echo",
+				Commands:    []string{"echo", "hello"},
+				Directory:   "/vela/src/github.com/octocat/helloworld",
+				Environment: map[string]string{"VELA_CPU_LIMIT": "8", "VELA_MEMORY_LIMIT": "512m"},
+				Image:       "alpine:latest",
+				Name:        "echo",
+				Number:      2,
+				Pull:        "always",
+			},
+			opts:           []ClientOpt{WithDefaultResources("1", "1g"), WithMaxResources("2", "4g")},
+			wantPrivileged: false,
+			wantCPU:        2000,
+			wantMemory:     512 * 1024 * 1024,
+		},
+		{
+			name:    "invalid resource limits from environment",
+			failure: true,
+			container: &pipeline.Container{
+				ID:          "step_github_octocat_1_echo",
+				Directory:   "/vela/src/github.com/octocat/helloworld",
+				Environment: map[string]string{"VELA_CPU_LIMIT": "lots"},
+				Image:       "alpine:latest",
+				Name:        "echo",
+				Number:      2,
+				Pull:        "always",
+			},
+		},
 	}
 
 	// run tests
@@ -330,6 +664,11 @@ func TestKubernetes_SetupContainer(t *testing.T) {
 				t.Errorf("expected containersLookup[ctn.Name] to be %d, got %d", i, j)
 			}
 
+			// make sure the environment is set up before the pod is created
+			if _, ok := _engine.pendingEnvironment[ctn.Name]; !ok {
+				t.Errorf("expected pendingEnvironment to contain %s", ctn.Name)
+			}
+
 			// Make sure Container has Privileged configured correctly
 			if test.wantPrivileged {
 				if ctn.SecurityContext == nil {
@@ -343,6 +682,15 @@ func TestKubernetes_SetupContainer(t *testing.T) {
 				}
 			}
 
+			// Make sure Container has the resource limits configured correctly
+			if got := ctn.Resources.Limits.Cpu().MilliValue(); got != test.wantCPU {
+				t.Errorf("Pod.Containers[%v].Resources.Limits.Cpu is %v, want %v", i, got, test.wantCPU)
+			}
+
+			if got := ctn.Resources.Limits.Memory().Value(); got != test.wantMemory {
+				t.Errorf("Pod.Containers[%v].Resources.Limits.Memory is %v, want %v", i, got, test.wantMemory)
+			}
+
 			switch test.wantFromTemplate.(type) {
 			case velav1alpha1.PipelineContainerSecurityContext:
 				want := test.wantFromTemplate.(velav1alpha1.PipelineContainerSecurityContext)
@@ -490,6 +838,32 @@ func TestKubernetes_WaitContainer(t *testing.T) {
 			},
 			newPod: _pod,
 		},
+		{
+			name:      "container image could not be pulled",
+			failure:   true,
+			container: _container,
+			oldPod:    _pod,
+			newPod: &v1.Pod{
+				ObjectMeta: _pod.ObjectMeta,
+				TypeMeta:   _pod.TypeMeta,
+				Spec:       _pod.Spec,
+				Status: v1.PodStatus{
+					Phase: v1.PodRunning,
+					ContainerStatuses: []v1.ContainerStatus{
+						{
+							Name: "step-github-octocat-1-clone",
+							State: v1.ContainerState{
+								Waiting: &v1.ContainerStateWaiting{
+									Reason:  "ErrImagePull",
+									Message: "failed to pull image: i/o timeout",
+								},
+							},
+							Image: "target/vela-git:v0.4.0",
+						},
+					},
+				},
+			},
+		},
 		{
 			name:      "if client.Pod.Spec is empty podTracker fails",
 			failure:   true,
@@ -545,6 +919,7 @@ func Test_podTracker_inspectContainerStatuses(t *testing.T) {
 		trackedPod string
 		ctnName    string
 		terminated bool
+		failed     bool
 		pod        *v1.Pod
 	}{
 		{
@@ -590,6 +965,59 @@ func Test_podTracker_inspectContainerStatuses(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:       "container image could not be pulled",
+			trackedPod: "test/github-octocat-1",
+			ctnName:    "step-github-octocat-1-clone",
+			terminated: false,
+			failed:     true,
+			pod: &v1.Pod{
+				ObjectMeta: _pod.ObjectMeta,
+				TypeMeta:   _pod.TypeMeta,
+				Spec:       _pod.Spec,
+				Status: v1.PodStatus{
+					Phase: v1.PodRunning,
+					ContainerStatuses: []v1.ContainerStatus{
+						{
+							Name: "step-github-octocat-1-clone",
+							State: v1.ContainerState{
+								Waiting: &v1.ContainerStateWaiting{
+									Reason:  "ImagePullBackOff",
+									Message: "Back-off pulling image",
+								},
+							},
+							Image: "target/vela-git:v0.4.0",
+						},
+					},
+				},
+			},
+		},
+		{
+			name:       "container is starting the pause image",
+			trackedPod: "test/github-octocat-1",
+			ctnName:    "step-github-octocat-1-clone",
+			terminated: false,
+			failed:     false,
+			pod: &v1.Pod{
+				ObjectMeta: _pod.ObjectMeta,
+				TypeMeta:   _pod.TypeMeta,
+				Spec:       _pod.Spec,
+				Status: v1.PodStatus{
+					Phase: v1.PodRunning,
+					ContainerStatuses: []v1.ContainerStatus{
+						{
+							Name: "step-github-octocat-1-clone",
+							State: v1.ContainerState{
+								Waiting: &v1.ContainerStateWaiting{
+									Reason: "ErrImagePull",
+								},
+							},
+							Image: pauseImage,
+						},
+					},
+				},
+			},
+		},
 		{
 			name:       "pod has an untracked container",
 			trackedPod: "test/github-octocat-1",
@@ -627,6 +1055,7 @@ func Test_podTracker_inspectContainerStatuses(t *testing.T) {
 			ctnTracker := containerTracker{
 				Name:       test.ctnName,
 				Terminated: make(chan struct{}),
+				Failed:     make(chan struct{}),
 			}
 			podTracker := podTracker{
 				Logger:     logger,
@@ -652,6 +1081,83 @@ func Test_podTracker_inspectContainerStatuses(t *testing.T) {
 					t.Error("inspectContainerStatuses should have signaled termination")
 				}
 			}()
+
+			func() {
+				defer func() {
+					//nolint:errcheck // repeat close() panics (otherwise it won't)
+					recover()
+				}()
+
+				close(ctnTracker.Failed)
+
+				// this will only run if close() did not panic
+				if test.failed {
+					t.Error("inspectContainerStatuses should have signaled failure")
+				}
+			}()
+		})
+	}
+}
+
+func Test_imagePullError(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		waiting *v1.ContainerStateWaiting
+		want    bool
+	}{
+		{
+			name: "registry timed out",
+			waiting: &v1.ContainerStateWaiting{
+				Reason:  "ErrImagePull",
+				Message: "failed to pull image: dial tcp: i/o timeout",
+			},
+			want: true,
+		},
+		{
+			name: "backing off",
+			waiting: &v1.ContainerStateWaiting{
+				Reason:  "ImagePullBackOff",
+				Message: "Back-off pulling image",
+			},
+			want: true,
+		},
+		{
+			name: "image not found",
+			waiting: &v1.ContainerStateWaiting{
+				Reason:  "ErrImagePull",
+				Message: "failed to pull image: manifest for alpine:foo not found",
+			},
+			want: false,
+		},
+		{
+			name: "invalid image",
+			waiting: &v1.ContainerStateWaiting{
+				Reason:  "InvalidImageName",
+				Message: "couldn't parse image reference",
+			},
+			want: false,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := imagePullError(v1.ContainerStatus{
+				Name:  "step-github-octocat-1-clone",
+				Image: "target/vela-git:v0.4.0",
+				State: v1.ContainerState{Waiting: test.waiting},
+			})
+
+			if err == nil {
+				t.Errorf("imagePullError should have returned err")
+			}
+
+			got := infra.Is(err)
+
+			if got != test.want {
+				t.Errorf("imagePullError infrastructure failure is %v, want %v", got, test.want)
+			}
 		})
 	}
 }
@@ -2,9 +2,28 @@
 
 package kubernetes
 
-import "github.com/go-vela/server/constants"
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/go-vela/server/constants"
+)
 
 // Driver outputs the configured runtime driver.
 func (c *client) Driver() string {
 	return constants.DriverKubernetes
 }
+
+// Ping verifies the Kubernetes API is reachable
+// and pods can be listed in the configured namespace.
+func (c *client) Ping(ctx context.Context) error {
+	c.Logger.Trace("pinging kubernetes api")
+
+	// send API call to list a pod in the namespace
+	//
+	// https://pkg.go.dev/k8s.io/client-go/kubernetes/typed/core/v1#PodInterface
+	_, err := c.Kubernetes.CoreV1().Pods(c.config.Namespace).List(ctx, metav1.ListOptions{Limit: 1})
+
+	return err
+}
@@ -3,6 +3,7 @@
 package kubernetes
 
 import (
+	"context"
 	"reflect"
 	"testing"
 
@@ -25,3 +26,17 @@ func TestKubernetes_Driver(t *testing.T) {
 		t.Errorf("Driver is %v, want %v", got, want)
 	}
 }
+
+func TestKubernetes_Ping(t *testing.T) {
+	// setup types
+	_engine, err := NewMock(_pod)
+	if err != nil {
+		t.Errorf("unable to create runtime engine: %v", err)
+	}
+
+	// run test
+	err = _engine.Ping(context.Background())
+	if err != nil {
+		t.Errorf("Ping returned err: %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-vela/server/compiler/types/pipeline"
+	"github.com/go-vela/server/constants"
+)
+
+// envDir represents the directory in the workspace volume
+// of the pod for the environment files of the containers.
+var envDir = path.Join(constants.WorkspaceMount, ".env")
+
+// envKey represents the pattern for the environment keys
+// that are able to be exported from an environment file.
+var envKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// envFile is a helper function to return the path
+// to the environment file for the container.
+func envFile(ctn *pipeline.Container) string {
+	return path.Join(envDir, ctn.ID)
+}
+
+// sourcesEnvironment is a helper function to return true if
+// the container runs commands with a shell that is able to
+// source the environment file for the container.
+func sourcesEnvironment(ctn *pipeline.Container) bool {
+	return slices.Equal(ctn.Entrypoint, []string{"/bin/sh", "-c"}) && len(ctn.Commands) > 0
+}
+
+// sourceEnvironment is a helper function to prefix the commands for the
+// container with sourcing (and then removing) the environment file for
+// the container if it was written before the container started.
+func sourceEnvironment(ctn *pipeline.Container, commands []string) []string {
+	if !sourcesEnvironment(ctn) {
+		return commands
+	}
+
+	commands = slices.Clone(commands)
+
+	commands[0] = fmt.Sprintf("if [ -f %[1]s ]; then . %[1]s; rm -f %[1]s; fi; %[2]s", envFile(ctn), commands[0])
+
+	return commands
+}
+
+// writeEnvironment writes the environment for the container that changed
+// since the pod was created (i.e. outputs from previous steps) to the
+// environment file for the container in the workspace volume of the pod.
+//
+// After creation, the image is the only container field we can edit in
+// kubernetes. So, the file is written using the outputs container and
+// sourced by the commands for the container when it starts. Containers
+// that don't run commands with a shell are unable to receive the changes.
+func (c *client) writeEnvironment(ctx context.Context, ctn *pipeline.Container) error {
+	index, ok := c.containersLookup[ctn.ID]
+	if !ok {
+		return nil
+	}
+
+	// capture the environment for the container when the pod was created
+	created := make(map[string]string, len(c.Pod.Spec.Containers[index].Env))
+
+	for _, env := range c.Pod.Spec.Containers[index].Env {
+		created[env.Name] = env.Value
+	}
+
+	changed := []string{}
+
+	for k, v := range ctn.Environment {
+		value, ok := created[k]
+		if !ok || value != v {
+			changed = append(changed, k)
+		}
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+
+	slices.Sort(changed)
+
+	if !sourcesEnvironment(ctn) {
+		c.Logger.Warnf("unable to update environment %v for container %s: only containers running commands are able to receive changes to the environment", changed, ctn.ID)
+
+		return nil
+	}
+
+	c.mutex.Lock()
+	outputsCtn := c.outputsCtn
+	c.mutex.Unlock()
+
+	// check if the outputs container is running to write the file
+	if len(outputsCtn) == 0 {
+		c.Logger.Warnf("unable to update environment %v for container %s: no outputs container is running", changed, ctn.ID)
+
+		return nil
+	}
+
+	env := new(bytes.Buffer)
+
+	for _, k := range changed {
+		if !envKey.MatchString(k) {
+			c.Logger.Warnf("unable to update environment %s for container %s: invalid environment key", k, ctn.ID)
+
+			continue
+		}
+
+		fmt.Fprintf(env, "export %s='%s'\n", k, strings.ReplaceAll(ctn.Environment[k], "'", `'\''`))
+	}
+
+	c.Logger.Tracef("writing environment %v for container %s", changed, ctn.ID)
+
+	// the file may contain secrets, so only allow the owner to read it
+	cmd := []string{"sh", "-c", `umask 077 && mkdir -p "$(dirname "$0")" && cat > "$0"`, envFile(ctn)}
+
+	stderr := new(bytes.Buffer)
+
+	err := c.podExec.Exec(ctx, c.config.Namespace, c.Pod.Name, outputsCtn, cmd, env, io.Discard, stderr)
+	if err != nil {
+		return fmt.Errorf("unable to write environment for container %s: %w: %s", ctn.ID, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
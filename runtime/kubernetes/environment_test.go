@@ -0,0 +1,154 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package kubernetes
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/go-vela/server/compiler/types/pipeline"
+)
+
+func TestKubernetes_sourceEnvironment(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name      string
+		container *pipeline.Container
+		want      []string
+	}{
+		{
+			name: "commands",
+			container: &pipeline.Container{
+				ID:         "step-github-octocat-1-echo",
+				Entrypoint: []string{"/bin/sh", "-c"},
+				Commands:   []string{"echo $VELA_BUILD_SCRIPT | base64 -d | /bin/sh -e"},
+			},
+			want: []string{
+				"if [ -f /vela/.env/step-github-octocat-1-echo ]; then . /vela/.env/step-github-octocat-1-echo; rm -f /vela/.env/step-github-octocat-1-echo; fi; echo $VELA_BUILD_SCRIPT | base64 -d | /bin/sh -e",
+			},
+		},
+		{
+			name: "custom entrypoint",
+			container: &pipeline.Container{
+				ID:         "step-github-octocat-1-echo",
+				Entrypoint: []string{"/usr/bin/env"},
+				Commands:   []string{"echo"},
+			},
+			want: []string{"echo"},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := sourceEnvironment(test.container, test.container.Commands)
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("sourceEnvironment is %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestKubernetes_writeEnvironment(t *testing.T) {
+	// setup types
+	_commands := &pipeline.Container{
+		ID:          "step-github-octocat-1-echo",
+		Entrypoint:  []string{"/bin/sh", "-c"},
+		Commands:    []string{"echo $VELA_BUILD_SCRIPT | base64 -d | /bin/sh -e"},
+		Environment: map[string]string{"FOO": "bar", "OUTPUT": "it's", "BAR": "baz"},
+	}
+
+	_plugin := &pipeline.Container{
+		ID:          "step-github-octocat-1-echo",
+		Environment: map[string]string{"FOO": "bar", "OUTPUT": "it's"},
+	}
+
+	// setup tests
+	tests := []struct {
+		name       string
+		failure    bool
+		container  *pipeline.Container
+		outputsCtn string
+		want       []byte
+	}{
+		{
+			name:       "changed environment",
+			failure:    false,
+			container:  _commands,
+			outputsCtn: "service-github-octocat-1-postgres",
+			want:       []byte("export BAR='baz'\nexport OUTPUT='it'\\''s'\n"),
+		},
+		{
+			name:    "unchanged environment",
+			failure: false,
+			container: &pipeline.Container{
+				ID:          "step-github-octocat-1-echo",
+				Entrypoint:  []string{"/bin/sh", "-c"},
+				Commands:    []string{"echo $VELA_BUILD_SCRIPT | base64 -d | /bin/sh -e"},
+				Environment: map[string]string{"FOO": "bar"},
+			},
+			outputsCtn: "service-github-octocat-1-postgres",
+			want:       nil,
+		},
+		{
+			name:       "plugin",
+			failure:    false,
+			container:  _plugin,
+			outputsCtn: "service-github-octocat-1-postgres",
+			want:       nil,
+		},
+		{
+			name:       "no outputs container",
+			failure:    false,
+			container:  _commands,
+			outputsCtn: "",
+			want:       nil,
+		},
+		{
+			name:       "outputs container not found",
+			failure:    true,
+			container:  _commands,
+			outputsCtn: "outputs-github-octocat-1",
+			want:       nil,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pod := _pod.DeepCopy()
+			pod.Spec.Containers[1].Env = []v1.EnvVar{{Name: "FOO", Value: "bar"}}
+
+			_engine, err := NewMock(pod)
+			if err != nil {
+				t.Errorf("unable to create runtime engine: %v", err)
+			}
+
+			_engine.outputsCtn = test.outputsCtn
+
+			err = _engine.writeEnvironment(context.Background(), test.container)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("writeEnvironment should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("writeEnvironment returned err: %v", err)
+			}
+
+			got := _engine.podExec.(*mockExecutor).files[envFile(test.container)]
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("writeEnvironment is %q, want %q", got, test.want)
+			}
+		})
+	}
+}
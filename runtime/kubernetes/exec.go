@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package kubernetes
+
+import (
+	"context"
+	"io"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// podExecutor represents the interface for running
+// commands inside a container of the pipeline pod.
+type podExecutor interface {
+	// Exec runs the command in the container of the pod with the
+	// optional stdin and writes the output to stdout and stderr.
+	Exec(ctx context.Context, namespace, pod, container string, cmd []string, stdin io.Reader, stdout, stderr io.Writer) error
+}
+
+// remoteExecutor implements the podExecutor interface
+// using the exec subresource for the pod.
+type remoteExecutor struct {
+	// https://pkg.go.dev/k8s.io/client-go/rest#Config
+	config *rest.Config
+	// https://pkg.go.dev/k8s.io/client-go/kubernetes#Interface
+	kubernetes kubernetes.Interface
+}
+
+// Exec runs the command in the container of the pod
+// using the exec subresource for the pod.
+func (r *remoteExecutor) Exec(ctx context.Context, namespace, pod, container string, cmd []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	// create the request for the exec subresource of the pod
+	//
+	// https://pkg.go.dev/k8s.io/client-go/rest#Request
+	req := r.kubernetes.CoreV1().RESTClient().
+		Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&v1.PodExecOptions{
+			Container: container,
+			Command:   cmd,
+			Stdin:     stdin != nil,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	// create the executor for streaming over websockets
+	//
+	// https://pkg.go.dev/k8s.io/client-go/tools/remotecommand#NewWebSocketExecutor
+	websocket, err := remotecommand.NewWebSocketExecutor(r.config, "GET", req.URL().String())
+	if err != nil {
+		return err
+	}
+
+	// create the executor for streaming over SPDY
+	//
+	// https://pkg.go.dev/k8s.io/client-go/tools/remotecommand#NewSPDYExecutor
+	spdy, err := remotecommand.NewSPDYExecutor(r.config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+
+	// fallback to SPDY for API servers that don't support websockets
+	//
+	// https://pkg.go.dev/k8s.io/client-go/tools/remotecommand#NewFallbackExecutor
+	executor, err := remotecommand.NewFallbackExecutor(websocket, spdy, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	})
+	if err != nil {
+		return err
+	}
+
+	// https://pkg.go.dev/k8s.io/client-go/tools/remotecommand#Executor
+	return executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+}
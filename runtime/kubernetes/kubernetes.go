@@ -3,16 +3,25 @@
 package kubernetes
 
 import (
+	"sync"
+	"time"
+
 	"github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
+	"github.com/go-vela/server/compiler/types/pipeline"
+	"github.com/go-vela/worker/internal/resource"
 	velav1alpha1 "github.com/go-vela/worker/runtime/kubernetes/apis/vela/v1alpha1"
 	velaK8sClient "github.com/go-vela/worker/runtime/kubernetes/generated/clientset/versioned"
 )
 
+// MaxOutputsSize represents the maximum size of
+// an outputs file captured from a container.
+const MaxOutputsSize = 10 * 1024 * 1024 // 10MB
+
 type config struct {
 	// specifies the config file to use for the Kubernetes client
 	File string
@@ -24,6 +33,14 @@ type config struct {
 	Volumes []string
 	// PipelinePodsTemplateName has the name of the PipelinePodTemplate to retrieve from the Namespace
 	PipelinePodsTemplateName string
+	// specifies the default resource limits for each Kubernetes container
+	DefaultLimits resource.Limits
+	// specifies the maximum resource limits for each Kubernetes container
+	MaxLimits resource.Limits
+	// specifies the time to wait for the containers in each Kubernetes pod to stop before they are killed
+	StopGracePeriod time.Duration
+	// specifies the time to wait for each Kubernetes pod to be scheduled and started
+	StartTimeout time.Duration
 }
 
 type client struct {
@@ -38,6 +55,9 @@ type client struct {
 	Pod *v1.Pod
 	// containersLookup maps the container name to its index in Containers
 	containersLookup map[string]int
+	// pendingEnvironment maps the container name to the containers
+	// awaiting the setup of their environment before pod creation
+	pendingEnvironment map[string]*pipeline.Container
 	// PodTracker wraps the Kubernetes client to simplify watching the pod for changes
 	PodTracker *podTracker
 	// PipelinePodTemplate has default values to be used in Setup* methods
@@ -46,6 +66,14 @@ type client struct {
 	commonVolumeMounts []v1.VolumeMount
 	// indicates when the pod has been created in kubernetes
 	createdPod bool
+	// clients are the Kubernetes API clients shared across runtime clients
+	clients *Clients
+	// podExec runs commands inside containers of the pod
+	podExec podExecutor
+	// outputsCtn is the name of the container the outputs are polled from
+	outputsCtn string
+	// mutex guards the name of the outputs container
+	mutex sync.Mutex
 }
 
 // New returns an Engine implementation that
@@ -60,6 +88,7 @@ func New(opts ...ClientOpt) (*client, error) {
 	c.config = new(config)
 	c.Pod = new(v1.Pod)
 	c.containersLookup = map[string]int{}
+	c.pendingEnvironment = map[string]*pipeline.Container{}
 
 	// create new logger for the client
 	//
@@ -79,6 +108,50 @@ func New(opts ...ClientOpt) (*client, error) {
 		}
 	}
 
+	// check if shared Kubernetes clients were not provided
+	if c.clients == nil {
+		// create new Kubernetes clients from configuration
+		clients, err := NewClients(c.config.File)
+		if err != nil {
+			if c.config.File == "" {
+				c.Logger.Error("VELA_RUNTIME_CONFIG not defined and failed to create kubernetes InClusterConfig!")
+			}
+
+			return nil, err
+		}
+
+		c.clients = clients
+	}
+
+	// set the Kubernetes client in the runtime client
+	c.Kubernetes = c.clients.Kubernetes
+
+	// set the pod executor in the runtime client
+	c.podExec = &remoteExecutor{
+		config:     c.clients.Config,
+		kubernetes: c.clients.Kubernetes,
+	}
+
+	// set the VelaKubernetes client in the runtime client
+	c.VelaKubernetes = c.clients.VelaKubernetes
+
+	return c, nil
+}
+
+// Clients represents the Kubernetes API clients that are
+// safe to share across the runtime clients for builds.
+type Clients struct {
+	// https://pkg.go.dev/k8s.io/client-go/rest#Config
+	Config *rest.Config
+	// https://pkg.go.dev/k8s.io/client-go/kubernetes#Interface
+	Kubernetes kubernetes.Interface
+	// VelaKubernetes is a client for custom Vela CRD-based APIs
+	VelaKubernetes velaK8sClient.Interface
+}
+
+// NewClients returns the Kubernetes API clients created from the
+// provided kubeconfig file or the in cluster config if no file is provided.
+func NewClients(file string) (*Clients, error) {
 	// use the current context in kubeconfig
 	//
 	// when no kube config is provided create InClusterConfig
@@ -88,16 +161,15 @@ func New(opts ...ClientOpt) (*client, error) {
 		err    error
 	)
 
-	if c.config.File == "" {
+	if file == "" {
 		// https://pkg.go.dev/k8s.io/client-go/rest#InClusterConfig
 		config, err = rest.InClusterConfig()
 		if err != nil {
-			c.Logger.Error("VELA_RUNTIME_CONFIG not defined and failed to create kubernetes InClusterConfig!")
 			return nil, err
 		}
 	} else {
 		// https://pkg.go.dev/k8s.io/client-go/tools/clientcmd#BuildConfigFromFlags
-		config, err = clientcmd.BuildConfigFromFlags("", c.config.File)
+		config, err = clientcmd.BuildConfigFromFlags("", file)
 		if err != nil {
 			return nil, err
 		}
@@ -111,17 +183,15 @@ func New(opts ...ClientOpt) (*client, error) {
 		return nil, err
 	}
 
-	// set the Kubernetes client in the runtime client
-	c.Kubernetes = _kubernetes
-
 	// creates VelaKubernetes client from configuration
 	_velaKubernetes, err := velaK8sClient.NewForConfig(config)
 	if err != nil {
 		return nil, err
 	}
 
-	// set the VelaKubernetes client in the runtime client
-	c.VelaKubernetes = _velaKubernetes
-
-	return c, nil
+	return &Clients{
+		Config:         config,
+		Kubernetes:     _kubernetes,
+		VelaKubernetes: _velaKubernetes,
+	}, nil
 }
@@ -321,3 +321,46 @@ var (
 		},
 	}
 )
+
+func TestKubernetes_NewClients(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		failure bool
+		path    string
+	}{
+		{
+			name:    "valid config file",
+			failure: false,
+			path:    "testdata/config",
+		},
+		{
+			name:    "missing config file",
+			failure: true,
+			path:    "testdata/config_missing",
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := NewClients(test.path)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("NewClients should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("NewClients returned err: %v", err)
+			}
+
+			if got.Config == nil || got.Kubernetes == nil || got.VelaKubernetes == nil {
+				t.Errorf("NewClients is %v, want all clients", got)
+			}
+		})
+	}
+}
@@ -7,13 +7,23 @@ package kubernetes
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/exec"
 
+	"github.com/go-vela/server/compiler/types/pipeline"
 	velav1alpha1 "github.com/go-vela/worker/runtime/kubernetes/apis/vela/v1alpha1"
 	fakeVelaK8sClient "github.com/go-vela/worker/runtime/kubernetes/generated/clientset/versioned/fake"
 )
@@ -33,6 +43,7 @@ func NewMock(_pod *v1.Pod, opts ...ClientOpt) (*client, error) {
 	c.Pod = new(v1.Pod)
 
 	c.containersLookup = map[string]int{}
+	c.pendingEnvironment = map[string]*pipeline.Container{}
 	for i, ctn := range _pod.Spec.Containers {
 		c.containersLookup[ctn.Name] = i
 	}
@@ -67,6 +78,12 @@ func NewMock(_pod *v1.Pod, opts ...ClientOpt) (*client, error) {
 	// https://pkg.go.dev/k8s.io/client-go/kubernetes/fake#NewSimpleClientset
 	c.Kubernetes = fake.NewSimpleClientset(c.Pod)
 
+	// set the mock pod executor in the runtime client
+	c.podExec = &mockExecutor{
+		kubernetes: c.Kubernetes,
+		files:      make(map[string][]byte),
+	}
+
 	// set the VelaKubernetes fake client in the runtime client
 	c.VelaKubernetes = fakeVelaK8sClient.NewSimpleClientset(
 		&velav1alpha1.PipelinePodsTemplate{
@@ -102,6 +119,7 @@ type MockKubernetesRuntime interface {
 	WaitForPodCreate(string, string)
 	SimulateResync(*v1.Pod)
 	SimulateStatusUpdate(*v1.Pod, []v1.ContainerStatus) error
+	SimulateFile(string, []byte)
 }
 
 // SetupMock allows the Kubernetes runtime to perform additional Mock-related config.
@@ -205,3 +223,96 @@ func (c *client) SimulateStatusUpdate(pod *v1.Pod, containerStatuses []v1.Contai
 
 	return err
 }
+
+// SimulateFile simulates a file written to the shared workspace volume of the pod.
+//
+// This function is intended for running tests only.
+func (c *client) SimulateFile(path string, content []byte) {
+	if m, ok := c.podExec.(*mockExecutor); ok {
+		m.Lock()
+		defer m.Unlock()
+
+		m.files[path] = content
+	}
+}
+
+// mockExecutor implements the podExecutor interface by
+// serving the files simulated in the shared workspace
+// volume for containers that exist in the pod.
+//
+// This is intended for running tests only.
+type mockExecutor struct {
+	sync.Mutex
+
+	// https://pkg.go.dev/k8s.io/client-go/kubernetes#Interface
+	kubernetes kubernetes.Interface
+	// files maps the path to the content of each simulated file
+	files map[string][]byte
+}
+
+// Exec simulates running the cat, find and sh (writing stdin
+// to a file) commands in the container of the pod.
+//
+// This function is intended for running tests only.
+func (m *mockExecutor) Exec(ctx context.Context, namespace, pod, container string, cmd []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	// verify the pod exists in the fake clientset
+	_pod, err := m.kubernetes.CoreV1().Pods(namespace).Get(ctx, pod, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	// verify the container exists in the pod
+	if !slices.ContainsFunc(_pod.Spec.Containers, func(ctn v1.Container) bool {
+		return strings.EqualFold(ctn.Name, container)
+	}) {
+		return fmt.Errorf("container %s not found in pod %s", container, pod)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	switch {
+	case len(cmd) == 2 && cmd[0] == "cat":
+		content, ok := m.files[cmd[1]]
+		if !ok {
+			fmt.Fprintf(stderr, "cat: can't open '%s': No such file or directory\n", cmd[1])
+
+			return exec.CodeExitError{Err: fmt.Errorf("command terminated with exit code 1"), Code: 1}
+		}
+
+		_, err = stdout.Write(content)
+
+		return err
+	case len(cmd) == 7 && cmd[0] == "find":
+		// convert the find -path pattern to a regular expression
+		pattern := regexp.QuoteMeta(cmd[5])
+		pattern = strings.ReplaceAll(pattern, `\*`, ".*")
+		pattern = strings.ReplaceAll(pattern, `\?`, ".")
+
+		re, err := regexp.Compile("^" + pattern + "$")
+		if err != nil {
+			return err
+		}
+
+		for _, path := range slices.Sorted(maps.Keys(m.files)) {
+			if strings.HasPrefix(path, cmd[1]+"/") && re.MatchString(path) {
+				fmt.Fprintln(stdout, path)
+			}
+		}
+
+		return nil
+	case len(cmd) == 4 && cmd[0] == "sh" && stdin != nil:
+		content, err := io.ReadAll(stdin)
+		if err != nil {
+			return err
+		}
+
+		m.files[cmd[3]] = content
+
+		return nil
+	}
+
+	fmt.Fprintf(stderr, "%s: not found\n", cmd[0])
+
+	return exec.CodeExitError{Err: fmt.Errorf("command terminated with exit code 127"), Code: 127}
+}
@@ -5,6 +5,7 @@ package kubernetes
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	// The k8s libraries have some quirks around yaml marshaling.
@@ -12,6 +13,7 @@ import (
 	// So, we need to use "sigs.k8s.io/yaml" instead of "github.com/buildkite/yaml".
 	"sigs.k8s.io/yaml"
 
+	"github.com/go-vela/worker/internal/resource"
 	velav1alpha1 "github.com/go-vela/worker/runtime/kubernetes/apis/vela/v1alpha1"
 )
 
@@ -30,6 +32,18 @@ func WithConfigFile(file string) ClientOpt {
 	}
 }
 
+// WithClients sets the shared API clients in the runtime client for Kubernetes.
+func WithClients(clients *Clients) ClientOpt {
+	return func(c *client) error {
+		c.Logger.Trace("configuring shared clients in kubernetes runtime client")
+
+		// set the Kubernetes API clients in the kubernetes client
+		c.clients = clients
+
+		return nil
+	}
+}
+
 // WithHostVolumes sets the host volumes in the runtime client for Kubernetes.
 func WithHostVolumes(volumes []string) ClientOpt {
 	return func(c *client) error {
@@ -120,3 +134,79 @@ func WithPrivilegedImages(images []string) ClientOpt {
 		return nil
 	}
 }
+
+// WithDefaultResources sets the default resource limits in the runtime client for Kubernetes.
+func WithDefaultResources(cpu, memory string) ClientOpt {
+	return func(c *client) error {
+		c.Logger.Trace("configuring default resource limits in kubernetes runtime client")
+
+		// parse the default resource limits
+		//
+		// https://pkg.go.dev/github.com/go-vela/worker/internal/resource#Parse
+		limits, err := resource.Parse(cpu, memory)
+		if err != nil {
+			return err
+		}
+
+		// set the runtime default resource limits in the kubernetes client
+		c.config.DefaultLimits = limits
+
+		return nil
+	}
+}
+
+// WithMaxResources sets the maximum resource limits in the runtime client for Kubernetes.
+func WithMaxResources(cpu, memory string) ClientOpt {
+	return func(c *client) error {
+		c.Logger.Trace("configuring maximum resource limits in kubernetes runtime client")
+
+		// parse the maximum resource limits
+		//
+		// https://pkg.go.dev/github.com/go-vela/worker/internal/resource#Parse
+		limits, err := resource.Parse(cpu, memory)
+		if err != nil {
+			return err
+		}
+
+		// set the runtime maximum resource limits in the kubernetes client
+		c.config.MaxLimits = limits
+
+		return nil
+	}
+}
+
+// WithStopGracePeriod sets the time to wait for the containers in
+// the pod to stop before they are killed in the runtime client for Kubernetes.
+func WithStopGracePeriod(period time.Duration) ClientOpt {
+	return func(c *client) error {
+		c.Logger.Trace("configuring stop grace period in kubernetes runtime client")
+
+		// check if the stop grace period provided is negative
+		if period < 0 {
+			return fmt.Errorf("invalid stop grace period provided: %s", period)
+		}
+
+		// set the runtime stop grace period in the kubernetes client
+		c.config.StopGracePeriod = period
+
+		return nil
+	}
+}
+
+// WithStartTimeout sets the time to wait for the pod to be
+// scheduled and started in the runtime client for Kubernetes.
+func WithStartTimeout(timeout time.Duration) ClientOpt {
+	return func(c *client) error {
+		c.Logger.Trace("configuring start timeout in kubernetes runtime client")
+
+		// check if the start timeout provided is negative
+		if timeout < 0 {
+			return fmt.Errorf("invalid start timeout provided: %s", timeout)
+		}
+
+		// set the runtime start timeout in the kubernetes client
+		c.config.StartTimeout = timeout
+
+		return nil
+	}
+}
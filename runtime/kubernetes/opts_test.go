@@ -5,6 +5,7 @@ package kubernetes
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -343,3 +344,135 @@ func TestKubernetes_ClientOpt_WithPodsTemplate(t *testing.T) {
 		})
 	}
 }
+
+func TestKubernetes_ClientOpt_WithClients(t *testing.T) {
+	// setup types
+	clients, err := NewClients("testdata/config")
+	if err != nil {
+		t.Errorf("NewClients returned err: %v", err)
+	}
+
+	// run test
+	_engine, err := New(
+		WithClients(clients),
+	)
+	if err != nil {
+		t.Errorf("WithClients returned err: %v", err)
+	}
+
+	if !reflect.DeepEqual(_engine.clients, clients) {
+		t.Errorf("WithClients is %v, want %v", _engine.clients, clients)
+	}
+
+	if !reflect.DeepEqual(_engine.Kubernetes, clients.Kubernetes) {
+		t.Errorf("WithClients Kubernetes is %v, want %v", _engine.Kubernetes, clients.Kubernetes)
+	}
+}
+
+func TestKubernetes_ClientOpt_WithStopGracePeriod(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		failure bool
+		period  time.Duration
+		want    time.Duration
+	}{
+		{
+			name:    "defined",
+			failure: false,
+			period:  30 * time.Second,
+			want:    30 * time.Second,
+		},
+		{
+			name:    "empty",
+			failure: false,
+			period:  0,
+			want:    0,
+		},
+		{
+			name:    "negative",
+			failure: true,
+			period:  -time.Second,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_engine, err := New(
+				WithConfigFile("testdata/config"),
+				WithStopGracePeriod(test.period),
+			)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("WithStopGracePeriod should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("WithStopGracePeriod returned err: %v", err)
+			}
+
+			if _engine.config.StopGracePeriod != test.want {
+				t.Errorf("WithStopGracePeriod is %v, want %v", _engine.config.StopGracePeriod, test.want)
+			}
+		})
+	}
+}
+
+func TestKubernetes_ClientOpt_WithStartTimeout(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		failure bool
+		timeout time.Duration
+		want    time.Duration
+	}{
+		{
+			name:    "defined",
+			failure: false,
+			timeout: 5 * time.Minute,
+			want:    5 * time.Minute,
+		},
+		{
+			name:    "empty",
+			failure: false,
+			timeout: 0,
+			want:    0,
+		},
+		{
+			name:    "negative",
+			failure: true,
+			timeout: -time.Second,
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_engine, err := New(
+				WithConfigFile("testdata/config"),
+				WithStartTimeout(test.timeout),
+			)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("WithStartTimeout should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("WithStartTimeout returned err: %v", err)
+			}
+
+			if _engine.config.StartTimeout != test.want {
+				t.Errorf("WithStartTimeout is %v, want %v", _engine.config.StartTimeout, test.want)
+			}
+		})
+	}
+}
@@ -29,6 +29,12 @@ type containerTracker struct {
 	terminatedOnce sync.Once
 	// Terminated will be closed once the container reaches a terminal state.
 	Terminated chan struct{}
+	// failedOnce ensures that the Failed channel only gets closed once.
+	failedOnce sync.Once
+	// Failed will be closed once the container fails to start (i.e. the image could not be pulled).
+	Failed chan struct{}
+	// Err is the reason the container failed to start, which is set before Failed is closed.
+	Err error
 	// TODO: collect streaming logs here before TailContainer is called
 }
 
@@ -173,6 +179,7 @@ func (p *podTracker) TrackContainers(containers []v1.Container) {
 		p.Containers[ctn.Name] = &containerTracker{
 			Name:       ctn.Name,
 			Terminated: make(chan struct{}),
+			Failed:     make(chan struct{}),
 		}
 	}
 }
@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"sync"
+
+	mobyClient "github.com/moby/moby/client"
+	"github.com/sirupsen/logrus"
+
+	"github.com/go-vela/server/constants"
+	"github.com/go-vela/worker/internal/registry"
+	"github.com/go-vela/worker/runtime/docker"
+	"github.com/go-vela/worker/runtime/kubernetes"
+)
+
+// Pool represents the connections to the runtime environments
+// shared safely across the builds executed concurrently.
+//
+// Each build still receives its own Vela engine since the
+// engines hold state for the pipeline they are executing.
+type Pool struct {
+	mu sync.Mutex
+	// shared Docker API client
+	docker mobyClient.APIClient
+	// shared Kubernetes API clients by config file
+	kubernetes map[string]*kubernetes.Clients
+	// shared registry credentials for Docker
+	registry *registry.Credentials
+}
+
+// NewPool returns a Pool without any connections to
+// the runtime environments which are created on demand.
+func NewPool() *Pool {
+	return &Pool{
+		kubernetes: make(map[string]*kubernetes.Clients),
+	}
+}
+
+// New creates and returns a Vela engine for a build capable of
+// integrating with the configured runtime using the connections
+// shared by the pool.
+func (p *Pool) New(s *Setup) (Engine, error) {
+	// validate the setup being provided
+	//
+	// https://pkg.go.dev/github.com/go-vela/worker/runtime#Setup.Validate
+	err := s.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	// copy the setup to avoid modifying the one provided
+	setup := *s
+
+	// mock engines create their own clients
+	if !setup.Mock {
+		err = p.share(&setup)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// https://pkg.go.dev/github.com/go-vela/worker/runtime#New
+	return New(&setup)
+}
+
+// LoadRegistryAuth reads the registry credentials from the provided
+// files for the Docker engines created by the pool, replacing any
+// credentials loaded before. Engines already created keep the
+// credentials they were created with.
+func (p *Pool) LoadRegistryAuth(dockerConfig, credentialsFile string) error {
+	// https://pkg.go.dev/github.com/go-vela/worker/internal/registry#Load
+	creds, err := registry.Load(dockerConfig, credentialsFile)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.registry = creds
+
+	return nil
+}
+
+// share is a helper function to attach the connections
+// for the runtime environment to the setup, creating
+// them on first use.
+func (p *Pool) share(s *Setup) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch s.Driver {
+	case constants.DriverDocker:
+		if p.docker == nil {
+			logrus.Debug("creating shared docker client for runtime pool")
+
+			// https://pkg.go.dev/github.com/go-vela/worker/runtime/docker#NewClient
+			_docker, err := docker.NewClient()
+			if err != nil {
+				return err
+			}
+
+			p.docker = _docker
+		}
+
+		// load the registry credentials on first use
+		// unless they were already loaded for the pool
+		if p.registry == nil {
+			// https://pkg.go.dev/github.com/go-vela/worker/internal/registry#Load
+			creds, err := registry.Load(s.DockerConfig, s.RegistryCredentials)
+			if err != nil {
+				return err
+			}
+
+			p.registry = creds
+		}
+
+		s.dockerClient = p.docker
+		s.registryAuth = p.registry
+	case constants.DriverKubernetes:
+		clients, ok := p.kubernetes[s.ConfigFile]
+		if !ok {
+			logrus.Debug("creating shared kubernetes clients for runtime pool")
+
+			// https://pkg.go.dev/github.com/go-vela/worker/runtime/kubernetes#NewClients
+			_clients, err := kubernetes.NewClients(s.ConfigFile)
+			if err != nil {
+				return err
+			}
+
+			p.kubernetes[s.ConfigFile] = _clients
+			clients = _clients
+		}
+
+		s.kubernetesClients = clients
+	}
+
+	return nil
+}
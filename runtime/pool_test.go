@@ -0,0 +1,197 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/go-vela/server/constants"
+)
+
+func TestRuntime_Pool_New(t *testing.T) {
+	// setup tests
+	tests := []struct {
+		name    string
+		failure bool
+		setup   *Setup
+		field   string
+	}{
+		{
+			name:    "docker driver",
+			failure: false,
+			setup: &Setup{
+				Driver: constants.DriverDocker,
+			},
+			field: "Docker",
+		},
+		{
+			name:    "kubernetes driver",
+			failure: false,
+			setup: &Setup{
+				Driver:     constants.DriverKubernetes,
+				Namespace:  "docker",
+				ConfigFile: "testdata/config",
+			},
+			field: "Kubernetes",
+		},
+		{
+			name:    "kubernetes driver-missing config",
+			failure: true,
+			setup: &Setup{
+				Driver:     constants.DriverKubernetes,
+				Namespace:  "docker",
+				ConfigFile: "testdata/config_missing",
+			},
+		},
+		{
+			name:    "invalid driver fails",
+			failure: true,
+			setup: &Setup{
+				Driver: "invalid",
+			},
+		},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := NewPool()
+
+			_engine, err := p.New(test.setup)
+
+			if test.failure {
+				if err == nil {
+					t.Errorf("New should have returned err")
+				}
+
+				return // continue to next test
+			}
+
+			if err != nil {
+				t.Errorf("New returned err: %v", err)
+			}
+
+			// ensure a second engine shares the client with the first
+			second, err := p.New(test.setup)
+			if err != nil {
+				t.Errorf("New returned err: %v", err)
+			}
+
+			if second == _engine {
+				t.Errorf("New returned the same engine for both builds")
+			}
+
+			got := reflect.ValueOf(second).Elem().FieldByName(test.field).Interface()
+			want := reflect.ValueOf(_engine).Elem().FieldByName(test.field).Interface()
+
+			if got != want {
+				t.Errorf("New %s is %v, want %v", test.field, got, want)
+			}
+		})
+	}
+}
+
+func TestRuntime_Pool_LoadRegistryAuth(t *testing.T) {
+	// setup types
+	p := NewPool()
+
+	setup := &Setup{
+		Driver: constants.DriverDocker,
+	}
+
+	// registryAuth is a helper function to capture
+	// the registry credentials for the engine
+	registryAuth := func(e Engine) uintptr {
+		return reflect.ValueOf(e).Elem().FieldByName("config").Elem().FieldByName("RegistryAuth").Pointer()
+	}
+
+	err := p.LoadRegistryAuth("", "testdata/registry_credentials.yml")
+	if err != nil {
+		t.Errorf("LoadRegistryAuth returned err: %v", err)
+	}
+
+	first, err := p.New(setup)
+	if err != nil {
+		t.Errorf("New returned err: %v", err)
+	}
+
+	second, err := p.New(setup)
+	if err != nil {
+		t.Errorf("New returned err: %v", err)
+	}
+
+	// ensure the engines share the credentials loaded for the pool
+	if registryAuth(first) != registryAuth(second) {
+		t.Errorf("New did not share the registry credentials between engines")
+	}
+
+	// reload the credentials
+	err = p.LoadRegistryAuth("", "testdata/registry_credentials.yml")
+	if err != nil {
+		t.Errorf("LoadRegistryAuth returned err: %v", err)
+	}
+
+	third, err := p.New(setup)
+	if err != nil {
+		t.Errorf("New returned err: %v", err)
+	}
+
+	if registryAuth(third) == registryAuth(first) {
+		t.Errorf("New did not use the reloaded registry credentials")
+	}
+
+	// ensure the credentials are kept when reloading fails
+	err = p.LoadRegistryAuth("", "testdata/registry_credentials_missing.yml")
+	if err == nil {
+		t.Errorf("LoadRegistryAuth should have returned err")
+	}
+
+	fourth, err := p.New(setup)
+	if err != nil {
+		t.Errorf("New returned err: %v", err)
+	}
+
+	if registryAuth(fourth) != registryAuth(third) {
+		t.Errorf("New did not keep the registry credentials after reloading failed")
+	}
+}
+
+// TestRuntime_Pool_Concurrent is intended to run with the race
+// detector to verify engines are safely created by concurrent builds.
+func TestRuntime_Pool_Concurrent(t *testing.T) {
+	// setup types
+	p := NewPool()
+
+	engines := make([]Engine, 10)
+
+	var wg sync.WaitGroup
+
+	for i := range engines {
+		wg.Go(func() {
+			_engine, err := p.New(&Setup{
+				Driver:      constants.DriverDocker,
+				BuildNumber: int64(i),
+			})
+			if err != nil {
+				t.Errorf("New returned err: %v", err)
+
+				return
+			}
+
+			engines[i] = _engine
+		})
+	}
+
+	wg.Wait()
+
+	for i := 1; i < len(engines); i++ {
+		got := reflect.ValueOf(engines[i]).Elem().FieldByName("Docker").Interface()
+		want := reflect.ValueOf(engines[0]).Elem().FieldByName("Docker").Interface()
+
+		if got != want {
+			t.Errorf("New engine %d Docker is %v, want %v", i, got, want)
+		}
+	}
+}
@@ -4,11 +4,15 @@ package runtime
 
 import (
 	"fmt"
+	"time"
 
+	mobyClient "github.com/moby/moby/client"
 	"github.com/sirupsen/logrus"
 	v1 "k8s.io/api/core/v1"
 
 	"github.com/go-vela/server/constants"
+	"github.com/go-vela/worker/internal/registry"
+	"github.com/go-vela/worker/internal/resource"
 	"github.com/go-vela/worker/runtime/docker"
 	"github.com/go-vela/worker/runtime/kubernetes"
 )
@@ -41,6 +45,41 @@ type Setup struct {
 	PrivilegedImages []string
 	// specifies a list of kernel capabilities to drop from container (only used by Docker)
 	DropCapabilities []string
+	// specifies the path to a docker config.json file with registry credentials (only used by Docker)
+	DockerConfig string
+	// specifies the path to a file with credentials per image registry (only used by Docker)
+	RegistryCredentials string
+	// specifies the default CPU limit for each container
+	DefaultCPULimit string
+	// specifies the default memory limit for each container
+	DefaultMemoryLimit string
+	// specifies the maximum CPU limit for each container
+	MaxCPULimit string
+	// specifies the maximum memory limit for each container
+	MaxMemoryLimit string
+	// specifies the interval to remove orphaned resources (only used by Docker)
+	JanitorInterval time.Duration
+	// specifies the minimum age of orphaned resources before they are removed (only used by Docker)
+	JanitorMaxAge time.Duration
+	// specifies the org for the repo of the build to label resources with (only used by Docker)
+	Org string
+	// specifies the full name of the repo of the build to label resources with (only used by Docker)
+	Repo string
+	// specifies the number of the build to label resources with (only used by Docker)
+	BuildNumber int64
+	// specifies the hostname of the worker to label resources with (only used by Docker)
+	Hostname string
+	// specifies the time to wait for containers to stop before they are killed
+	StopGracePeriod time.Duration
+	// specifies the time to wait for the pod for a build to start (only used by Kubernetes)
+	StartTimeout time.Duration
+
+	// shared Docker API client provided by the runtime pool
+	dockerClient mobyClient.APIClient
+	// registry credentials loaded by the runtime pool (only used by Docker)
+	registryAuth *registry.Credentials
+	// shared Kubernetes API clients provided by the runtime pool
+	kubernetesClients *kubernetes.Clients
 }
 
 // Docker creates and returns a Vela engine capable of
@@ -53,6 +92,22 @@ func (s *Setup) Docker() (Engine, error) {
 		docker.WithPrivilegedImages(s.PrivilegedImages),
 		docker.WithLogger(s.Logger),
 		docker.WithDropCapabilities(s.DropCapabilities),
+		docker.WithDefaultResources(s.DefaultCPULimit, s.DefaultMemoryLimit),
+		docker.WithMaxResources(s.MaxCPULimit, s.MaxMemoryLimit),
+		docker.WithBuildLabels(s.Org, s.Repo, s.BuildNumber, s.Hostname),
+		docker.WithStopGracePeriod(s.StopGracePeriod),
+	}
+
+	// check if registry credentials were loaded by the runtime pool
+	if s.registryAuth != nil {
+		opts = append(opts, docker.WithRegistryCredentials(s.registryAuth))
+	} else {
+		opts = append(opts, docker.WithRegistryAuth(s.DockerConfig, s.RegistryCredentials))
+	}
+
+	// check if a shared Docker API client was provided
+	if s.dockerClient != nil {
+		opts = append(opts, docker.WithClient(s.dockerClient))
 	}
 
 	if s.Mock {
@@ -68,6 +123,46 @@ func (s *Setup) Docker() (Engine, error) {
 	return docker.New(opts...)
 }
 
+// Janitor creates and returns a janitor capable of removing the orphaned
+// resources for pipelines not returned by active from the runtime environment.
+//
+// A nil janitor is returned if the runtime driver
+// is not Docker or no janitor interval was provided.
+func (s *Setup) Janitor(active func() []string) (*docker.Janitor, error) {
+	logrus.Trace("creating docker runtime janitor from setup")
+
+	if s.Driver != constants.DriverDocker || s.JanitorInterval <= 0 {
+		return nil, nil
+	}
+
+	opts := []docker.ClientOpt{
+		docker.WithLogger(s.Logger),
+		docker.WithBuildLabels("", "", 0, s.Hostname),
+	}
+
+	if s.Mock {
+		// create new mock Docker runtime client
+		//
+		// https://pkg.go.dev/github.com/go-vela/worker/runtime/docker#NewMock
+		_docker, err := docker.NewMock(opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return docker.NewJanitor(_docker, active, s.JanitorInterval, s.JanitorMaxAge)
+	}
+
+	// create new Docker runtime client
+	//
+	// https://pkg.go.dev/github.com/go-vela/worker/runtime/docker#New
+	_docker, err := docker.New(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return docker.NewJanitor(_docker, active, s.JanitorInterval, s.JanitorMaxAge)
+}
+
 // Kubernetes creates and returns a Vela engine capable of
 // integrating with a Kubernetes runtime environment.
 func (s *Setup) Kubernetes() (Engine, error) {
@@ -80,6 +175,15 @@ func (s *Setup) Kubernetes() (Engine, error) {
 		kubernetes.WithPodsTemplate(s.PodsTemplateName, s.PodsTemplateFile),
 		kubernetes.WithPrivilegedImages(s.PrivilegedImages),
 		kubernetes.WithLogger(s.Logger),
+		kubernetes.WithDefaultResources(s.DefaultCPULimit, s.DefaultMemoryLimit),
+		kubernetes.WithMaxResources(s.MaxCPULimit, s.MaxMemoryLimit),
+		kubernetes.WithStopGracePeriod(s.StopGracePeriod),
+		kubernetes.WithStartTimeout(s.StartTimeout),
+	}
+
+	// check if shared Kubernetes API clients were provided
+	if s.kubernetesClients != nil {
+		opts = append(opts, kubernetes.WithClients(s.kubernetesClients))
 	}
 
 	if s.Mock {
@@ -115,6 +219,43 @@ func (s *Setup) Validate() error {
 		}
 	}
 
+	// parse the default resource limits provided
+	defaults, err := resource.Parse(s.DefaultCPULimit, s.DefaultMemoryLimit)
+	if err != nil {
+		return fmt.Errorf("invalid default resource limits provided: %w", err)
+	}
+
+	// parse the maximum resource limits provided
+	limits, err := resource.Parse(s.MaxCPULimit, s.MaxMemoryLimit)
+	if err != nil {
+		return fmt.Errorf("invalid maximum resource limits provided: %w", err)
+	}
+
+	// check if the default CPU limit exceeds the maximum
+	if limits.CPU > 0 && defaults.CPU > limits.CPU {
+		return fmt.Errorf("default cpu limit %s exceeds maximum cpu limit %s", s.DefaultCPULimit, s.MaxCPULimit)
+	}
+
+	// check if the default memory limit exceeds the maximum
+	if limits.Memory > 0 && defaults.Memory > limits.Memory {
+		return fmt.Errorf("default memory limit %s exceeds maximum memory limit %s", s.DefaultMemoryLimit, s.MaxMemoryLimit)
+	}
+
+	// check if a janitor max age was provided for the janitor interval
+	if s.JanitorInterval > 0 && s.JanitorMaxAge <= 0 {
+		return fmt.Errorf("no runtime janitor max age provided")
+	}
+
+	// check if the stop grace period provided is negative
+	if s.StopGracePeriod < 0 {
+		return fmt.Errorf("invalid runtime stop grace period provided: %s", s.StopGracePeriod)
+	}
+
+	// check if the start timeout provided is negative
+	if s.StartTimeout < 0 {
+		return fmt.Errorf("invalid runtime start timeout provided: %s", s.StartTimeout)
+	}
+
 	// setup is valid
 	return nil
 }
@@ -4,6 +4,7 @@ package runtime
 
 import (
 	"testing"
+	"time"
 
 	"github.com/go-vela/server/constants"
 )
@@ -34,6 +35,41 @@ func TestRuntime_Setup_Docker(t *testing.T) {
 	}
 }
 
+func TestRuntime_Setup_Janitor(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   string
+		interval time.Duration
+		want     bool
+	}{
+		{name: "docker driver", driver: constants.DriverDocker, interval: time.Minute, want: true},
+		{name: "docker driver-disabled", driver: constants.DriverDocker, interval: 0, want: false},
+		{name: "kubernetes driver", driver: constants.DriverKubernetes, interval: time.Minute, want: false},
+	}
+
+	// run tests
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// setup types
+			_setup := &Setup{
+				Mock:            true,
+				Driver:          test.driver,
+				JanitorInterval: test.interval,
+				JanitorMaxAge:   time.Hour,
+			}
+
+			got, err := _setup.Janitor(func() []string { return nil })
+			if err != nil {
+				t.Errorf("Janitor returned err: %v", err)
+			}
+
+			if (got != nil) != test.want {
+				t.Errorf("Janitor is %v, want janitor %t", got, test.want)
+			}
+		})
+	}
+}
+
 func TestRuntime_Setup_Kubernetes(t *testing.T) {
 	tests := []struct {
 		name string
@@ -86,6 +122,85 @@ func TestRuntime_Validate(t *testing.T) {
 				Namespace: "docker",
 			},
 		},
+		{
+			name:    "docker driver with resource limits",
+			failure: false,
+			setup: &Setup{
+				Driver:             constants.DriverDocker,
+				DefaultCPULimit:    "1",
+				DefaultMemoryLimit: "1g",
+				MaxCPULimit:        "4",
+				MaxMemoryLimit:     "8g",
+			},
+		},
+		{
+			name:    "invalid default resource limits",
+			failure: true,
+			setup: &Setup{
+				Driver:          constants.DriverDocker,
+				DefaultCPULimit: "lots",
+			},
+		},
+		{
+			name:    "invalid maximum resource limits",
+			failure: true,
+			setup: &Setup{
+				Driver:         constants.DriverDocker,
+				MaxMemoryLimit: "lots",
+			},
+		},
+		{
+			name:    "default cpu limit exceeds maximum",
+			failure: true,
+			setup: &Setup{
+				Driver:          constants.DriverDocker,
+				DefaultCPULimit: "8",
+				MaxCPULimit:     "4",
+			},
+		},
+		{
+			name:    "default memory limit exceeds maximum",
+			failure: true,
+			setup: &Setup{
+				Driver:             constants.DriverDocker,
+				DefaultMemoryLimit: "16g",
+				MaxMemoryLimit:     "8g",
+			},
+		},
+		{
+			name:    "docker driver with janitor",
+			failure: false,
+			setup: &Setup{
+				Driver:          constants.DriverDocker,
+				JanitorInterval: time.Minute,
+				JanitorMaxAge:   time.Hour,
+			},
+		},
+		{
+			name:    "docker driver-missing janitor max age",
+			failure: true,
+			setup: &Setup{
+				Driver:          constants.DriverDocker,
+				JanitorInterval: time.Minute,
+			},
+		},
+		{
+			name:    "docker driver-negative stop grace period",
+			failure: true,
+			setup: &Setup{
+				Driver:          constants.DriverDocker,
+				StopGracePeriod: -time.Second,
+			},
+		},
+		{
+			name:    "kubernetes driver-negative start timeout",
+			failure: true,
+			setup: &Setup{
+				Driver:       constants.DriverKubernetes,
+				Namespace:    "docker",
+				StartTimeout: -time.Second,
+			},
+		},
 		{
 			name:    "empty driver",
 			failure: true,
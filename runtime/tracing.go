@@ -0,0 +1,268 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"context"
+	"io"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/go-vela/server/compiler/types/pipeline"
+	"github.com/go-vela/worker/internal/exit"
+	"github.com/go-vela/worker/internal/infra"
+	"github.com/go-vela/worker/internal/tracing"
+)
+
+// traced represents a Vela engine that creates
+// a span for every call to the runtime.
+type traced struct {
+	Engine
+}
+
+// WithTracing returns a Vela engine that creates a span
+// for every call to the provided runtime engine.
+func WithTracing(e Engine) Engine {
+	return &traced{Engine: e}
+}
+
+// start is a helper function to create the span
+// for the call to the runtime with the attributes
+// for the pipeline build or container.
+func (t *traced) start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, attribute.String("vela.runtime", t.Driver()))
+
+	return tracing.Start(ctx, "runtime."+name, attrs...)
+}
+
+// end is a helper function to end the span for the call to the
+// runtime and mark the failures to reach the runtime over the
+// network as failures of the infrastructure.
+func end(span trace.Span, err *error) {
+	// https://pkg.go.dev/github.com/go-vela/worker/internal/infra#Runtime
+	*err = infra.Runtime(*err)
+
+	tracing.End(span, *err)
+}
+
+// buildAttrs is a helper function to return the span attributes for the pipeline build.
+func buildAttrs(b *pipeline.Build) []attribute.KeyValue {
+	return []attribute.KeyValue{attribute.String("vela.pipeline.id", b.ID)}
+}
+
+// containerAttrs is a helper function to return the span attributes for the pipeline container.
+func containerAttrs(ctn *pipeline.Container) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("vela.container.id", ctn.ID),
+		attribute.String("vela.container.name", ctn.Name),
+		attribute.String("vela.container.image", ctn.Image),
+	}
+}
+
+// Ping creates a span for verifying the runtime environment is reachable.
+func (t *traced) Ping(ctx context.Context) (err error) {
+	ctx, span := t.start(ctx, "Ping")
+	defer end(span, &err)
+
+	return t.Engine.Ping(ctx)
+}
+
+// InspectBuild creates a span for displaying details about the pipeline build.
+func (t *traced) InspectBuild(ctx context.Context, b *pipeline.Build) (_ []byte, err error) {
+	ctx, span := t.start(ctx, "InspectBuild", buildAttrs(b)...)
+	defer end(span, &err)
+
+	return t.Engine.InspectBuild(ctx, b)
+}
+
+// SetupBuild creates a span for preparing the pipeline build.
+func (t *traced) SetupBuild(ctx context.Context, b *pipeline.Build) (err error) {
+	ctx, span := t.start(ctx, "SetupBuild", buildAttrs(b)...)
+	defer end(span, &err)
+
+	return t.Engine.SetupBuild(ctx, b)
+}
+
+// StreamBuild creates a span for streaming the pipeline build.
+func (t *traced) StreamBuild(ctx context.Context, b *pipeline.Build) (err error) {
+	ctx, span := t.start(ctx, "StreamBuild", buildAttrs(b)...)
+	defer end(span, &err)
+
+	return t.Engine.StreamBuild(ctx, b)
+}
+
+// AssembleBuild creates a span for finalizing the pipeline build setup.
+func (t *traced) AssembleBuild(ctx context.Context, b *pipeline.Build) (err error) {
+	ctx, span := t.start(ctx, "AssembleBuild", buildAttrs(b)...)
+	defer end(span, &err)
+
+	return t.Engine.AssembleBuild(ctx, b)
+}
+
+// RemoveBuild creates a span for deleting the pipeline build metadata.
+func (t *traced) RemoveBuild(ctx context.Context, b *pipeline.Build) (err error) {
+	ctx, span := t.start(ctx, "RemoveBuild", buildAttrs(b)...)
+	defer end(span, &err)
+
+	return t.Engine.RemoveBuild(ctx, b)
+}
+
+// ReconcileBuild creates a span for deleting the resources
+// left behind for the pipeline build by a previous worker process.
+func (t *traced) ReconcileBuild(ctx context.Context, b *pipeline.Build) (err error) {
+	ctx, span := t.start(ctx, "ReconcileBuild", buildAttrs(b)...)
+	defer end(span, &err)
+
+	return t.Engine.ReconcileBuild(ctx, b)
+}
+
+// InspectContainer creates a span for inspecting the pipeline container.
+func (t *traced) InspectContainer(ctx context.Context, ctn *pipeline.Container) (err error) {
+	ctx, span := t.start(ctx, "InspectContainer", containerAttrs(ctn)...)
+	defer end(span, &err)
+
+	return t.Engine.InspectContainer(ctx, ctn)
+}
+
+// ExitReasonContainer creates a span for capturing why
+// the pipeline container exited.
+func (t *traced) ExitReasonContainer(ctx context.Context, ctn *pipeline.Container) (_ *exit.Reason, err error) {
+	ctx, span := t.start(ctx, "ExitReasonContainer", containerAttrs(ctn)...)
+	defer end(span, &err)
+
+	return t.Engine.ExitReasonContainer(ctx, ctn)
+}
+
+// PollOutputsContainer creates a span for capturing
+// file contents from the outputs container.
+func (t *traced) PollOutputsContainer(ctx context.Context, ctn *pipeline.Container, path string) (_ []byte, err error) {
+	ctx, span := t.start(ctx, "PollOutputsContainer", containerAttrs(ctn)...)
+	defer end(span, &err)
+
+	return t.Engine.PollOutputsContainer(ctx, ctn, path)
+}
+
+// RemoveContainer creates a span for deleting the pipeline container.
+func (t *traced) RemoveContainer(ctx context.Context, ctn *pipeline.Container) (err error) {
+	ctx, span := t.start(ctx, "RemoveContainer", containerAttrs(ctn)...)
+	defer end(span, &err)
+
+	return t.Engine.RemoveContainer(ctx, ctn)
+}
+
+// RunContainer creates a span for creating and starting the pipeline container.
+func (t *traced) RunContainer(ctx context.Context, ctn *pipeline.Container, b *pipeline.Build) (err error) {
+	ctx, span := t.start(ctx, "RunContainer", containerAttrs(ctn)...)
+	defer end(span, &err)
+
+	return t.Engine.RunContainer(ctx, ctn, b)
+}
+
+// SetupContainer creates a span for preparing the image for the pipeline container.
+func (t *traced) SetupContainer(ctx context.Context, ctn *pipeline.Container) (err error) {
+	ctx, span := t.start(ctx, "SetupContainer", containerAttrs(ctn)...)
+	defer end(span, &err)
+
+	return t.Engine.SetupContainer(ctx, ctn)
+}
+
+// TailContainer creates a span for capturing the logs on the pipeline container.
+//
+// The span only covers opening the logs since they are
+// read by the caller for as long as the container runs.
+func (t *traced) TailContainer(ctx context.Context, ctn *pipeline.Container) (_ io.ReadCloser, err error) {
+	ctx, span := t.start(ctx, "TailContainer", containerAttrs(ctn)...)
+	defer end(span, &err)
+
+	return t.Engine.TailContainer(ctx, ctn)
+}
+
+// WaitContainer creates a span for blocking until the pipeline container completes.
+func (t *traced) WaitContainer(ctx context.Context, ctn *pipeline.Container) (err error) {
+	ctx, span := t.start(ctx, "WaitContainer", containerAttrs(ctn)...)
+	defer end(span, &err)
+
+	return t.Engine.WaitContainer(ctx, ctn)
+}
+
+// CreateImage creates a span for creating the pipeline container image.
+func (t *traced) CreateImage(ctx context.Context, ctn *pipeline.Container) (err error) {
+	ctx, span := t.start(ctx, "CreateImage", containerAttrs(ctn)...)
+	defer end(span, &err)
+
+	return t.Engine.CreateImage(ctx, ctn)
+}
+
+// InspectImage creates a span for inspecting the pipeline container image.
+func (t *traced) InspectImage(ctx context.Context, ctn *pipeline.Container) (_ []byte, err error) {
+	ctx, span := t.start(ctx, "InspectImage", containerAttrs(ctn)...)
+	defer end(span, &err)
+
+	return t.Engine.InspectImage(ctx, ctn)
+}
+
+// CreateNetwork creates a span for creating the pipeline network.
+func (t *traced) CreateNetwork(ctx context.Context, b *pipeline.Build) (err error) {
+	ctx, span := t.start(ctx, "CreateNetwork", buildAttrs(b)...)
+	defer end(span, &err)
+
+	return t.Engine.CreateNetwork(ctx, b)
+}
+
+// InspectNetwork creates a span for inspecting the pipeline network.
+func (t *traced) InspectNetwork(ctx context.Context, b *pipeline.Build) (_ []byte, err error) {
+	ctx, span := t.start(ctx, "InspectNetwork", buildAttrs(b)...)
+	defer end(span, &err)
+
+	return t.Engine.InspectNetwork(ctx, b)
+}
+
+// RemoveNetwork creates a span for deleting the pipeline network.
+func (t *traced) RemoveNetwork(ctx context.Context, b *pipeline.Build) (err error) {
+	ctx, span := t.start(ctx, "RemoveNetwork", buildAttrs(b)...)
+	defer end(span, &err)
+
+	return t.Engine.RemoveNetwork(ctx, b)
+}
+
+// CreateVolume creates a span for creating the pipeline volume.
+func (t *traced) CreateVolume(ctx context.Context, b *pipeline.Build) (err error) {
+	ctx, span := t.start(ctx, "CreateVolume", buildAttrs(b)...)
+	defer end(span, &err)
+
+	return t.Engine.CreateVolume(ctx, b)
+}
+
+// InspectVolume creates a span for inspecting the pipeline volume.
+func (t *traced) InspectVolume(ctx context.Context, b *pipeline.Build) (_ []byte, err error) {
+	ctx, span := t.start(ctx, "InspectVolume", buildAttrs(b)...)
+	defer end(span, &err)
+
+	return t.Engine.InspectVolume(ctx, b)
+}
+
+// RemoveVolume creates a span for deleting the pipeline volume.
+func (t *traced) RemoveVolume(ctx context.Context, b *pipeline.Build) (err error) {
+	ctx, span := t.start(ctx, "RemoveVolume", buildAttrs(b)...)
+	defer end(span, &err)
+
+	return t.Engine.RemoveVolume(ctx, b)
+}
+
+// PollFileNames creates a span for capturing the artifacts from the pipeline container.
+func (t *traced) PollFileNames(ctx context.Context, ctn *pipeline.Container, step *pipeline.Container) (_ []string, err error) {
+	ctx, span := t.start(ctx, "PollFileNames", containerAttrs(ctn)...)
+	defer end(span, &err)
+
+	return t.Engine.PollFileNames(ctx, ctn, step)
+}
+
+// PollFileContent creates a span for capturing the content
+// and size of a file from the pipeline container.
+func (t *traced) PollFileContent(ctx context.Context, ctn *pipeline.Container, path string) (_ io.Reader, _ int64, err error) {
+	ctx, span := t.start(ctx, "PollFileContent", containerAttrs(ctn)...)
+	defer end(span, &err)
+
+	return t.Engine.PollFileContent(ctx, ctn, path)
+}
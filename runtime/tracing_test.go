@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/go-vela/server/compiler/types/pipeline"
+	"github.com/go-vela/server/constants"
+)
+
+func TestRuntime_WithTracing(t *testing.T) {
+	// setup types
+	recorder := tracetest.NewSpanRecorder()
+
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(sdktrace.NewTracerProvider()) })
+
+	_engine, err := New(&Setup{
+		Driver: constants.DriverDocker,
+		Mock:   true,
+	})
+	if err != nil {
+		t.Fatalf("unable to create runtime engine: %v", err)
+	}
+
+	_pipeline := &pipeline.Build{
+		ID:      "github_octocat_1",
+		Version: "1",
+	}
+
+	_engine = WithTracing(_engine)
+
+	// run test
+	err = _engine.CreateNetwork(context.Background(), _pipeline)
+	if err != nil {
+		t.Errorf("CreateNetwork returned err: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("WithTracing ended %d spans, want 1", len(spans))
+	}
+
+	if spans[0].Name() != "runtime.CreateNetwork" {
+		t.Errorf("WithTracing span is %s, want %s", spans[0].Name(), "runtime.CreateNetwork")
+	}
+
+	if _engine.Driver() != constants.DriverDocker {
+		t.Errorf("Driver is %s, want %s", _engine.Driver(), constants.DriverDocker)
+	}
+}